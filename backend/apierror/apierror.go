@@ -0,0 +1,242 @@
+// Package apierror defines the body of every error response. Clients branch
+// on Code, which never changes once released; Message is for people and may
+// be reworded at any time.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Error is the JSON body of an error response
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Details lists the invalid fields of a VALIDATION_FAILED error
+	Details []FieldError `json:"details,omitempty"`
+	// RequestID matches the X-Request-ID header and the server logs
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// FieldError describes one invalid request field
+type FieldError struct {
+	// Field is the name the client sent, e.g. amount_ml
+	Field string `json:"field"`
+	// Rule is the check that failed, e.g. required, gt, oneof, type
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Codes in use. Add new ones rather than changing the meaning of these.
+const (
+	// any route
+	ValidationFailed   = "VALIDATION_FAILED"
+	MalformedJSON      = "MALFORMED_JSON"
+	NotFound           = "NOT_FOUND"
+	MethodNotAllowed   = "METHOD_NOT_ALLOWED"
+	RateLimited        = "RATE_LIMITED"
+	InternalError      = "INTERNAL_ERROR"
+	ServiceUnavailable = "SERVICE_UNAVAILABLE"
+
+	// authentication and authorization
+	AuthRequired       = "AUTH_REQUIRED"
+	InvalidToken       = "INVALID_TOKEN"
+	TokenRevoked       = "TOKEN_REVOKED"
+	Forbidden          = "FORBIDDEN"
+	SessionRequired    = "SESSION_REQUIRED"
+	ScopeMissing       = "SCOPE_MISSING"
+	InvalidCredentials = "INVALID_CREDENTIALS"
+	IncorrectPassword  = "INCORRECT_PASSWORD"
+	LoginLocked        = "LOGIN_LOCKED"
+
+	// sessions and passwords
+	InvalidRefreshToken = "INVALID_REFRESH_TOKEN"
+	RefreshTokenRevoked = "REFRESH_TOKEN_REVOKED"
+	RefreshTokenReused  = "REFRESH_TOKEN_REUSED"
+	RefreshTokenExpired = "REFRESH_TOKEN_EXPIRED"
+	InvalidResetToken   = "INVALID_RESET_TOKEN"
+
+	// two-factor authentication
+	InvalidMFAChallenge     = "INVALID_MFA_CHALLENGE"
+	InvalidMFACode          = "INVALID_MFA_CODE"
+	TwoFactorNotSetUp       = "TWO_FACTOR_NOT_SET_UP"
+	TwoFactorAlreadyEnabled = "TWO_FACTOR_ALREADY_ENABLED"
+	TwoFactorNotEnabled     = "TWO_FACTOR_NOT_ENABLED"
+
+	// accounts
+	UsernameTaken           = "USERNAME_TAKEN"
+	EmailTaken              = "EMAIL_TAKEN"
+	UserNotFound            = "USER_NOT_FOUND"
+	AccountDisabled         = "ACCOUNT_DISABLED"
+	AccountPendingDeletion  = "ACCOUNT_PENDING_DELETION"
+	AccountNotPendingDelete = "ACCOUNT_NOT_PENDING_DELETION"
+	PasswordResetRequired   = "PASSWORD_RESET_REQUIRED"
+	CannotTargetSelf        = "CANNOT_TARGET_SELF"
+	TokenNotFound           = "TOKEN_NOT_FOUND"
+	TokenLimitReached       = "TOKEN_LIMIT_REACHED"
+
+	// health data
+	ProfileNotFound   = "PROFILE_NOT_FOUND"
+	WaterLogNotFound  = "WATER_LOG_NOT_FOUND"
+	WeightLogNotFound = "WEIGHT_LOG_NOT_FOUND"
+	BeverageNotFound  = "BEVERAGE_NOT_FOUND"
+	BeverageNameTaken = "BEVERAGE_NAME_TAKEN"
+	ReminderNotFound  = "REMINDER_NOT_FOUND"
+
+	// notifications
+	NotificationNotFound = "NOTIFICATION_NOT_FOUND"
+)
+
+// requestIDHeader is set on every response by middleware.RequestID before
+// the handlers run
+const requestIDHeader = "X-Request-ID"
+
+// Respond writes an error with the request ID. The handler must return
+// afterwards.
+func Respond(c *gin.Context, status int, code, message string) {
+	Write(c, status, &Error{Code: code, Message: message})
+}
+
+// Abort is Respond for middleware: the rest of the chain is skipped
+func Abort(c *gin.Context, status int, code, message string) {
+	Respond(c, status, code, message)
+	c.Abort()
+}
+
+// Write fills in the request ID of e and writes it
+func Write(c *gin.Context, status int, e *Error) {
+	e.RequestID = c.Writer.Header().Get(requestIDHeader)
+	c.JSON(status, e)
+}
+
+// Invalid rejects one field that failed a check made by the handler itself
+// rather than by its binding tags
+func Invalid(c *gin.Context, field, rule, message string) {
+	Write(c, http.StatusBadRequest, &Error{
+		Code:    ValidationFailed,
+		Message: message,
+		Details: []FieldError{{Field: field, Rule: rule, Message: message}},
+	})
+}
+
+// BadRequest answers a failed ShouldBindJSON or ShouldBindQuery. Validator
+// messages and decoder errors name Go types and are never sent as they are.
+func BadRequest(c *gin.Context, err error) {
+	Write(c, http.StatusBadRequest, FromBinding(err))
+}
+
+// FromBinding describes an error returned by gin's binding
+func FromBinding(err error) *Error {
+	var validation validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+
+	switch {
+	case errors.As(err, &validation):
+		details := make([]FieldError, len(validation))
+		messages := make([]string, len(validation))
+		for i, fe := range validation {
+			details[i] = FieldError{Field: fieldPath(fe), Rule: fe.Tag(), Message: ruleMessage(fe)}
+			messages[i] = details[i].Message
+		}
+		return &Error{Code: ValidationFailed, Message: strings.Join(messages, "; "), Details: details}
+
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		message := fmt.Sprintf("%s must be %s", typeErr.Field, jsonKind(typeErr.Type))
+		return &Error{
+			Code:    ValidationFailed,
+			Message: message,
+			Details: []FieldError{{Field: typeErr.Field, Rule: "type", Message: message}},
+		}
+
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return &Error{Code: MalformedJSON, Message: "Request body must be a JSON object."}
+	}
+	// e.g. a timestamp that is not RFC 3339
+	return &Error{Code: ValidationFailed, Message: "Request has a value in the wrong format."}
+}
+
+func init() {
+	// report fields by their JSON names, the ones the client knows
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				name, _, _ = strings.Cut(field.Tag.Get("form"), ",")
+			}
+			return name
+		})
+	}
+}
+
+// fieldPath is the field's JSON path without the top-level struct name,
+// e.g. items[0].name
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found || path == "" {
+		return fe.Field()
+	}
+	return path
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	field := fieldPath(fe)
+	isString := fe.Kind() == reflect.String
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be an email address"
+	case "oneof":
+		return field + " must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "gt":
+		return field + " must be greater than " + fe.Param()
+	case "gte":
+		return field + " must be at least " + fe.Param()
+	case "lt":
+		return field + " must be less than " + fe.Param()
+	case "lte":
+		return field + " must be at most " + fe.Param()
+	case "min":
+		if isString {
+			return field + " must be at least " + fe.Param() + " characters"
+		}
+		return field + " must be at least " + fe.Param()
+	case "max":
+		if isString {
+			return field + " must be at most " + fe.Param() + " characters"
+		}
+		return field + " must be at most " + fe.Param()
+	}
+	return field + " is invalid"
+}
+
+// jsonKind names a Go type the way a JSON client thinks of it
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return "an object"
+}
@@ -0,0 +1,86 @@
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type bindTarget struct {
+	Name  string  `json:"name" binding:"required,max=5"`
+	Units string  `json:"units" binding:"omitempty,oneof=metric imperial"`
+	Ratio float64 `json:"ratio" binding:"gt=0"`
+}
+
+func bind(t *testing.T, body string) *Error {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	var target bindTarget
+	err := c.ShouldBindJSON(&target)
+	if err == nil {
+		t.Fatalf("Expected %s to fail binding", body)
+	}
+	return FromBinding(err)
+}
+
+func TestFromBinding_ValidationErrors(t *testing.T) {
+	e := bind(t, `{"name": "too long", "units": "stone", "ratio": 0}`)
+	if e.Code != ValidationFailed {
+		t.Fatalf("Expected %s, got %s", ValidationFailed, e.Code)
+	}
+
+	got := make(map[string]string)
+	for _, detail := range e.Details {
+		got[detail.Field] = detail.Rule
+	}
+	want := map[string]string{"name": "max", "units": "oneof", "ratio": "gt"}
+	for field, rule := range want {
+		if got[field] != rule {
+			t.Errorf("Expected %s to fail %s, got %v", field, rule, e.Details)
+		}
+	}
+	if !strings.Contains(e.Message, "units must be one of: metric, imperial") {
+		t.Errorf("Expected a readable message, got %q", e.Message)
+	}
+}
+
+func TestFromBinding_WrongType(t *testing.T) {
+	e := bind(t, `{"name": "ok", "ratio": "high"}`)
+	if e.Code != ValidationFailed || len(e.Details) != 1 || e.Details[0].Field != "ratio" || e.Details[0].Rule != "type" {
+		t.Errorf("Expected a type error on ratio, got %+v", e)
+	}
+}
+
+func TestFromBinding_MalformedJSON(t *testing.T) {
+	for _, body := range []string{`{"name":`, ``, `not json`} {
+		if e := bind(t, body); e.Code != MalformedJSON {
+			t.Errorf("Expected %s for %q, got %+v", MalformedJSON, body, e)
+		}
+	}
+}
+
+func TestRespond_IncludesRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Header("X-Request-ID", "req-1")
+
+	Abort(c, http.StatusNotFound, ProfileNotFound, "Profile not found")
+
+	if !c.IsAborted() {
+		t.Error("Expected Abort to stop the chain")
+	}
+	var body Error
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if body.Code != ProfileNotFound || body.Message != "Profile not found" || body.RequestID != "req-1" {
+		t.Errorf("Unexpected body: %+v", body)
+	}
+}
@@ -0,0 +1,346 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, name, contents string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestDefault_IsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Errorf("Expected default config to be valid, got %v", err)
+	}
+}
+
+func TestLoad_YAMLFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  addr: ":9090"
+database:
+  path: /tmp/test.db
+cors:
+  allow_origins: ["https://app.example.com"]
+  max_age: 30m
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.Server.Addr != ":9090" {
+		t.Errorf("Expected addr :9090, got %s", cfg.Server.Addr)
+	}
+	if cfg.Database.Path != "/tmp/test.db" {
+		t.Errorf("Expected database path /tmp/test.db, got %s", cfg.Database.Path)
+	}
+	if len(cfg.CORS.AllowOrigins) != 1 || cfg.CORS.AllowOrigins[0] != "https://app.example.com" {
+		t.Errorf("Unexpected origins %v", cfg.CORS.AllowOrigins)
+	}
+	if cfg.CORS.MaxAge.Std() != 30*time.Minute {
+		t.Errorf("Expected max age 30m, got %v", cfg.CORS.MaxAge.Std())
+	}
+	// unset values keep their defaults
+	if len(cfg.Server.TrustedProxies) != 2 {
+		t.Errorf("Expected default trusted proxies, got %v", cfg.Server.TrustedProxies)
+	}
+}
+
+func TestLoad_TOMLFile(t *testing.T) {
+	path := writeConfigFile(t, "config.toml", `
+environment = "development"
+
+[server]
+addr = ":7070"
+trusted_proxies = ["10.0.0.1"]
+
+[cors]
+max_age = "1h"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.Server.Addr != ":7070" {
+		t.Errorf("Expected addr :7070, got %s", cfg.Server.Addr)
+	}
+	if len(cfg.Server.TrustedProxies) != 1 || cfg.Server.TrustedProxies[0] != "10.0.0.1" {
+		t.Errorf("Unexpected trusted proxies %v", cfg.Server.TrustedProxies)
+	}
+	if cfg.CORS.MaxAge.Std() != time.Hour {
+		t.Errorf("Expected max age 1h, got %v", cfg.CORS.MaxAge.Std())
+	}
+}
+
+func TestLoad_UnsupportedExtension(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{}`)
+
+	if _, err := Load(path); err == nil {
+		t.Error("Expected error for unsupported config file type")
+	}
+}
+
+func TestLoad_EnvOverridesFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  addr: ":9090"
+`)
+	t.Setenv("SERVER_ADDR", ":6060")
+	t.Setenv("CORS_ALLOW_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("DB_PATH", "env.db")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.Server.Addr != ":6060" {
+		t.Errorf("Expected env addr :6060, got %s", cfg.Server.Addr)
+	}
+	if len(cfg.CORS.AllowOrigins) != 2 || cfg.CORS.AllowOrigins[1] != "https://b.example.com" {
+		t.Errorf("Unexpected origins %v", cfg.CORS.AllowOrigins)
+	}
+	if cfg.Database.Path != "env.db" {
+		t.Errorf("Expected env database path, got %s", cfg.Database.Path)
+	}
+}
+
+func TestLoad_InvalidEnvDuration(t *testing.T) {
+	t.Setenv("CORS_MAX_AGE", "forever")
+
+	if _, err := Load(""); err == nil {
+		t.Error("Expected error for invalid CORS_MAX_AGE")
+	}
+}
+
+func TestValidate_ProductionRequiresRealSecret(t *testing.T) {
+	cfg := Default()
+	cfg.Environment = EnvProduction
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "development default") {
+		t.Errorf("Expected development secret to be rejected in production, got %v", err)
+	}
+
+	cfg.JWT.Secret = "too-short"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected short secret to be rejected in production")
+	}
+
+	cfg.JWT.Secret = strings.Repeat("x", 32)
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected valid production config, got %v", err)
+	}
+}
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	cfg := Default()
+	cfg.Environment = "staging"
+	cfg.Server.Addr = ""
+	cfg.Database.Path = ""
+	cfg.CORS.AllowOrigins = []string{"*"}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+
+	for _, want := range []string{"environment", "server.addr", "database.path", "cors.allow_origins"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %s, got %v", want, err)
+		}
+	}
+}
+
+func TestLoad_ServerTimeoutsFromEnv(t *testing.T) {
+	t.Setenv("SERVER_WRITE_TIMEOUT", "1m")
+	t.Setenv("SERVER_SHUTDOWN_TIMEOUT", "45s")
+	t.Setenv("SERVER_DRAIN_DELAY", "10s")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.Server.DrainDelay.Std() != 10*time.Second {
+		t.Errorf("Expected a drain delay of 10s, got %s", cfg.Server.DrainDelay.Std())
+	}
+
+	if cfg.Server.WriteTimeout.Std() != time.Minute || cfg.Server.ShutdownTimeout.Std() != 45*time.Second {
+		t.Errorf("Unexpected server timeouts %+v", cfg.Server)
+	}
+	if cfg.Server.ReadHeaderTimeout.Std() != 5*time.Second {
+		t.Errorf("Expected the default read header timeout, got %s", cfg.Server.ReadHeaderTimeout.Std())
+	}
+
+	cfg.Server.ShutdownTimeout = 0
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "shutdown_timeout") {
+		t.Errorf("Expected a zero shutdown timeout to be rejected, got %v", err)
+	}
+
+	cfg.Server.ShutdownTimeout = Duration(time.Second)
+	cfg.Server.DrainDelay = Duration(-time.Second)
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "drain_delay") {
+		t.Errorf("Expected a negative drain delay to be rejected, got %v", err)
+	}
+}
+
+func TestLoad_PostgresFromEnv(t *testing.T) {
+	t.Setenv("DB_DRIVER", "postgres")
+	t.Setenv("DATABASE_URL", "postgres://fitness@localhost/fitness")
+	t.Setenv("DB_MAX_OPEN_CONNS", "25")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "5m")
+	t.Setenv("DB_CONN_MAX_IDLE_TIME", "90s")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.Database.Driver != DriverPostgres || cfg.Database.DSN != "postgres://fitness@localhost/fitness" {
+		t.Errorf("Unexpected database config %+v", cfg.Database)
+	}
+	if cfg.Database.MaxOpenConns != 25 || cfg.Database.ConnMaxLifetime.Std() != 5*time.Minute || cfg.Database.ConnMaxIdleTime.Std() != 90*time.Second {
+		t.Errorf("Unexpected pool settings %+v", cfg.Database)
+	}
+}
+
+func TestLoad_MetricsFromEnv(t *testing.T) {
+	t.Setenv("METRICS_TOKEN", "scrape-me")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !cfg.Metrics.Enabled || cfg.Metrics.Token != "scrape-me" {
+		t.Errorf("Unexpected metrics config %+v", cfg.Metrics)
+	}
+
+	t.Setenv("METRICS_ENABLED", "maybe")
+	if _, err := Load(""); err == nil {
+		t.Error("Expected an invalid METRICS_ENABLED to be rejected")
+	}
+}
+
+func TestLoad_RateLimitFromEnv(t *testing.T) {
+	t.Setenv("RATE_LIMIT_AUTH_REQUESTS", "5")
+	t.Setenv("RATE_LIMIT_API_PERIOD", "30s")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.RateLimit.Auth.Requests != 5 || cfg.RateLimit.API.Period.Std() != 30*time.Second {
+		t.Errorf("Unexpected rate limit config %+v", cfg.RateLimit)
+	}
+
+	cfg.RateLimit.API.Requests = 0
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "rate_limit.api") {
+		t.Errorf("Expected an empty api policy to be rejected, got %v", err)
+	}
+	cfg.RateLimit.Enabled = false
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected policies to be ignored while disabled, got %v", err)
+	}
+}
+
+func TestLoad_RemindersFromEnv(t *testing.T) {
+	t.Setenv("REMINDER_WEBHOOK_URL", "https://hooks.example.com/water")
+	t.Setenv("REMINDER_WEBHOOK_SECRET", "shh")
+	t.Setenv("REMINDER_CHECK_INTERVAL", "30s")
+	t.Setenv("REMINDER_WEBHOOK_TIMEOUT", "3s")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !cfg.Reminders.Enabled || cfg.Reminders.WebhookURL != "https://hooks.example.com/water" ||
+		cfg.Reminders.WebhookSecret != "shh" || cfg.Reminders.CheckInterval.Std() != 30*time.Second ||
+		cfg.Reminders.WebhookTimeout.Std() != 3*time.Second {
+		t.Errorf("Unexpected reminder config %+v", cfg.Reminders)
+	}
+
+	cfg.Reminders.WebhookURL = "hooks.example.com/water"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "reminders.webhook_url") {
+		t.Errorf("Expected a webhook URL without a scheme to be rejected, got %v", err)
+	}
+}
+
+func TestValidate_Database(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Driver = DriverPostgres
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "database.dsn") {
+		t.Errorf("Expected postgres without a DSN to be rejected, got %v", err)
+	}
+
+	cfg.Database.Driver = "mysql"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "database.driver") {
+		t.Errorf("Expected unknown driver to be rejected, got %v", err)
+	}
+
+	cfg = Default()
+	cfg.Database.MaxOpenConns = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected negative pool size to be rejected")
+	}
+}
+
+func TestLoad_KeyFile(t *testing.T) {
+	keyFile := writeConfigFile(t, "keys.yaml", `
+active_key: k2
+keys:
+  - id: k2
+    algorithm: EdDSA
+    private_key_file: k2.pem
+  - id: k1
+    secret: old-secret
+`)
+	t.Setenv("JWT_KEY_FILE", keyFile)
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	keys, active := cfg.JWT.SigningKeys()
+	if active != "k2" {
+		t.Errorf("Expected active key k2, got %s", active)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 keys, got %d", len(keys))
+	}
+	if keys[0].PrivateKeyFile != filepath.Join(filepath.Dir(keyFile), "k2.pem") {
+		t.Errorf("Expected key path relative to key file, got %s", keys[0].PrivateKeyFile)
+	}
+}
+
+func TestValidate_JWTKeys(t *testing.T) {
+	cfg := Default()
+	cfg.JWT.ActiveKey = "missing"
+	cfg.JWT.Keys = []JWTKeyConfig{
+		{ID: "a", Algorithm: "HS512", Secret: "x"},
+		{ID: "b", Algorithm: "RS256"},
+		{ID: "b", Secret: "dup"},
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	for _, want := range []string{"unsupported algorithm", "private_key_file", "more than once", "does not match"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %q, got %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+)
+
+// Models lists every table the backend owns. The schema itself comes from
+// package migrations; tests check that it matches these models.
+func Models() []interface{} {
+	all := append([]interface{}{&models.User{}}, UserOwnedModels()...)
+	return append(all, &models.RevokedToken{}, &models.UserRevocation{}, &models.LockoutEvent{})
+}
+
+// UserOwnedModels lists every table with a user_id column that account
+// deletion clears, so new per-user tables must be added here. Revocations
+// are left out: they must outlive the user to keep already-issued access
+// tokens refused, and are pruned once those have expired.
+func UserOwnedModels() []interface{} {
+	return []interface{}{
+		&models.HealthProfile{},
+		&models.WaterIntake{},
+		&models.WaterIntakeEdit{},
+		&models.WeightLog{},
+		&models.ExerciseLog{},
+		&models.HydrationGoal{},
+		&models.Beverage{},
+		&models.ReminderRule{},
+		&models.Notification{},
+		&models.RefreshToken{},
+		&models.PasswordResetToken{},
+		&models.TwoFactor{},
+		&models.RecoveryCode{},
+		&models.PersonalAccessToken{},
+	}
+}
+
+// DeleteUser removes a user and all of their data in one transaction
+func DeleteUser(db *gorm.DB, userID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range UserOwnedModels() {
+			if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.User{}, userID).Error
+	})
+}
+
+// PurgeScheduledDeletions deletes accounts whose deletion was requested
+// before the cutoff, i.e. whose grace period has run out. It returns how
+// many accounts were removed.
+func PurgeScheduledDeletions(db *gorm.DB, cutoff time.Time) (int, error) {
+	var ids []uint
+	if err := db.Model(&models.User{}).
+		Where("deletion_requested_at IS NOT NULL AND deletion_requested_at <= ?", cutoff).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	for i, id := range ids {
+		if err := DeleteUser(db, id); err != nil {
+			return i, err
+		}
+	}
+	return len(ids), nil
+}
+
+// SetRole changes a user's role by username, e.g. to create the first admin
+func SetRole(db *gorm.DB, username, role string) error {
+	if !models.ValidRole(role) {
+		return fmt.Errorf("unknown role %q", role)
+	}
+	result := db.Model(&models.User{}).Where("username = ?", username).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no user named %q", username)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+)
+
+func setupAccountTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	if err := db.AutoMigrate(Models()...); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+func TestPurgeScheduledDeletions(t *testing.T) {
+	db := setupAccountTestDB(t)
+	now := time.Now()
+	expired := now.Add(-8 * 24 * time.Hour)
+	recent := now.Add(-time.Hour)
+
+	db.Create(&models.User{ID: 1, Username: "expired", PasswordHash: "hash", DeletionRequestedAt: &expired})
+	db.Create(&models.User{ID: 2, Username: "pending", PasswordHash: "hash", DeletionRequestedAt: &recent})
+	db.Create(&models.User{ID: 3, Username: "active", PasswordHash: "hash"})
+	for id := uint(1); id <= 3; id++ {
+		db.Create(&models.WaterIntake{UserID: id, AmountML: 250, LoggedAt: now})
+		db.Create(&models.WeightLog{UserID: id, WeightKG: 70})
+	}
+
+	purged, err := PurgeScheduledDeletions(db, now.Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeScheduledDeletions returned error: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("Expected 1 account purged, got %d", purged)
+	}
+
+	var ids []uint
+	db.Model(&models.User{}).Order("id").Pluck("id", &ids)
+	if len(ids) != 2 || ids[0] != 2 || ids[1] != 3 {
+		t.Errorf("Expected users 2 and 3 to remain, got %v", ids)
+	}
+
+	var orphans int64
+	db.Model(&models.WaterIntake{}).Where("user_id = ?", 1).Count(&orphans)
+	if orphans != 0 {
+		t.Errorf("Expected purged user's water logs to be deleted, got %d", orphans)
+	}
+}
+
+func TestDeleteUser_KeepsRevocations(t *testing.T) {
+	db := setupAccountTestDB(t)
+	revocations := NewRevocationStore(db)
+	now := time.Now()
+	db.Create(&models.User{ID: 1, Username: "leaving", PasswordHash: "hash"})
+
+	if err := revocations.RevokeUser(1, now); err != nil {
+		t.Fatalf("RevokeUser returned error: %v", err)
+	}
+	if err := DeleteUser(db, 1); err != nil {
+		t.Fatalf("DeleteUser returned error: %v", err)
+	}
+
+	// access tokens issued before the deletion are still refused
+	if revoked, _ := revocations.IsRevoked(claimsFor(1, "t1", now.Add(-time.Minute))); !revoked {
+		t.Error("Expected the deleted user's tokens to stay revoked")
+	}
+}
+
+func TestSetRole(t *testing.T) {
+	db := setupAccountTestDB(t)
+	db.Create(&models.User{Username: "someone", PasswordHash: "hash"})
+
+	if err := SetRole(db, "someone", models.RoleAdmin); err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+	var user models.User
+	db.Where("username = ?", "someone").First(&user)
+	if user.Role != models.RoleAdmin {
+		t.Errorf("Expected role admin, got %q", user.Role)
+	}
+
+	if err := SetRole(db, "nobody", models.RoleAdmin); err == nil {
+		t.Error("Expected an error for an unknown user")
+	}
+	if err := SetRole(db, "someone", "root"); err == nil {
+		t.Error("Expected an error for an unknown role")
+	}
+}
@@ -1,48 +1,119 @@
 package database
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
 
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
-	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database/migrations"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/metrics"
 )
 
-var DB *gorm.DB
+// Open connects to the configured database without touching the schema
+func Open(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case config.DriverSQLite:
+		dialector = sqlite.Open(cfg.Path)
+	case config.DriverPostgres:
+		dialector = postgres.Open(cfg.DSN)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.Driver)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{NowFunc: nowUTC})
+	if err != nil {
+		return nil, err
+	}
+	if err := Configure(db); err != nil {
+		return nil, err
+	}
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		return nil, err
+	}
 
-func InitDatabase() {
-	var err error
-	DB, err = gorm.Open(sqlite.Open("fitness.db"), &gorm.Config{})
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	if cfg.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 	}
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime.Std())
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime.Std())
 
-	log.Println("Database connected successfully")
+	return db, nil
 }
 
-func GetDB() *gorm.DB {
-	return DB
+// Configure installs the callbacks every connection needs whatever the
+// driver. Tests that open their own database call it too.
+func Configure(db *gorm.DB) error {
+	db.Config.NowFunc = nowUTC
+	return registerUTCCallbacks(db)
 }
 
-func Connect() {
-	var err error
-	DB, err = gorm.Open(sqlite.Open("fitness.db"), &gorm.Config{})
+// Connect opens the database and brings its schema up to date. The caller
+// owns the connection pool and must Close it.
+func Connect(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	db, err := Open(cfg)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return nil, fmt.Errorf("connect: %w", err)
 	}
 
-	err = DB.AutoMigrate(
-		&models.User{},
-		&models.HealthProfile{},
-		&models.WaterIntake{},
-		&models.WeightLog{},
-		&models.ExerciseLog{},
-	)
+	if err := Migrate(db, cfg.AutoMigrate); err != nil {
+		Close(db)
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	slog.Info("database connected and migrated", "driver", cfg.Driver)
+	return db, nil
+}
 
+// Ping checks that the database answers, for readiness probes
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatal("Failed to migrate database:", err)
+		return err
 	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Close closes the connection pool
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
 
-	log.Println("Database connected and migrated successfully")
+// Migrate brings the schema up to date. With apply off it only checks, so
+// deployments that run "migrate up" as a separate step fail fast instead of
+// serving on an old schema.
+func Migrate(db *gorm.DB, apply bool) error {
+	migrator := migrations.New(db)
+
+	if !apply {
+		pending, err := migrator.Pending()
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d pending migrations, starting with %d (%s); run the migrate subcommand",
+				len(pending), pending[0].Version, pending[0].Name)
+		}
+		return nil
+	}
+
+	results, err := migrator.Up(0, false)
+	for _, result := range results {
+		slog.Info("applied migration", "version", result.Version, "name", result.Name)
+	}
+	return err
 }
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The migrations must produce every table and column the models use;
+// this fails when a model changes without a migration to go with it.
+func TestMigrate_MatchesModels(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	if err := Migrate(db, true); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	for _, model := range Models() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("Failed to parse %T: %v", model, err)
+		}
+		if !db.Migrator().HasTable(stmt.Schema.Table) {
+			t.Errorf("No migration creates table %s", stmt.Schema.Table)
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !db.Migrator().HasColumn(model, field.DBName) {
+				t.Errorf("No migration creates column %s.%s", stmt.Schema.Table, field.DBName)
+			}
+		}
+	}
+}
+
+func TestMigrate_CheckOnlyRefusesPending(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	if err := Migrate(db, false); err == nil {
+		t.Fatal("Expected an error for an unmigrated database")
+	}
+	if err := Migrate(db, true); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if err := Migrate(db, false); err != nil {
+		t.Errorf("Expected a migrated database to pass, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The schema as it was when migrations were introduced. Databases created
+// by the old AutoMigrate start-up are adopted as they are: AutoMigrate only
+// creates what is missing.
+
+type baselineUser struct {
+	ID                    uint       `gorm:"primaryKey;autoIncrement"`
+	Username              string     `gorm:"uniqueIndex;size:50;not null"`
+	Email                 *string    `gorm:"uniqueIndex;size:254"`
+	PasswordHash          string     `gorm:"not null"`
+	Role                  string     `gorm:"size:20;not null;default:user;index"`
+	CreatedAt             time.Time  `gorm:"autoCreateTime"`
+	DeletionRequestedAt   *time.Time `gorm:"index"`
+	DisabledAt            *time.Time `gorm:"index"`
+	PasswordResetRequired bool       `gorm:"not null;default:false"`
+}
+
+func (baselineUser) TableName() string { return "users" }
+
+type baselineHealthProfile struct {
+	ID             uint `gorm:"primaryKey"`
+	UserID         uint `gorm:"uniqueIndex;not null"`
+	DateOfBirth    *time.Time
+	Sex            string `gorm:"size:10"`
+	HeightCM       float64
+	WeightKG       float64
+	NeckCM         *float64
+	WaistCM        *float64
+	HipsCM         *float64
+	ActivityLevel  string `gorm:"size:20"`
+	PreferredUnits string `gorm:"size:10;default:metric"`
+	UpdatedAt      time.Time
+}
+
+func (baselineHealthProfile) TableName() string { return "health_profiles" }
+
+type baselineWaterIntake struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	AmountML  int       `gorm:"not null"`
+	LoggedAt  time.Time `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (baselineWaterIntake) TableName() string { return "water_intakes" }
+
+type baselineWeightLog struct {
+	ID       uint         `gorm:"primaryKey"`
+	UserID   uint         `gorm:"index;not null"`
+	User     baselineUser `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	WeightKG float64      `gorm:"not null"`
+	LoggedAt time.Time    `gorm:"autoCreateTime"`
+}
+
+func (baselineWeightLog) TableName() string { return "weight_logs" }
+
+type baselineExerciseLog struct {
+	ID             uint         `gorm:"primaryKey"`
+	UserID         uint         `gorm:"index;not null"`
+	User           baselineUser `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	Type           string       `gorm:"not null"`
+	Duration       int          `gorm:"not null"`
+	CaloriesBurned int          `gorm:"not null"`
+	LoggedAt       time.Time    `gorm:"autoCreateTime"`
+}
+
+func (baselineExerciseLog) TableName() string { return "exercise_logs" }
+
+type baselineRefreshToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"index;not null"`
+	FamilyID  string    `gorm:"size:32;index;not null"`
+	TokenHash string    `gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (baselineRefreshToken) TableName() string { return "refresh_tokens" }
+
+type baselineRevokedToken struct {
+	JTI       string    `gorm:"primaryKey;size:32"`
+	UserID    uint      `gorm:"index;not null"`
+	ExpiresAt time.Time `gorm:"index;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (baselineRevokedToken) TableName() string { return "revoked_tokens" }
+
+type baselineUserRevocation struct {
+	UserID        uint      `gorm:"primaryKey;autoIncrement:false"`
+	RevokedBefore time.Time `gorm:"not null"`
+	ExpiresAt     time.Time `gorm:"index;not null"`
+}
+
+func (baselineUserRevocation) TableName() string { return "user_revocations" }
+
+type baselinePasswordResetToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"index;not null"`
+	TokenHash string    `gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (baselinePasswordResetToken) TableName() string { return "password_reset_tokens" }
+
+type baselineLockoutEvent struct {
+	ID          uint      `gorm:"primaryKey"`
+	Scope       string    `gorm:"size:10;not null"`
+	Username    string    `gorm:"size:50;index"`
+	IP          string    `gorm:"size:45;index"`
+	Failures    int       `gorm:"not null"`
+	LockedUntil time.Time `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;index"`
+}
+
+func (baselineLockoutEvent) TableName() string { return "lockout_events" }
+
+type baselineTwoFactor struct {
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false"`
+	Secret    string    `gorm:"size:64;not null"`
+	Enabled   bool      `gorm:"not null;default:false"`
+	LastStep  int64     `gorm:"not null;default:0"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (baselineTwoFactor) TableName() string { return "two_factors" }
+
+type baselineRecoveryCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index;not null"`
+	CodeHash  string `gorm:"size:64;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (baselineRecoveryCode) TableName() string { return "recovery_codes" }
+
+type baselinePersonalAccessToken struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"index;not null"`
+	Name       string `gorm:"size:100;not null"`
+	TokenHash  string `gorm:"size:64;uniqueIndex;not null"`
+	Hint       string `gorm:"size:8;not null"`
+	Scopes     string `gorm:"size:255;not null"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+func (baselinePersonalAccessToken) TableName() string { return "personal_access_tokens" }
+
+// baselineTables in creation order; users first for the foreign keys
+var baselineTables = []interface{}{
+	&baselineUser{},
+	&baselineHealthProfile{},
+	&baselineWaterIntake{},
+	&baselineWeightLog{},
+	&baselineExerciseLog{},
+	&baselineRefreshToken{},
+	&baselineRevokedToken{},
+	&baselineUserRevocation{},
+	&baselinePasswordResetToken{},
+	&baselineLockoutEvent{},
+	&baselineTwoFactor{},
+	&baselineRecoveryCode{},
+	&baselinePersonalAccessToken{},
+}
+
+func init() {
+	register(Migration{
+		Version: 1,
+		Name:    "baseline",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(baselineTables...)
+		},
+		Down: func(tx *gorm.DB) error {
+			for i := len(baselineTables) - 1; i >= 0; i-- {
+				if err := tx.Migrator().DropTable(baselineTables[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// The log endpoints all list one user's entries by date; the single-column
+// user_id indexes from the baseline leave the date filter and sort to a
+// scan of every entry the user ever logged.
+
+var logIndexes = []struct {
+	name  string
+	table string
+}{
+	{"idx_water_intakes_user_logged_at", "water_intakes"},
+	{"idx_weight_logs_user_logged_at", "weight_logs"},
+	{"idx_exercise_logs_user_logged_at", "exercise_logs"},
+}
+
+func init() {
+	register(Migration{
+		Version: 2,
+		Name:    "log_user_date_indexes",
+		Up: func(tx *gorm.DB) error {
+			for _, index := range logIndexes {
+				if err := tx.Exec("CREATE INDEX IF NOT EXISTS " + index.name + " ON " + index.table + " (user_id, logged_at)").Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, index := range logIndexes {
+				if err := tx.Exec("DROP INDEX IF EXISTS " + index.name).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
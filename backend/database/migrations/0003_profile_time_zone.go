@@ -0,0 +1,28 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Days are counted in the user's time zone. Existing profiles get the empty
+// zone, which means UTC, the only zone the API knew before.
+
+type profileTimeZone struct {
+	TimeZone string `gorm:"size:64;not null;default:''"`
+}
+
+func (profileTimeZone) TableName() string { return "health_profiles" }
+
+func init() {
+	register(Migration{
+		Version: 3,
+		Name:    "profile_time_zone",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&profileTimeZone{}, "TimeZone") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&profileTimeZone{}, "TimeZone")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&profileTimeZone{}, "TimeZone")
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Water goals are set per user with the day they take effect, replacing the
+// fixed 2000ml goal. Users without a row get the automatic goal.
+
+type hydrationGoal struct {
+	ID            uint `gorm:"primaryKey"`
+	UserID        uint `gorm:"not null;uniqueIndex:idx_hydration_goals_user_from"`
+	GoalML        *int
+	EffectiveFrom string `gorm:"size:10;not null;uniqueIndex:idx_hydration_goals_user_from"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (hydrationGoal) TableName() string { return "hydration_goals" }
+
+func init() {
+	register(Migration{
+		Version: 4,
+		Name:    "hydration_goals",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&hydrationGoal{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&hydrationGoal{})
+		},
+	})
+}
@@ -0,0 +1,93 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Water logs record what was drunk. Each log keeps the hydration, caffeine
+// and energy worked out when it was logged; existing logs are plain water.
+// The built-in catalog is copied here as it was when introduced, with
+// approximate values after the beverage hydration index.
+
+type beverage struct {
+	ID                 uint    `gorm:"primaryKey"`
+	UserID             *uint   `gorm:"index"`
+	Name               string  `gorm:"size:50;not null"`
+	HydrationFactor    float64 `gorm:"not null"`
+	CaffeineMGPer100ML float64 `gorm:"column:caffeine_mg_per_100ml;not null;default:0"`
+	KcalPer100ML       float64 `gorm:"column:kcal_per_100ml;not null;default:0"`
+	CreatedAt          time.Time
+}
+
+func (beverage) TableName() string { return "beverages" }
+
+type waterIntakeBeverage struct {
+	BeverageID  *uint
+	HydrationML int     `gorm:"not null;default:0"`
+	CaffeineMG  float64 `gorm:"not null;default:0"`
+	Kcal        float64 `gorm:"not null;default:0"`
+}
+
+func (waterIntakeBeverage) TableName() string { return "water_intakes" }
+
+type profileCaffeineLimit struct {
+	CaffeineLimitMG *int
+}
+
+func (profileCaffeineLimit) TableName() string { return "health_profiles" }
+
+var builtinBeverages = []beverage{
+	{Name: "Water", HydrationFactor: 1},
+	{Name: "Sparkling water", HydrationFactor: 1},
+	{Name: "Coffee", HydrationFactor: 0.9, CaffeineMGPer100ML: 40, KcalPer100ML: 2},
+	{Name: "Espresso", HydrationFactor: 0.8, CaffeineMGPer100ML: 212, KcalPer100ML: 9},
+	{Name: "Tea", HydrationFactor: 0.95, CaffeineMGPer100ML: 20, KcalPer100ML: 1},
+	{Name: "Milk", HydrationFactor: 1.5, KcalPer100ML: 61},
+	{Name: "Orange juice", HydrationFactor: 1.1, KcalPer100ML: 45},
+	{Name: "Sports drink", HydrationFactor: 1.1, KcalPer100ML: 26},
+	{Name: "Cola", HydrationFactor: 0.9, CaffeineMGPer100ML: 10, KcalPer100ML: 42},
+	{Name: "Energy drink", HydrationFactor: 0.9, CaffeineMGPer100ML: 32, KcalPer100ML: 45},
+	{Name: "Beer", HydrationFactor: 0.8, KcalPer100ML: 43},
+	{Name: "Wine", HydrationFactor: 0.3, KcalPer100ML: 83},
+}
+
+var waterIntakeBeverageColumns = []string{"BeverageID", "HydrationML", "CaffeineMG", "Kcal"}
+
+func init() {
+	register(Migration{
+		Version: 5,
+		Name:    "beverages",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&beverage{}); err != nil {
+				return err
+			}
+			catalog := append([]beverage(nil), builtinBeverages...)
+			if err := tx.Create(&catalog).Error; err != nil {
+				return err
+			}
+
+			for _, column := range waterIntakeBeverageColumns {
+				if err := tx.Migrator().AddColumn(&waterIntakeBeverage{}, column); err != nil {
+					return err
+				}
+			}
+			if err := tx.Exec("UPDATE water_intakes SET hydration_ml = amount_ml").Error; err != nil {
+				return err
+			}
+			return tx.Migrator().AddColumn(&profileCaffeineLimit{}, "CaffeineLimitMG")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropColumn(&profileCaffeineLimit{}, "CaffeineLimitMG"); err != nil {
+				return err
+			}
+			for _, column := range waterIntakeBeverageColumns {
+				if err := tx.Migrator().DropColumn(&waterIntakeBeverage{}, column); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().DropTable(&beverage{})
+		},
+	})
+}
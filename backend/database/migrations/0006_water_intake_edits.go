@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Water logs can be edited in place. Each edit keeps the version it
+// replaced so clients can show the history.
+
+type waterIntakeEdit struct {
+	ID            uint      `gorm:"primaryKey"`
+	WaterIntakeID uint      `gorm:"not null;index"`
+	UserID        uint      `gorm:"not null;index"`
+	AmountML      int       `gorm:"not null"`
+	LoggedAt      time.Time `gorm:"not null"`
+	BeverageID    *uint
+	HydrationML   int       `gorm:"not null;default:0"`
+	CaffeineMG    float64   `gorm:"not null;default:0"`
+	Kcal          float64   `gorm:"not null;default:0"`
+	EditedAt      time.Time `gorm:"not null"`
+}
+
+func (waterIntakeEdit) TableName() string { return "water_intake_edits" }
+
+func init() {
+	register(Migration{
+		Version: 6,
+		Name:    "water_intake_edits",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&waterIntakeEdit{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&waterIntakeEdit{})
+		},
+	})
+}
@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Users set hydration reminder rules, which a background job checks and
+// delivers through the in-app inbox and an optional webhook.
+
+type reminderRule struct {
+	ID              uint   `gorm:"primaryKey"`
+	UserID          uint   `gorm:"not null;index"`
+	Kind            string `gorm:"size:20;not null"`
+	Enabled         bool   `gorm:"not null"`
+	StartTime       string `gorm:"size:5;not null"`
+	EndTime         string `gorm:"size:5;not null"`
+	IntervalMinutes int    `gorm:"not null"`
+	MarginPercent   int    `gorm:"not null;default:0"`
+	LastSentAt      *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (reminderRule) TableName() string { return "reminder_rules" }
+
+type notification struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	Kind      string `gorm:"size:30;not null"`
+	Title     string `gorm:"size:100;not null"`
+	Body      string `gorm:"size:500;not null"`
+	CreatedAt time.Time
+	ReadAt    *time.Time
+}
+
+func (notification) TableName() string { return "notifications" }
+
+func init() {
+	register(Migration{
+		Version: 7,
+		Name:    "reminders",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&reminderRule{}, &notification{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&notification{}, &reminderRule{})
+		},
+	})
+}
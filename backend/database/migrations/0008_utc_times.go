@@ -0,0 +1,77 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Times are stored in UTC. SQLite compares them as text, so rows written
+// with a local UTC offset before that sort and filter wrongly against UTC
+// bounds; they are rewritten here. PostgreSQL stores instants and needs
+// nothing.
+
+func init() {
+	register(Migration{
+		Version: 8,
+		Name:    "utc_times",
+		Up: func(tx *gorm.DB) error {
+			if tx.Dialector.Name() != "sqlite" {
+				return nil
+			}
+			tables, err := tx.Migrator().GetTables()
+			if err != nil {
+				return err
+			}
+			for _, table := range tables {
+				columns, err := tx.Migrator().ColumnTypes(table)
+				if err != nil {
+					return err
+				}
+				for _, column := range columns {
+					if !strings.EqualFold(column.DatabaseTypeName(), "datetime") {
+						continue
+					}
+					if err := utcColumn(tx, table, column.Name()); err != nil {
+						return fmt.Errorf("%s.%s: %w", table, column.Name(), err)
+					}
+				}
+			}
+			return nil
+		},
+		// the rewritten values are the same instants, so there is nothing
+		// to undo
+		Down: func(tx *gorm.DB) error {
+			return nil
+		},
+	})
+}
+
+// utcColumn rewrites the values of one column that carry a non-zero offset
+func utcColumn(tx *gorm.DB, table, column string) error {
+	var rows []struct {
+		RowID int64
+		Value time.Time
+	}
+	err := tx.Raw("SELECT rowid AS row_id, ? AS value FROM ? WHERE ? IS NOT NULL",
+		clause.Column{Name: column}, clause.Table{Name: table}, clause.Column{Name: column}).
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if _, offset := row.Value.Zone(); offset == 0 {
+			continue
+		}
+		err := tx.Exec("UPDATE ? SET ? = ? WHERE rowid = ?",
+			clause.Table{Name: table}, clause.Column{Name: column}, row.Value.UTC(), row.RowID).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+// Package migrations evolves the database schema through ordered, versioned
+// steps recorded in the schema_migrations table.
+//
+// Every change to a table goes in a new file named NNNN_description.go
+// that registers a Migration with the next version number. Migrations must
+// not use the structs in package models, which keep changing; they declare
+// the columns they need themselves or use plain SQL.
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is one step of the schema history. Up and Down run inside a
+// transaction together with the bookkeeping in schema_migrations.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration records an applied migration
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:100;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+var registry = map[int]Migration{}
+
+// register adds a migration to the history; each migration file calls it
+// from init
+func register(m Migration) {
+	if _, exists := registry[m.Version]; exists {
+		panic(fmt.Sprintf("migration version %d registered twice", m.Version))
+	}
+	registry[m.Version] = m
+}
+
+// All returns the registered migrations in version order
+func All() []Migration {
+	all := make([]Migration, 0, len(registry))
+	for _, m := range registry {
+		all = append(all, m)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all
+}
+
+// Status describes a migration and whether it has been applied
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Result is a migration that was applied or reverted, with the SQL it ran
+type Result struct {
+	Version int
+	Name    string
+	SQL     []string
+}
+
+// errDryRun rolls back the transaction of a dry run
+var errDryRun = errors.New("dry run")
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New returns a migrator for the registered migrations
+func New(db *gorm.DB) *Migrator {
+	return &Migrator{db: db, migrations: All()}
+}
+
+// applied returns the applied versions, creating schema_migrations on
+// first use
+func (m *Migrator) applied(db *gorm.DB) (map[int]SchemaMigration, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// Status lists every known migration in order
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied(m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Pending returns the migrations that have not been applied yet
+func (m *Migrator) Pending() ([]Migration, error) {
+	applied, err := m.applied(m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies pending migrations up to and including target, or all of them
+// if target is 0. Each migration commits on its own, so a failure leaves
+// the ones before it applied. A dry run applies them in one transaction,
+// records the SQL and rolls everything back.
+func (m *Migrator) Up(target int, dryRun bool) ([]Result, error) {
+	return m.run(dryRun, func(applied map[int]SchemaMigration) []step {
+		var steps []step
+		for _, migration := range m.migrations {
+			if target > 0 && migration.Version > target {
+				break
+			}
+			if _, ok := applied[migration.Version]; !ok {
+				steps = append(steps, step{migration: migration, up: true})
+			}
+		}
+		return steps
+	})
+}
+
+// Down reverts the most recently applied migrations, newest first
+func (m *Migrator) Down(count int, dryRun bool) ([]Result, error) {
+	return m.run(dryRun, func(applied map[int]SchemaMigration) []step {
+		var steps []step
+		for i := len(m.migrations) - 1; i >= 0 && len(steps) < count; i-- {
+			if _, ok := applied[m.migrations[i].Version]; ok {
+				steps = append(steps, step{migration: m.migrations[i], up: false})
+			}
+		}
+		return steps
+	})
+}
+
+type step struct {
+	migration Migration
+	up        bool
+}
+
+func (m *Migrator) run(dryRun bool, plan func(map[int]SchemaMigration) []step) ([]Result, error) {
+	applied, err := m.applied(m.db)
+	if err != nil {
+		return nil, err
+	}
+	steps := plan(applied)
+
+	var results []Result
+	if dryRun {
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			for _, s := range steps {
+				result, err := apply(tx, s)
+				if err != nil {
+					return err
+				}
+				results = append(results, result)
+			}
+			return errDryRun
+		})
+		if !errors.Is(err, errDryRun) {
+			return results, err
+		}
+		return results, nil
+	}
+
+	for _, s := range steps {
+		var result Result
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			result, err = apply(tx, s)
+			return err
+		})
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// apply runs one step and updates schema_migrations
+func apply(tx *gorm.DB, s step) (Result, error) {
+	recorder := &sqlRecorder{}
+	recorded := tx.Session(&gorm.Session{Logger: recorder})
+
+	migration := s.migration
+	result := Result{Version: migration.Version, Name: migration.Name}
+	if s.up {
+		if err := migration.Up(recorded); err != nil {
+			return result, fmt.Errorf("migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+		if err := tx.Create(&SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error; err != nil {
+			return result, err
+		}
+	} else {
+		if migration.Down == nil {
+			return result, fmt.Errorf("migration %d (%s) cannot be reverted", migration.Version, migration.Name)
+		}
+		if err := migration.Down(recorded); err != nil {
+			return result, fmt.Errorf("reverting migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+		if err := tx.Delete(&SchemaMigration{}, migration.Version).Error; err != nil {
+			return result, err
+		}
+	}
+
+	result.SQL = recorder.statements
+	return result, nil
+}
@@ -0,0 +1,179 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupMigrationTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	return db
+}
+
+func TestAll_OrderedAndUnique(t *testing.T) {
+	all := All()
+	if len(all) == 0 {
+		t.Fatal("Expected registered migrations")
+	}
+	for i, migration := range all {
+		if migration.Version != i+1 {
+			t.Errorf("Expected version %d at position %d, got %d", i+1, i, migration.Version)
+		}
+		if migration.Up == nil || migration.Down == nil {
+			t.Errorf("Migration %d must have Up and Down", migration.Version)
+		}
+	}
+}
+
+func TestMigrator_UpAndStatus(t *testing.T) {
+	db := setupMigrationTestDB(t)
+	migrator := New(db)
+
+	results, err := migrator.Up(0, false)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(results) != len(All()) {
+		t.Errorf("Expected %d migrations applied, got %d", len(All()), len(results))
+	}
+	if !db.Migrator().HasTable("users") || !db.Migrator().HasIndex("water_intakes", "idx_water_intakes_user_logged_at") {
+		t.Error("Expected the schema to be created")
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			t.Errorf("Expected migration %d to be applied", status.Version)
+		}
+	}
+
+	// running again is a no-op
+	if results, err := migrator.Up(0, false); err != nil || len(results) != 0 {
+		t.Errorf("Expected nothing to do, got %v %v", results, err)
+	}
+}
+
+func TestMigrator_UpToTargetAndDown(t *testing.T) {
+	db := setupMigrationTestDB(t)
+	migrator := New(db)
+
+	if _, err := migrator.Up(1, false); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	pending, _ := migrator.Pending()
+	if len(pending) != len(All())-1 || pending[0].Version != 2 {
+		t.Fatalf("Expected everything after version 1 to be pending, got %v", pending)
+	}
+
+	if _, err := migrator.Up(0, false); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	results, err := migrator.Down(1, false)
+	if err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	latest := All()[len(All())-1]
+	if len(results) != 1 || results[0].Version != latest.Version {
+		t.Errorf("Expected the latest migration to be reverted, got %v", results)
+	}
+	if pending, _ := migrator.Pending(); len(pending) != 1 {
+		t.Errorf("Expected one pending migration, got %d", len(pending))
+	}
+
+	if _, err := migrator.Down(len(All()), false); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if db.Migrator().HasTable("users") {
+		t.Error("Expected reverting the baseline to drop the tables")
+	}
+}
+
+func TestMigrator_DryRunChangesNothing(t *testing.T) {
+	db := setupMigrationTestDB(t)
+	migrator := New(db)
+
+	results, err := migrator.Up(0, true)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(results) != len(All()) {
+		t.Fatalf("Expected every migration in the plan, got %d", len(results))
+	}
+
+	var sql []string
+	for _, result := range results {
+		sql = append(sql, result.SQL...)
+	}
+	joined := strings.Join(sql, "\n")
+	if !strings.Contains(joined, "CREATE TABLE `users`") || !strings.Contains(joined, "idx_weight_logs_user_logged_at") {
+		t.Errorf("Expected the dry run to show the SQL, got:\n%s", joined)
+	}
+
+	if db.Migrator().HasTable("users") {
+		t.Error("Dry run must not create tables")
+	}
+	if pending, _ := migrator.Pending(); len(pending) != len(All()) {
+		t.Errorf("Dry run must not record migrations, %d pending", len(pending))
+	}
+}
+
+func TestMigrator_AdoptsAutoMigratedDatabase(t *testing.T) {
+	db := setupMigrationTestDB(t)
+
+	// a database from before migrations, with a row to keep
+	if err := db.AutoMigrate(baselineTables...); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	db.Create(&baselineUser{Username: "existing", PasswordHash: "hash"})
+
+	if _, err := New(db).Up(0, false); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	var count int64
+	db.Table("users").Count(&count)
+	if count != 1 {
+		t.Errorf("Expected existing data to survive, got %d users", count)
+	}
+}
+
+func TestMigrator_RewritesLocalTimesToUTC(t *testing.T) {
+	db := setupMigrationTestDB(t)
+	migrator := New(db)
+
+	if _, err := migrator.Up(7, false); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	// rows written with local offsets before times were stored in UTC
+	local := time.Date(2026, 3, 1, 0, 30, 0, 0, time.FixedZone("CET", 3600))
+	db.Create(&baselineUser{Username: "existing", PasswordHash: "hash"})
+	db.Exec("INSERT INTO water_intakes (user_id, amount_ml, logged_at, created_at, updated_at) VALUES (1, 250, ?, ?, ?)", local, local, local)
+
+	if _, err := migrator.Up(0, false); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	var stored string
+	db.Raw("SELECT CAST(logged_at AS TEXT) FROM water_intakes").Scan(&stored)
+	if !strings.HasPrefix(stored, "2026-02-28 23:30:00") || !strings.HasSuffix(stored, "+00:00") {
+		t.Errorf("Expected logged_at rewritten to UTC, got %q", stored)
+	}
+
+	// the UTC bound of 1 March must no longer include the row
+	var count int64
+	db.Table("water_intakes").Where("logged_at >= ?", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)).Count(&count)
+	if count != 0 {
+		t.Errorf("Expected the row to fall on 28 February in UTC, %d rows matched", count)
+	}
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// last_used_at is only written when it is older than this, so a script
+// polling the API does not turn every request into a write
+const lastUsedResolution = time.Minute
+
+// PersonalTokenStore keeps personal access tokens in the database and
+// authenticates them
+type PersonalTokenStore struct {
+	db *gorm.DB
+}
+
+func NewPersonalTokenStore(db *gorm.DB) *PersonalTokenStore {
+	return &PersonalTokenStore{db: db}
+}
+
+func (s *PersonalTokenStore) Authenticate(token string, now time.Time) (*utils.PersonalToken, error) {
+	var record models.PersonalAccessToken
+	err := s.db.Where("token_hash = ?", utils.HashToken(token)).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.ExpiresAt != nil && !now.Before(*record.ExpiresAt) {
+		return nil, nil
+	}
+
+	// tokens stop working with the account's sessions: when it is disabled,
+	// pending deletion or waiting for a forced password reset
+	var user models.User
+	if err := s.db.Select("id", "deletion_requested_at", "disabled_at", "password_reset_required").First(&user, record.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if user.DeletionRequestedAt != nil || user.DisabledAt != nil || user.PasswordResetRequired {
+		return nil, nil
+	}
+
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) >= lastUsedResolution {
+		if err := s.db.Model(&record).Update("last_used_at", now).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return &utils.PersonalToken{
+		ID:     record.ID,
+		UserID: record.UserID,
+		Scopes: strings.Fields(record.Scopes),
+	}, nil
+}
+
+func (s *PersonalTokenStore) Create(token *models.PersonalAccessToken) error {
+	return s.db.Create(token).Error
+}
+
+func (s *PersonalTokenStore) List(userID uint) ([]models.PersonalAccessToken, error) {
+	tokens := []models.PersonalAccessToken{}
+	err := s.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+func (s *PersonalTokenStore) Count(userID uint) (int64, error) {
+	var count int64
+	err := s.db.Model(&models.PersonalAccessToken{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (s *PersonalTokenStore) Delete(userID, id uint) error {
+	result := s.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.PersonalAccessToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+func createPersonalToken(t *testing.T, store *PersonalTokenStore, userID uint, scopes string, expiresAt *time.Time) string {
+	token, hash, err := utils.GeneratePersonalToken()
+	if err != nil {
+		t.Fatalf("GeneratePersonalToken: %v", err)
+	}
+	record := models.PersonalAccessToken{
+		UserID:    userID,
+		Name:      "test",
+		TokenHash: hash,
+		Hint:      token[len(token)-4:],
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if err := store.db.Create(&record).Error; err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+	return token
+}
+
+func TestPersonalTokenStore_Authenticate(t *testing.T) {
+	db := setupAccountTestDB(t)
+	store := NewPersonalTokenStore(db)
+	user := models.User{Username: "tokenuser", PasswordHash: "hash"}
+	db.Create(&user)
+
+	token := createPersonalToken(t, store, user.ID, "water:read weight:write", nil)
+	now := time.Now()
+
+	authenticated, err := store.Authenticate(token, now)
+	if err != nil || authenticated == nil {
+		t.Fatalf("Expected the token to authenticate, got %v %v", authenticated, err)
+	}
+	if authenticated.UserID != user.ID || !authenticated.Allows("weight:write") || authenticated.Allows("water:write") {
+		t.Errorf("Unexpected token %+v", authenticated)
+	}
+
+	var record models.PersonalAccessToken
+	db.First(&record, authenticated.ID)
+	if record.LastUsedAt == nil {
+		t.Error("Expected last_used_at to be set")
+	}
+
+	if unknown, err := store.Authenticate(utils.PersonalTokenPrefix+"unknown", now); err != nil || unknown != nil {
+		t.Errorf("Expected an unknown token to be refused, got %v %v", unknown, err)
+	}
+}
+
+func TestPersonalTokenStore_RefusesExpiredAndDeletedAccounts(t *testing.T) {
+	db := setupAccountTestDB(t)
+	store := NewPersonalTokenStore(db)
+	user := models.User{Username: "tokenuser", PasswordHash: "hash"}
+	db.Create(&user)
+
+	past := time.Now().Add(-time.Minute)
+	expired := createPersonalToken(t, store, user.ID, "water:read", &past)
+	if authenticated, _ := store.Authenticate(expired, time.Now()); authenticated != nil {
+		t.Error("Expected an expired token to be refused")
+	}
+
+	token := createPersonalToken(t, store, user.ID, "water:read", nil)
+	db.Model(&user).Update("deletion_requested_at", time.Now())
+	if authenticated, _ := store.Authenticate(token, time.Now()); authenticated != nil {
+		t.Error("Expected tokens of an account pending deletion to be refused")
+	}
+}
+
+func TestPersonalTokenStore_RefusesAccountsAwaitingPasswordReset(t *testing.T) {
+	db := setupAccountTestDB(t)
+	store := NewPersonalTokenStore(db)
+	user := models.User{Username: "tokenuser", PasswordHash: "hash"}
+	db.Create(&user)
+
+	token := createPersonalToken(t, store, user.ID, "water:read", nil)
+	db.Model(&user).Update("password_reset_required", true)
+	if authenticated, err := store.Authenticate(token, time.Now()); err != nil || authenticated != nil {
+		t.Errorf("Expected tokens to be refused until the password is reset, got %v %v", authenticated, err)
+	}
+
+	// the reset clears the flag and the token works again
+	db.Model(&user).Update("password_reset_required", false)
+	if authenticated, err := store.Authenticate(token, time.Now()); err != nil || authenticated == nil {
+		t.Errorf("Expected the token to work after the reset, got %v %v", authenticated, err)
+	}
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// RevocationStore keeps token revocations in the database so every server
+// instance sees them
+type RevocationStore struct {
+	db *gorm.DB
+}
+
+func NewRevocationStore(db *gorm.DB) *RevocationStore {
+	return &RevocationStore{db: db}
+}
+
+func (s *RevocationStore) RevokeToken(jti string, userID uint, expiresAt time.Time) error {
+	return s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.RevokedToken{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}).Error
+}
+
+func (s *RevocationStore) RevokeUser(userID uint, before time.Time) error {
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"revoked_before", "expires_at"}),
+	}).Create(&models.UserRevocation{
+		UserID:        userID,
+		RevokedBefore: before,
+		ExpiresAt:     before.Add(utils.AccessTokenTTL()),
+	}).Error
+}
+
+func (s *RevocationStore) IsRevoked(claims *utils.Claims) (bool, error) {
+	if claims.ID != "" {
+		var count int64
+		if err := s.db.Model(&models.RevokedToken{}).Where("jti = ?", claims.ID).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+
+	var revocation models.UserRevocation
+	err := s.db.Where("user_id = ?", claims.UserID).First(&revocation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return utils.IssuedBefore(claims, revocation.RevokedBefore), nil
+}
+
+func (s *RevocationStore) Prune(now time.Time) error {
+	if err := s.db.Where("expires_at < ?", now).Delete(&models.RevokedToken{}).Error; err != nil {
+		return err
+	}
+	return s.db.Where("expires_at < ?", now).Delete(&models.UserRevocation{}).Error
+}
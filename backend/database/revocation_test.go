@@ -0,0 +1,98 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+func setupRevocationTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&models.RevokedToken{}, &models.UserRevocation{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+func claimsFor(userID uint, jti string, issuedAt time.Time) *utils.Claims {
+	return &utils.Claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       jti,
+			IssuedAt: jwt.NewNumericDate(issuedAt),
+		},
+	}
+}
+
+func TestRevocationStore_TokenAndUser(t *testing.T) {
+	store := NewRevocationStore(setupRevocationTestDB(t))
+	now := time.Now()
+
+	if err := store.RevokeToken("abc", 1, now.Add(time.Minute)); err != nil {
+		t.Fatalf("RevokeToken returned error: %v", err)
+	}
+	// revoking twice is harmless
+	if err := store.RevokeToken("abc", 1, now.Add(time.Minute)); err != nil {
+		t.Fatalf("RevokeToken returned error on repeat: %v", err)
+	}
+
+	if revoked, _ := store.IsRevoked(claimsFor(1, "abc", now)); !revoked {
+		t.Error("Expected revoked jti to be refused")
+	}
+	if revoked, _ := store.IsRevoked(claimsFor(1, "xyz", now)); revoked {
+		t.Error("Expected unrelated jti to be accepted")
+	}
+
+	if err := store.RevokeUser(2, now); err != nil {
+		t.Fatalf("RevokeUser returned error: %v", err)
+	}
+	// a later logout-all moves the cutoff forward
+	if err := store.RevokeUser(2, now.Add(time.Hour)); err != nil {
+		t.Fatalf("RevokeUser returned error on repeat: %v", err)
+	}
+
+	if revoked, _ := store.IsRevoked(claimsFor(2, "t1", now.Add(30*time.Minute))); !revoked {
+		t.Error("Expected token issued before the latest cutoff to be refused")
+	}
+	if revoked, _ := store.IsRevoked(claimsFor(2, "t2", now.Add(2*time.Hour))); revoked {
+		t.Error("Expected token issued after the cutoff to be accepted")
+	}
+}
+
+func TestRevocationStore_Prune(t *testing.T) {
+	db := setupRevocationTestDB(t)
+	store := NewRevocationStore(db)
+	now := time.Now()
+
+	store.RevokeToken("expired", 1, now.Add(-time.Minute))
+	store.RevokeToken("live", 1, now.Add(time.Minute))
+	store.RevokeUser(2, now.Add(-utils.AccessTokenTTL()-time.Minute))
+
+	if err := store.Prune(now); err != nil {
+		t.Fatalf("Prune returned error: %v", err)
+	}
+
+	var tokens int64
+	db.Model(&models.RevokedToken{}).Count(&tokens)
+	if tokens != 1 {
+		t.Errorf("Expected 1 revoked token after prune, got %d", tokens)
+	}
+
+	var users int64
+	db.Model(&models.UserRevocation{}).Count(&users)
+	if users != 0 {
+		t.Errorf("Expected user revocation to be pruned, got %d", users)
+	}
+}
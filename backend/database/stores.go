@@ -0,0 +1,657 @@
+package database
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+// NewStores returns the database-backed implementation of every store
+func NewStores(db *gorm.DB) store.Stores {
+	return store.Stores{
+		Users:          &UserStore{db: db},
+		RefreshTokens:  &RefreshTokenStore{db: db},
+		TwoFactors:     &TwoFactorStore{db: db},
+		Lockouts:       &LockoutStore{db: db},
+		PasswordResets: &PasswordResetStore{db: db},
+		PersonalTokens: NewPersonalTokenStore(db),
+		Revocations:    NewRevocationStore(db),
+		Profiles:       &ProfileStore{db: db},
+		Water:          &WaterStore{db: db},
+		Weights:        &WeightStore{db: db},
+		Exercises:      &ExerciseStore{db: db},
+
+		HydrationGoals: &HydrationGoalStore{db: db},
+		Beverages:      &BeverageStore{db: db},
+		Reminders:      &ReminderStore{db: db},
+		Notifications:  &NotificationStore{db: db},
+		Stats:          &StatsStore{db: db},
+	}
+}
+
+// notFound turns GORM's missing-row error into store.ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return store.ErrNotFound
+	}
+	return err
+}
+
+type UserStore struct {
+	db *gorm.DB
+}
+
+func (s *UserStore) Get(id uint) (models.User, error) {
+	var user models.User
+	err := s.db.First(&user, id).Error
+	return user, notFound(err)
+}
+
+func (s *UserStore) GetByUsername(username string) (models.User, error) {
+	var user models.User
+	err := s.db.Where("username = ?", username).First(&user).Error
+	return user, notFound(err)
+}
+
+func (s *UserStore) GetByEmail(email string) (models.User, error) {
+	var user models.User
+	err := s.db.Where("email = ?", email).First(&user).Error
+	return user, notFound(err)
+}
+
+func (s *UserStore) Create(user *models.User) error {
+	return s.db.Create(user).Error
+}
+
+// likeEscaper makes LIKE wildcards in a search term match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (s *UserStore) Search(filter store.UserFilter, offset, limit int) ([]models.User, int64, error) {
+	query := s.db.Model(&models.User{})
+	if filter.Query != "" {
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(filter.Query)) + "%"
+		query = query.Where(`LOWER(username) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\'`, pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Disabled != nil {
+		if *filter.Disabled {
+			query = query.Where("disabled_at IS NOT NULL")
+		} else {
+			query = query.Where("disabled_at IS NULL")
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	users := []models.User{}
+	err := query.Order("id").Offset(offset).Limit(limit).Find(&users).Error
+	return users, total, err
+}
+
+func (s *UserStore) SetPassword(id uint, hash string) error {
+	return s.update(id, map[string]interface{}{"password_hash": hash, "password_reset_required": false})
+}
+
+func (s *UserStore) RequirePasswordReset(id uint) error {
+	return s.update(id, map[string]interface{}{"password_reset_required": true})
+}
+
+func (s *UserStore) SetRole(id uint, role string) error {
+	return s.update(id, map[string]interface{}{"role": role})
+}
+
+func (s *UserStore) SetDisabled(id uint, at *time.Time) error {
+	return s.update(id, map[string]interface{}{"disabled_at": at})
+}
+
+func (s *UserStore) SetDeletionRequested(id uint, at *time.Time) error {
+	return s.update(id, map[string]interface{}{"deletion_requested_at": at})
+}
+
+// update changes columns of one user
+func (s *UserStore) update(id uint, values map[string]interface{}) error {
+	result := s.db.Model(&models.User{}).Where("id = ?", id).Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *UserStore) Delete(id uint) error {
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+	return DeleteUser(s.db, id)
+}
+
+type RefreshTokenStore struct {
+	db *gorm.DB
+}
+
+func (s *RefreshTokenStore) Create(token *models.RefreshToken) error {
+	return s.db.Create(token).Error
+}
+
+func (s *RefreshTokenStore) GetByHash(hash string) (models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := s.db.Where("token_hash = ?", hash).First(&token).Error
+	return token, notFound(err)
+}
+
+func (s *RefreshTokenStore) Rotate(usedID uint, next *models.RefreshToken) (bool, error) {
+	rotated := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// the used_at check makes two concurrent exchanges of one token
+		// race for the row; the loser changes nothing
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", usedID).
+			Update("used_at", time.Now())
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		rotated = true
+		return tx.Create(next).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return rotated, nil
+}
+
+func (s *RefreshTokenStore) RevokeFamily(familyID string) error {
+	return s.db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (s *RefreshTokenStore) RevokeUser(userID uint) error {
+	return s.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+type TwoFactorStore struct {
+	db *gorm.DB
+}
+
+func (s *TwoFactorStore) Get(userID uint) (models.TwoFactor, error) {
+	var twoFactor models.TwoFactor
+	err := s.db.Where("user_id = ?", userID).First(&twoFactor).Error
+	return twoFactor, notFound(err)
+}
+
+func (s *TwoFactorStore) Save(twoFactor *models.TwoFactor) error {
+	return s.db.Save(twoFactor).Error
+}
+
+func (s *TwoFactorStore) Enable(userID uint) error {
+	result := s.db.Model(&models.TwoFactor{}).Where("user_id = ?", userID).Update("enabled", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *TwoFactorStore) UseStep(userID uint, step int64) (bool, error) {
+	// two requests racing with the same code race for the row; the loser
+	// changes nothing
+	result := s.db.Model(&models.TwoFactor{}).
+		Where("user_id = ? AND last_step < ?", userID, step).
+		Update("last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (s *TwoFactorStore) Delete(userID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.TwoFactor{}).Error
+	})
+}
+
+func (s *TwoFactorStore) ReplaceRecoveryCodes(userID uint, hashes []string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		records := make([]models.RecoveryCode, len(hashes))
+		for i, hash := range hashes {
+			records[i] = models.RecoveryCode{UserID: userID, CodeHash: hash}
+		}
+		return tx.Create(&records).Error
+	})
+}
+
+func (s *TwoFactorStore) UseRecoveryCode(userID uint, hash string) (bool, error) {
+	result := s.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+type LockoutStore struct {
+	db *gorm.DB
+}
+
+func (s *LockoutStore) Create(event *models.LockoutEvent) error {
+	return s.db.Create(event).Error
+}
+
+func (s *LockoutStore) List(offset, limit int) ([]models.LockoutEvent, error) {
+	events := []models.LockoutEvent{}
+	err := s.db.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&events).Error
+	return events, err
+}
+
+type PasswordResetStore struct {
+	db *gorm.DB
+}
+
+func (s *PasswordResetStore) Replace(token *models.PasswordResetToken) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+func (s *PasswordResetStore) GetByHash(hash string) (models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := s.db.Where("token_hash = ?", hash).First(&token).Error
+	return token, notFound(err)
+}
+
+func (s *PasswordResetStore) Redeem(id uint) (bool, error) {
+	result := s.db.Model(&models.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+type ProfileStore struct {
+	db *gorm.DB
+}
+
+func (s *ProfileStore) Get(userID uint) (models.HealthProfile, error) {
+	var profile models.HealthProfile
+	err := s.db.Where("user_id = ?", userID).First(&profile).Error
+	return profile, notFound(err)
+}
+
+func (s *ProfileStore) Save(profile *models.HealthProfile) error {
+	if profile.ID == 0 {
+		return s.db.Create(profile).Error
+	}
+	return s.db.Save(profile).Error
+}
+
+type WaterStore struct {
+	db *gorm.DB
+}
+
+func (s *WaterStore) Create(log *models.WaterIntake) error {
+	return s.db.Create(log).Error
+}
+
+func (s *WaterStore) Get(userID, id uint) (models.WaterIntake, error) {
+	var log models.WaterIntake
+	err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&log).Error
+	return log, notFound(err)
+}
+
+func (s *WaterStore) List(userID uint, from, to time.Time) ([]models.WaterIntake, error) {
+	query := s.db.Where("user_id = ?", userID)
+	if !from.IsZero() {
+		query = query.Where("logged_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("logged_at < ?", to)
+	}
+
+	logs := []models.WaterIntake{}
+	err := query.Order("logged_at DESC, id DESC").Find(&logs).Error
+	return logs, err
+}
+
+func (s *WaterStore) DailyTotals(userID uint, days []time.Time) ([]store.DayTotal, error) {
+	totals := []store.DayTotal{}
+	if len(days) < 2 {
+		return totals, nil
+	}
+
+	// maps each log to its day; the range has at most a year of days
+	var day strings.Builder
+	args := make([]interface{}, 0, len(days)-1)
+	day.WriteString("CASE")
+	for i, end := range days[1:] {
+		day.WriteString(" WHEN logged_at < ? THEN " + strconv.Itoa(i))
+		// arguments outside WHERE miss the UTC callback
+		args = append(args, end.UTC())
+	}
+	day.WriteString(" END")
+
+	err := s.db.Model(&models.WaterIntake{}).
+		Select(day.String()+" AS day, SUM(amount_ml) AS total_ml, SUM(hydration_ml) AS hydration_ml, "+
+			"SUM(caffeine_mg) AS caffeine_mg, COUNT(*) AS entry_count", args...).
+		Where("user_id = ? AND logged_at >= ? AND logged_at < ?", userID, days[0], days[len(days)-1]).
+		Group("day").Order("day").
+		Scan(&totals).Error
+	return totals, err
+}
+
+func (s *WaterStore) Update(log *models.WaterIntake) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var previous models.WaterIntake
+		if err := tx.Where("id = ? AND user_id = ?", log.ID, log.UserID).First(&previous).Error; err != nil {
+			return notFound(err)
+		}
+		edit := models.WaterIntakeEdit{
+			WaterIntakeID: previous.ID,
+			UserID:        previous.UserID,
+			AmountML:      previous.AmountML,
+			LoggedAt:      previous.LoggedAt,
+			BeverageID:    previous.BeverageID,
+			HydrationML:   previous.HydrationML,
+			CaffeineMG:    previous.CaffeineMG,
+			Kcal:          previous.Kcal,
+			EditedAt:      time.Now(),
+		}
+		if err := tx.Create(&edit).Error; err != nil {
+			return err
+		}
+		log.CreatedAt = previous.CreatedAt
+		return tx.Select("amount_ml", "logged_at", "beverage_id", "hydration_ml", "caffeine_mg", "kcal", "updated_at").
+			Updates(log).Error
+	})
+}
+
+func (s *WaterStore) Edits(userID, id uint) ([]models.WaterIntakeEdit, error) {
+	edits := []models.WaterIntakeEdit{}
+	err := s.db.Where("water_intake_id = ? AND user_id = ?", id, userID).
+		Order("edited_at DESC, id DESC").Find(&edits).Error
+	return edits, err
+}
+
+func (s *WaterStore) Delete(userID, id uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND user_id = ?", id, userID).Delete(&models.WaterIntake{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return store.ErrNotFound
+		}
+		return tx.Where("water_intake_id = ?", id).Delete(&models.WaterIntakeEdit{}).Error
+	})
+}
+
+type WeightStore struct {
+	db *gorm.DB
+}
+
+func (s *WeightStore) Create(log *models.WeightLog) error {
+	return s.db.Create(log).Error
+}
+
+func (s *WeightStore) Get(userID, id uint) (models.WeightLog, error) {
+	var log models.WeightLog
+	err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&log).Error
+	return log, notFound(err)
+}
+
+func (s *WeightStore) Recent(userID uint, limit int) ([]models.WeightLog, error) {
+	logs := []models.WeightLog{}
+	err := s.db.Where("user_id = ?", userID).Order("logged_at DESC, id DESC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+func (s *WeightStore) Update(log *models.WeightLog) error {
+	result := s.db.Model(&models.WeightLog{}).
+		Where("id = ? AND user_id = ?", log.ID, log.UserID).
+		Updates(map[string]interface{}{"weight_kg": log.WeightKG, "logged_at": log.LoggedAt})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+type ExerciseStore struct {
+	db *gorm.DB
+}
+
+func (s *ExerciseStore) Create(log *models.ExerciseLog) error {
+	return s.db.Create(log).Error
+}
+
+func (s *ExerciseStore) Recent(userID uint, limit int) ([]models.ExerciseLog, error) {
+	logs := []models.ExerciseLog{}
+	err := s.db.Where("user_id = ?", userID).Order("logged_at DESC, id DESC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+func (s *ExerciseStore) List(userID uint, from, to time.Time) ([]models.ExerciseLog, error) {
+	logs := []models.ExerciseLog{}
+	err := s.db.Where("user_id = ? AND logged_at >= ? AND logged_at < ?", userID, from, to).
+		Order("logged_at DESC, id DESC").Find(&logs).Error
+	return logs, err
+}
+
+type HydrationGoalStore struct {
+	db *gorm.DB
+}
+
+func (s *HydrationGoalStore) Set(goal *models.HydrationGoal) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.HydrationGoal
+		err := tx.Where("user_id = ? AND effective_from = ?", goal.UserID, goal.EffectiveFrom).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			goal.ID = 0
+			return tx.Create(goal).Error
+		}
+		if err != nil {
+			return err
+		}
+		goal.ID, goal.CreatedAt = existing.ID, existing.CreatedAt
+		return tx.Save(goal).Error
+	})
+}
+
+func (s *HydrationGoalStore) List(userID uint) ([]models.HydrationGoal, error) {
+	goals := []models.HydrationGoal{}
+	err := s.db.Where("user_id = ?", userID).Order("effective_from").Find(&goals).Error
+	return goals, err
+}
+
+type BeverageStore struct {
+	db *gorm.DB
+}
+
+func (s *BeverageStore) List(userID uint) ([]models.Beverage, error) {
+	beverages := []models.Beverage{}
+	err := s.db.Where("user_id IS NULL OR user_id = ?", userID).Order("name, id").Find(&beverages).Error
+	return beverages, err
+}
+
+func (s *BeverageStore) Get(userID, id uint) (models.Beverage, error) {
+	var beverage models.Beverage
+	err := s.db.Where("id = ? AND (user_id IS NULL OR user_id = ?)", id, userID).First(&beverage).Error
+	return beverage, notFound(err)
+}
+
+func (s *BeverageStore) Create(beverage *models.Beverage) error {
+	return s.db.Create(beverage).Error
+}
+
+func (s *BeverageStore) Delete(userID, id uint) error {
+	// user_id = ? never matches the built-in catalog
+	result := s.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Beverage{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+type ReminderStore struct {
+	db *gorm.DB
+}
+
+func (s *ReminderStore) List(userID uint) ([]models.ReminderRule, error) {
+	rules := []models.ReminderRule{}
+	err := s.db.Where("user_id = ?", userID).Order("id").Find(&rules).Error
+	return rules, err
+}
+
+func (s *ReminderStore) Get(userID, id uint) (models.ReminderRule, error) {
+	var rule models.ReminderRule
+	err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&rule).Error
+	return rule, notFound(err)
+}
+
+func (s *ReminderStore) Create(rule *models.ReminderRule) error {
+	return s.db.Create(rule).Error
+}
+
+func (s *ReminderStore) Update(rule *models.ReminderRule) error {
+	// the scheduler writes last_sent_at at any time, so it is never saved
+	// from here
+	result := s.db.Model(&models.ReminderRule{}).
+		Where("id = ? AND user_id = ?", rule.ID, rule.UserID).
+		Updates(map[string]interface{}{
+			"kind":             rule.Kind,
+			"enabled":          rule.Enabled,
+			"start_time":       rule.StartTime,
+			"end_time":         rule.EndTime,
+			"interval_minutes": rule.IntervalMinutes,
+			"margin_percent":   rule.MarginPercent,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return store.ErrNotFound
+	}
+	return s.db.First(rule, rule.ID).Error
+}
+
+func (s *ReminderStore) Delete(userID, id uint) error {
+	result := s.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.ReminderRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *ReminderStore) Enabled() ([]models.ReminderRule, error) {
+	rules := []models.ReminderRule{}
+	err := s.db.Where("enabled = ?", true).Order("id").Find(&rules).Error
+	return rules, err
+}
+
+func (s *ReminderStore) Claim(id uint, previous *time.Time, at time.Time) (bool, error) {
+	query := s.db.Model(&models.ReminderRule{}).Where("id = ?", id)
+	if previous == nil {
+		query = query.Where("last_sent_at IS NULL")
+	} else {
+		query = query.Where("last_sent_at = ?", *previous)
+	}
+	result := query.Update("last_sent_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+type NotificationStore struct {
+	db *gorm.DB
+}
+
+func (s *NotificationStore) Create(notification *models.Notification) error {
+	return s.db.Create(notification).Error
+}
+
+func (s *NotificationStore) List(userID uint, limit int) ([]models.Notification, error) {
+	notifications := []models.Notification{}
+	err := s.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+func (s *NotificationStore) MarkRead(userID, id uint, at time.Time) error {
+	var notification models.Notification
+	if err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		return notFound(err)
+	}
+	return s.db.Model(&models.Notification{}).
+		Where("id = ? AND read_at IS NULL", id).
+		Update("read_at", at).Error
+}
+
+type StatsStore struct {
+	db *gorm.DB
+}
+
+func (s *StatsStore) Usage(weekAgo, monthAgo time.Time) (store.UsageStats, error) {
+	stats := store.UsageStats{UsersByRole: map[string]int64{}}
+	var roles []struct {
+		Role  string
+		Count int64
+	}
+
+	// one transaction so the counts agree with each other
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		counts := []struct {
+			query *gorm.DB
+			into  *int64
+		}{
+			{tx.Model(&models.User{}), &stats.Users},
+			{tx.Model(&models.User{}).Where("disabled_at IS NOT NULL"), &stats.Disabled},
+			{tx.Model(&models.User{}).Where("deletion_requested_at IS NOT NULL"), &stats.PendingDeletion},
+			{tx.Model(&models.TwoFactor{}).Where("enabled = ?", true), &stats.TwoFactorEnabled},
+			{tx.Model(&models.User{}).Where("created_at >= ?", weekAgo), &stats.NewLastWeek},
+			{tx.Model(&models.User{}).Where("created_at >= ?", monthAgo), &stats.NewLastMonth},
+			{tx.Model(&models.RefreshToken{}).Where("created_at >= ?", weekAgo).Distinct("user_id"), &stats.ActiveLastWeek},
+			{tx.Model(&models.WaterIntake{}), &stats.WaterLogs},
+			{tx.Model(&models.WeightLog{}), &stats.WeightLogs},
+			{tx.Model(&models.ExerciseLog{}), &stats.ExerciseLogs},
+		}
+		for _, count := range counts {
+			if err := count.query.Count(count.into).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.User{}).Select("role, COUNT(*) AS count").Group("role").Scan(&roles).Error
+	})
+	if err != nil {
+		return store.UsageStats{}, err
+	}
+
+	for _, role := range roles {
+		stats.UsersByRole[role.Role] = role.Count
+	}
+	return stats, nil
+}
@@ -0,0 +1,23 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database/dbtest"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database/migrations"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store/storetest"
+)
+
+func TestStores(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Stores {
+		db := dbtest.Open(t)
+		// migrated rather than auto-migrated: the built-in beverages come
+		// from a migration
+		if _, err := migrations.New(db).Up(0, false); err != nil {
+			t.Fatalf("Failed to migrate test database: %v", err)
+		}
+		return database.NewStores(db)
+	})
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLite keeps times as text with their UTC offset and compares them as
+// strings, while PostgreSQL compares instants. Storing and querying every
+// time in UTC makes range filters such as "logged_at >= ?" behave the same
+// on both.
+
+// nowUTC is GORM's clock for autoCreateTime and autoUpdateTime columns
+func nowUTC() time.Time {
+	return time.Now().UTC()
+}
+
+// registerUTCCallbacks converts times to UTC in written rows, in Update
+// maps and in WHERE conditions before GORM builds the SQL
+func registerUTCCallbacks(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("app:utc", utcCallback); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("app:utc", utcCallback); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("app:utc", utcCallback); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("app:utc", utcCallback); err != nil {
+		return err
+	}
+	return callbacks.Row().Before("gorm:row").Register("app:utc", utcCallback)
+}
+
+func utcCallback(db *gorm.DB) {
+	stmt := db.Statement
+
+	if stmt.Schema != nil && stmt.ReflectValue.IsValid() {
+		switch stmt.ReflectValue.Kind() {
+		case reflect.Struct:
+			utcFields(db, stmt.ReflectValue)
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < stmt.ReflectValue.Len(); i++ {
+				utcFields(db, reflect.Indirect(stmt.ReflectValue.Index(i)))
+			}
+		}
+	}
+
+	if values, ok := stmt.Dest.(map[string]interface{}); ok {
+		for key, value := range values {
+			values[key] = toUTC(value)
+		}
+	}
+
+	if where, ok := stmt.Clauses["WHERE"]; ok {
+		if expression, ok := where.Expression.(clause.Where); ok {
+			utcExpressions(expression.Exprs)
+		}
+	}
+}
+
+// utcFields rewrites the time fields of one row
+func utcFields(db *gorm.DB, row reflect.Value) {
+	if row.Kind() != reflect.Struct {
+		return
+	}
+	for _, field := range db.Statement.Schema.Fields {
+		if field.FieldType != reflect.TypeOf(time.Time{}) && field.FieldType != reflect.TypeOf(&time.Time{}) {
+			continue
+		}
+		value, zero := field.ValueOf(db.Statement.Context, row)
+		if zero {
+			continue
+		}
+		if converted := toUTC(value); converted != value {
+			field.Set(db.Statement.Context, row, converted)
+		}
+	}
+}
+
+// utcExpressions rewrites the time arguments of WHERE conditions in place
+func utcExpressions(exprs []clause.Expression) {
+	for i, expr := range exprs {
+		switch e := expr.(type) {
+		case clause.Expr:
+			utcValues(e.Vars)
+		case clause.NamedExpr:
+			utcValues(e.Vars)
+		case clause.AndConditions:
+			utcExpressions(e.Exprs)
+		case clause.OrConditions:
+			utcExpressions(e.Exprs)
+		case clause.NotConditions:
+			utcExpressions(e.Exprs)
+		case clause.IN:
+			utcValues(e.Values)
+		case clause.Eq:
+			e.Value = toUTC(e.Value)
+			exprs[i] = e
+		case clause.Neq:
+			e.Value = toUTC(e.Value)
+			exprs[i] = e
+		case clause.Gt:
+			e.Value = toUTC(e.Value)
+			exprs[i] = e
+		case clause.Gte:
+			e.Value = toUTC(e.Value)
+			exprs[i] = e
+		case clause.Lt:
+			e.Value = toUTC(e.Value)
+			exprs[i] = e
+		case clause.Lte:
+			e.Value = toUTC(e.Value)
+			exprs[i] = e
+		}
+	}
+}
+
+func utcValues(values []interface{}) {
+	for i, value := range values {
+		values[i] = toUTC(value)
+	}
+}
+
+func toUTC(value interface{}) interface{} {
+	switch t := value.(type) {
+	case time.Time:
+		if t.Location() != time.UTC {
+			return t.UTC()
+		}
+	case *time.Time:
+		if t != nil && t.Location() != time.UTC {
+			utc := t.UTC()
+			return &utc
+		}
+	}
+	return value
+}
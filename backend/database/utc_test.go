@@ -0,0 +1,58 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+)
+
+func TestConfigure_StoresAndQueriesUTC(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := Configure(db); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	if err := db.AutoMigrate(&models.WaterIntake{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	east := time.FixedZone("UTC+2", 2*60*60)
+	loggedAt := time.Date(2026, 3, 11, 0, 30, 0, 0, east)
+	log := models.WaterIntake{UserID: 1, AmountML: 250, LoggedAt: loggedAt}
+	if err := db.Create(&log).Error; err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	var stored string
+	db.Raw("SELECT logged_at FROM water_intakes WHERE id = ?", log.ID).Scan(&stored)
+	if stored != "2026-03-10T22:30:00Z" {
+		t.Errorf("Expected the time stored in UTC, got %q", stored)
+	}
+
+	// a bound in another zone still compares as an instant
+	var count int64
+	db.Model(&models.WaterIntake{}).Where("logged_at >= ?", time.Date(2026, 3, 11, 0, 0, 0, 0, east)).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected the log after 2026-03-11T00:00+02:00, got %d rows", count)
+	}
+	db.Model(&models.WaterIntake{}).Where("logged_at < ?", time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)).Count(&count)
+	if count != 1 {
+		t.Errorf("Expected the log before 2026-03-11T00:00Z, got %d rows", count)
+	}
+
+	// Update(column, value) goes through a map
+	if err := db.Model(&log).Update("logged_at", time.Date(2026, 3, 12, 1, 0, 0, 0, east)).Error; err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	db.Raw("SELECT logged_at FROM water_intakes WHERE id = ?", log.ID).Scan(&stored)
+	if stored != "2026-03-11T23:00:00Z" {
+		t.Errorf("Expected the update stored in UTC, got %q", stored)
+	}
+}
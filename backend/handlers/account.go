@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+type deleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// AccountHandler deletes accounts and restores ones pending deletion
+type AccountHandler struct {
+	auth *AuthHandler
+	cfg  config.AccountConfig
+}
+
+func NewAccountHandler(auth *AuthHandler, cfg config.AccountConfig) *AccountHandler {
+	return &AccountHandler{auth: auth, cfg: cfg}
+}
+
+// DeleteAccount - DELETE /api/account
+// Requires the password. Without a grace period the user and all of their
+// health data are removed at once; otherwise the account is locked and
+// purged by a background job when the grace period ends.
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	var req deleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+
+	user, err := h.auth.users.Get(userID)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.UserNotFound, "User not found.")
+		return
+	}
+
+	if !utils.CheckPasswordHash(user.PasswordHash, req.Password) {
+		apierror.Respond(c, http.StatusForbidden, apierror.IncorrectPassword, "Password is incorrect.")
+		return
+	}
+
+	if h.cfg.DeletionGraceDays == 0 {
+		// already-issued access tokens outlive the rows, so they are
+		// revoked first; if that fails the account is left in place
+		if err := h.auth.revocations.RevokeUser(user.ID, time.Now()); err != nil {
+			serverError(c, "Failed to delete account.", err)
+			return
+		}
+		if err := h.auth.users.Delete(user.ID); err != nil {
+			serverError(c, "Failed to delete account.", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Account deleted"})
+		return
+	}
+
+	now := time.Now()
+	if err := h.auth.users.SetDeletionRequested(user.ID, &now); err != nil {
+		serverError(c, "Failed to delete account.", err)
+		return
+	}
+	if err := h.auth.revokeSessions(user.ID); err != nil {
+		serverError(c, "Failed to delete account.", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":     "Account scheduled for deletion. Log in through the restore endpoint to cancel.",
+		"purge_after": now.Add(h.cfg.DeletionGracePeriod()),
+	})
+}
+
+// RestoreAccount - POST /api/account/restore
+// Cancels a pending deletion and logs the user back in. Users with
+// two-factor authentication get a challenge to finish at /api/auth/login/2fa.
+func (h *AccountHandler) RestoreAccount(c *gin.Context) {
+	var req loginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+
+	user, ok := h.auth.authenticate(c, req.Username, req.Password)
+	if !ok {
+		return
+	}
+
+	if user.DeletionRequestedAt == nil {
+		apierror.Respond(c, http.StatusBadRequest, apierror.AccountNotPendingDelete, "Account is not scheduled for deletion.")
+		return
+	}
+
+	if err := h.auth.users.SetDeletionRequested(user.ID, nil); err != nil {
+		serverError(c, "Failed to restore account.", err)
+		return
+	}
+
+	// the account is back, but the session still needs the second factor
+	if enabled, err := h.auth.twoFactorEnabled(user.ID); err != nil {
+		serverError(c, "Failed to log in.", err)
+		return
+	} else if enabled {
+		sendMFAChallenge(c, user)
+		return
+	}
+	loginThrottle.Success(user.Username)
+
+	tokens, err := h.auth.issueTokens(user)
+	if err != nil {
+		serverError(c, "Failed to generate token.", err)
+		return
+	}
+
+	response := gin.H{
+		"message":  "Account restored",
+		"id":       user.ID,
+		"username": user.Username,
+	}
+	tokens.addTo(response)
+	c.JSON(http.StatusOK, response)
+}
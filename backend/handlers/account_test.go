@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+func setupAccountRouter(t *testing.T, stores store.Stores, graceDays int) *gin.Engine {
+	router, auth := setupSessionRouter(t, stores)
+	accounts := NewAccountHandler(auth, config.AccountConfig{DeletionGraceDays: graceDays})
+	router.POST("/account/restore", accounts.RestoreAccount)
+	router.DELETE("/account", middleware.AuthMiddleware(), accounts.DeleteAccount)
+	return router
+}
+
+// gives a user a record in every store of health data
+func seedHealthData(t *testing.T, stores store.Stores, userID uint) {
+	log := models.WaterIntake{UserID: userID, AmountML: 250, LoggedAt: time.Now()}
+	goalML := 2500
+	seed(t, stores,
+		&models.HealthProfile{UserID: userID, Sex: "male", HeightCM: 180, WeightKG: 80},
+		&log,
+		&models.WeightLog{UserID: userID, WeightKG: 80},
+		&models.ExerciseLog{UserID: userID, Type: "Running", Duration: 30, CaloriesBurned: 300},
+		&models.HydrationGoal{UserID: userID, GoalML: &goalML, EffectiveFrom: "2026-01-01"},
+		&models.ReminderRule{UserID: userID, Kind: models.ReminderInterval, Enabled: true, StartTime: "08:00", EndTime: "22:00", IntervalMinutes: 90},
+		&models.Notification{UserID: userID, Kind: models.NotificationHydrationReminder, Title: "Time for some water"},
+	)
+
+	// the edit keeps the logged amount as an earlier version
+	log.AmountML, log.HydrationML = 300, 300
+	if err := stores.Water.Update(&log); err != nil {
+		t.Fatalf("Failed to edit water log: %v", err)
+	}
+}
+
+// countUserRecords counts the health records the stores hold for a user
+func countUserRecords(t *testing.T, stores store.Stores, userID uint) int {
+	t.Helper()
+	total := 0
+	if _, err := stores.Profiles.Get(userID); err == nil {
+		total++
+	}
+	water, err := stores.Water.List(userID, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Failed to list water logs: %v", err)
+	}
+	for _, log := range water {
+		edits, err := stores.Water.Edits(userID, log.ID)
+		if err != nil {
+			t.Fatalf("Failed to list water log edits: %v", err)
+		}
+		total += 1 + len(edits)
+	}
+	weights, err := stores.Weights.Recent(userID, 100)
+	if err != nil {
+		t.Fatalf("Failed to list weight logs: %v", err)
+	}
+	exercises, err := stores.Exercises.Recent(userID, 100)
+	if err != nil {
+		t.Fatalf("Failed to list exercise logs: %v", err)
+	}
+	goals, err := stores.HydrationGoals.List(userID)
+	if err != nil {
+		t.Fatalf("Failed to list hydration goals: %v", err)
+	}
+	rules, err := stores.Reminders.List(userID)
+	if err != nil {
+		t.Fatalf("Failed to list reminder rules: %v", err)
+	}
+	notifications, err := stores.Notifications.List(userID, 100)
+	if err != nil {
+		t.Fatalf("Failed to list notifications: %v", err)
+	}
+	return total + len(weights) + len(exercises) + len(goals) + len(rules) + len(notifications)
+}
+
+func TestDeleteAccount_Immediate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAccountRouter(t, stores, 0)
+
+		session := registerTestUser(t, router, "testuser")
+		other := registerTestUser(t, router, "otheruser")
+		userID := uint(session["id"].(float64))
+		otherID := uint(other["id"].(float64))
+		seedHealthData(t, stores, userID)
+		seedHealthData(t, stores, otherID)
+
+		w := jsonRequest(router, "DELETE", "/account", session["token"].(string), map[string]string{"password": "password123"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		if _, err := stores.Users.Get(userID); err == nil {
+			t.Error("Expected user to be deleted")
+		}
+		if records := countUserRecords(t, stores, userID); records != 0 {
+			t.Errorf("Expected all of the user's data to be deleted, %d records left", records)
+		}
+		if records := countUserRecords(t, stores, otherID); records == 0 {
+			t.Error("Expected other user's data to be untouched")
+		}
+
+		if w := authedRequest(router, "GET", "/me", session["token"].(string)); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected token of deleted user to be revoked, got %d", w.Code)
+		}
+	})
+}
+
+// failingRevocationStore cannot record logouts
+type failingRevocationStore struct {
+	*utils.MemoryRevocationStore
+}
+
+func (failingRevocationStore) RevokeUser(userID uint, before time.Time) error {
+	return errors.New("revocation store unavailable")
+}
+
+func TestDeleteAccount_RevocationFailureKeepsAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAccountRouter(t, stores, 0)
+		failingStores := stores
+		failingStores.Revocations = failingRevocationStore{utils.NewMemoryRevocationStore()}
+		failing := NewAccountHandler(newAuthHandler(failingStores), config.AccountConfig{})
+		router.DELETE("/account-failing", middleware.AuthMiddleware(), failing.DeleteAccount)
+
+		session := registerTestUser(t, router, "testuser")
+
+		w := jsonRequest(router, "DELETE", "/account-failing", session["token"].(string), map[string]string{"password": "password123"})
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected status 500, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		if _, err := stores.Users.GetByUsername("testuser"); err != nil {
+			t.Error("Expected user to still exist when their tokens could not be revoked")
+		}
+	})
+}
+
+func TestDeleteAccount_WrongPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAccountRouter(t, stores, 0)
+
+		session := registerTestUser(t, router, "testuser")
+
+		w := jsonRequest(router, "DELETE", "/account", session["token"].(string), map[string]string{"password": "wrongpassword"})
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+
+		if _, err := stores.Users.GetByUsername("testuser"); err != nil {
+			t.Error("Expected user to still exist")
+		}
+	})
+}
+
+func TestDeleteAccount_GracePeriodAndRestore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAccountRouter(t, stores, 7)
+
+		session := registerTestUser(t, router, "testuser")
+		userID := uint(session["id"].(float64))
+		seedHealthData(t, stores, userID)
+
+		w := jsonRequest(router, "DELETE", "/account", session["token"].(string), map[string]string{"password": "password123"})
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("Expected status 202, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		// data is kept during the grace period, but the user cannot log in
+		if records := countUserRecords(t, stores, userID); records == 0 {
+			t.Error("Expected data to be kept during the grace period")
+		}
+		credentials := map[string]string{"username": "testuser", "password": "password123"}
+		if w := jsonRequest(router, "POST", "/login", "", credentials); w.Code != http.StatusForbidden {
+			t.Errorf("Expected login to be refused while deletion is pending, got %d", w.Code)
+		}
+
+		// restoring cancels the deletion
+		w = jsonRequest(router, "POST", "/account/restore", "", credentials)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 on restore, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var restored map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &restored)
+		if restored["token"] == nil {
+			t.Error("Expected restore to return a token")
+		}
+
+		if w := jsonRequest(router, "POST", "/login", "", credentials); w.Code != http.StatusOK {
+			t.Errorf("Expected login to work after restore, got %d", w.Code)
+		}
+	})
+}
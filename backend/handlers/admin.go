@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+// AdminHandler serves /api/admin
+type AdminHandler struct {
+	auth      *AuthHandler
+	passwords *PasswordHandler // mails forced password resets
+	stats     store.StatsStore
+}
+
+func NewAdminHandler(auth *AuthHandler, passwords *PasswordHandler, stats store.StatsStore) *AdminHandler {
+	return &AdminHandler{auth: auth, passwords: passwords, stats: stats}
+}
+
+type setRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// adminUserResponse is what admins see of an account. It never includes
+// health data.
+func adminUserResponse(user models.User) gin.H {
+	return gin.H{
+		"id":                      user.ID,
+		"username":                user.Username,
+		"email":                   user.Email,
+		"role":                    user.Role,
+		"created_at":              user.CreatedAt,
+		"disabled_at":             user.DisabledAt,
+		"deletion_requested_at":   user.DeletionRequestedAt,
+		"password_reset_required": user.PasswordResetRequired,
+	}
+}
+
+// pagination reads ?page= and ?limit=, defaulting to the first 50 rows
+func pagination(c *gin.Context) (page int, limit int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	return page, limit
+}
+
+// findTargetUser loads the user named in the path, writing a 404 if there
+// is none
+func (h *AdminHandler) findTargetUser(c *gin.Context) (models.User, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.UserNotFound, "User not found.")
+		return models.User{}, false
+	}
+	user, err := h.auth.users.Get(uint(id))
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.UserNotFound, "User not found.")
+		return models.User{}, false
+	}
+	return user, true
+}
+
+// notSelf refuses admin actions that would lock the caller out of the
+// admin API
+func notSelf(c *gin.Context, user models.User) bool {
+	if userID, _ := middleware.GetUserID(c); userID == user.ID {
+		apierror.Respond(c, http.StatusBadRequest, apierror.CannotTargetSelf, "Admins cannot do this to their own account.")
+		return false
+	}
+	return true
+}
+
+// ListUsers - GET /api/admin/users
+// Optional filters: q (username or email contains), role, disabled=true|false.
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	filter := store.UserFilter{Query: strings.TrimSpace(c.Query("q"))}
+	if role := c.Query("role"); role != "" {
+		if !models.ValidRole(role) {
+			apierror.Invalid(c, "role", "oneof", "Unknown role.")
+			return
+		}
+		filter.Role = role
+	}
+	switch c.Query("disabled") {
+	case "true":
+		disabled := true
+		filter.Disabled = &disabled
+	case "false":
+		disabled := false
+		filter.Disabled = &disabled
+	}
+
+	page, limit := pagination(c)
+	users, total, err := h.auth.users.Search(filter, (page-1)*limit, limit)
+	if err != nil {
+		serverError(c, "Failed to retrieve users.", err)
+		return
+	}
+
+	response := make([]gin.H, len(users))
+	for i, user := range users {
+		response[i] = adminUserResponse(user)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"users": response,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GetUser - GET /api/admin/users/:id
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, ok := h.findTargetUser(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, adminUserResponse(user))
+}
+
+// DisableUser - POST /api/admin/users/:id/disable
+// Blocks logins and ends every session and personal access token use.
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	user, ok := h.findTargetUser(c)
+	if !ok || !notSelf(c, user) {
+		return
+	}
+
+	if user.DisabledAt == nil {
+		now := time.Now()
+		if err := h.auth.users.SetDisabled(user.ID, &now); err != nil {
+			serverError(c, "Failed to disable user.", err)
+			return
+		}
+		user.DisabledAt = &now
+	}
+	if err := h.auth.revokeSessions(user.ID); err != nil {
+		serverError(c, "Failed to disable user.", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, adminUserResponse(user))
+}
+
+// EnableUser - POST /api/admin/users/:id/enable
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	user, ok := h.findTargetUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.auth.users.SetDisabled(user.ID, nil); err != nil {
+		serverError(c, "Failed to enable user.", err)
+		return
+	}
+	user.DisabledAt = nil
+
+	c.JSON(http.StatusOK, adminUserResponse(user))
+}
+
+// ForcePasswordReset - POST /api/admin/users/:id/force-password-reset
+// Ends the user's sessions and blocks logins until they choose a new
+// password through the reset flow. Users with an email address are sent a
+// reset link straight away.
+func (h *AdminHandler) ForcePasswordReset(c *gin.Context) {
+	user, ok := h.findTargetUser(c)
+	if !ok || !notSelf(c, user) {
+		return
+	}
+
+	if err := h.auth.users.RequirePasswordReset(user.ID); err != nil {
+		serverError(c, "Failed to force password reset.", err)
+		return
+	}
+	user.PasswordResetRequired = true
+
+	if err := h.auth.revokeSessions(user.ID); err != nil {
+		serverError(c, "Failed to force password reset.", err)
+		return
+	}
+
+	emailed := false
+	if user.Email != nil {
+		if err := h.passwords.sendPasswordReset(c.Request.Context(), user); err != nil {
+			serverError(c, "Failed to send reset email.", err)
+			return
+		}
+		emailed = true
+	}
+
+	response := adminUserResponse(user)
+	response["reset_email_sent"] = emailed
+	c.JSON(http.StatusOK, response)
+}
+
+// SetUserRole - PUT /api/admin/users/:id/role
+// The user's sessions are ended so the new role applies from their next
+// login.
+func (h *AdminHandler) SetUserRole(c *gin.Context) {
+	user, ok := h.findTargetUser(c)
+	if !ok || !notSelf(c, user) {
+		return
+	}
+
+	var req setRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+	if !models.ValidRole(req.Role) {
+		apierror.Invalid(c, "role", "oneof", "Role must be 'user', 'coach' or 'admin'.")
+		return
+	}
+
+	if err := h.auth.users.SetRole(user.ID, req.Role); err != nil {
+		serverError(c, "Failed to update role.", err)
+		return
+	}
+	user.Role = req.Role
+
+	if err := h.auth.revokeSessions(user.ID); err != nil {
+		serverError(c, "Failed to update role.", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, adminUserResponse(user))
+}
+
+// ListLockouts - GET /api/admin/lockouts
+// Login lockouts recorded by the brute-force protection, newest first.
+func (h *AdminHandler) ListLockouts(c *gin.Context) {
+	page, limit := pagination(c)
+
+	events, err := h.auth.lockouts.List((page-1)*limit, limit)
+	if err != nil {
+		serverError(c, "Failed to retrieve lockouts.", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"lockouts": events, "page": page, "limit": limit})
+}
+
+// GetUsageStats - GET /api/admin/stats
+// Aggregate counts only; nothing here identifies a user.
+func (h *AdminHandler) GetUsageStats(c *gin.Context) {
+	now := time.Now()
+	stats, err := h.stats.Usage(now.AddDate(0, 0, -7), now.AddDate(0, 0, -30))
+	if err != nil {
+		serverError(c, "Failed to compute statistics.", err)
+		return
+	}
+
+	byRole := gin.H{models.RoleUser: 0, models.RoleCoach: 0, models.RoleAdmin: 0}
+	for role, count := range stats.UsersByRole {
+		byRole[role] = count
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": gin.H{
+			"total":              stats.Users,
+			"by_role":            byRole,
+			"disabled":           stats.Disabled,
+			"pending_deletion":   stats.PendingDeletion,
+			"two_factor_enabled": stats.TwoFactorEnabled,
+			"new_last_7_days":    stats.NewLastWeek,
+			"new_last_30_days":   stats.NewLastMonth,
+			"active_last_7_days": stats.ActiveLastWeek,
+		},
+		"logs": gin.H{
+			"water":    stats.WaterLogs,
+			"weight":   stats.WeightLogs,
+			"exercise": stats.ExerciseLogs,
+		},
+	})
+}
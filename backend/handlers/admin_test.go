@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/mailer"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+func setupAdminRouter(t *testing.T, stores store.Stores, m mailer.Mailer) *gin.Engine {
+	router, auth := setupSessionRouter(t, stores)
+	passwords := NewPasswordHandler(auth, stores.PasswordResets, m, config.Default().Auth)
+	admins := NewAdminHandler(auth, passwords, stores.Stats)
+	router.POST("/password/reset", passwords.ResetPassword)
+	admin := router.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.SessionOnly(), middleware.RequireRole(models.RoleAdmin))
+	admin.GET("/users", admins.ListUsers)
+	admin.POST("/users/:id/disable", admins.DisableUser)
+	admin.POST("/users/:id/enable", admins.EnableUser)
+	admin.POST("/users/:id/force-password-reset", admins.ForcePasswordReset)
+	admin.PUT("/users/:id/role", admins.SetUserRole)
+	admin.GET("/stats", admins.GetUsageStats)
+	return router
+}
+
+// registerAdmin registers a user, promotes them and logs them in again so
+// the token carries the role
+func registerAdmin(t *testing.T, stores store.Stores, router *gin.Engine) string {
+	registered := registerTestUser(t, router, "adminuser")
+	if err := stores.Users.SetRole(uint(registered["id"].(float64)), models.RoleAdmin); err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+	var response map[string]interface{}
+	json.Unmarshal(postLogin(router, "adminuser", "password123").Body.Bytes(), &response)
+	return response["token"].(string)
+}
+
+func TestAdmin_RequiresAdminRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAdminRouter(t, stores, mailer.NewLogMailer(io.Discard))
+		session := registerTestUser(t, router, "testuser")
+
+		if w := authedRequest(router, "GET", "/admin/users", session["token"].(string)); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+}
+
+func TestAdmin_ListAndSearchUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAdminRouter(t, stores, mailer.NewLogMailer(io.Discard))
+		adminToken := registerAdmin(t, stores, router)
+		registerTestUser(t, router, "testuser")
+		registerTestUser(t, router, "otheruser")
+
+		w := authedRequest(router, "GET", "/admin/users?q=TEST", adminToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		var response struct {
+			Users []map[string]interface{} `json:"users"`
+			Total int                      `json:"total"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if response.Total != 1 || len(response.Users) != 1 || response.Users[0]["username"] != "testuser" {
+			t.Errorf("Unexpected search result %+v", response)
+		}
+		if strings.Contains(w.Body.String(), "password_hash") || strings.Contains(w.Body.String(), "$2a$") {
+			t.Error("Password hashes must not be listed")
+		}
+
+		json.Unmarshal(authedRequest(router, "GET", "/admin/users?role=admin", adminToken).Body.Bytes(), &response)
+		if response.Total != 1 || response.Users[0]["username"] != "adminuser" {
+			t.Errorf("Unexpected role filter result %+v", response)
+		}
+	})
+}
+
+func TestAdmin_SearchMatchesWildcardsLiterally(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAdminRouter(t, stores, mailer.NewLogMailer(io.Discard))
+		adminToken := registerAdmin(t, stores, router)
+		registerTestUser(t, router, "test_user")
+		registerTestUser(t, router, "testxuser")
+
+		var response struct {
+			Users []map[string]interface{} `json:"users"`
+			Total int                      `json:"total"`
+		}
+		json.Unmarshal(authedRequest(router, "GET", "/admin/users?q=t_u", adminToken).Body.Bytes(), &response)
+		if response.Total != 1 || response.Users[0]["username"] != "test_user" {
+			t.Errorf("Expected _ to match only itself, got %+v", response)
+		}
+
+		response.Users, response.Total = nil, 0
+		json.Unmarshal(authedRequest(router, "GET", "/admin/users?q=%25", adminToken).Body.Bytes(), &response)
+		if response.Total != 0 {
+			t.Errorf("Expected %% to match no usernames, got %+v", response)
+		}
+	})
+}
+
+func TestAdmin_DisableAndEnableUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAdminRouter(t, stores, mailer.NewLogMailer(io.Discard))
+		adminToken := registerAdmin(t, stores, router)
+		session := registerTestUser(t, router, "testuser")
+		path := "/admin/users/" + jsonNumber(session["id"])
+
+		if w := authedRequest(router, "POST", path+"/disable", adminToken); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d %s", w.Code, w.Body.String())
+		}
+		if w := authedRequest(router, "GET", "/me", session["token"].(string)); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected the disabled user's session to be revoked, got %d", w.Code)
+		}
+		if w := postLogin(router, "testuser", "password123"); w.Code != http.StatusForbidden {
+			t.Errorf("Expected a disabled user to be refused, got %d", w.Code)
+		}
+		// a wrong password does not reveal that the account is disabled
+		if w := postLogin(router, "testuser", "wrongpassword"); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+
+		if w := authedRequest(router, "POST", path+"/enable", adminToken); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		if w := postLogin(router, "testuser", "password123"); w.Code != http.StatusOK {
+			t.Errorf("Expected an enabled user to log in, got %d", w.Code)
+		}
+	})
+}
+
+func TestAdmin_CannotDisableSelf(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAdminRouter(t, stores, mailer.NewLogMailer(io.Discard))
+		adminToken := registerAdmin(t, stores, router)
+
+		admin, _ := stores.Users.GetByUsername("adminuser")
+		if w := authedRequest(router, "POST", "/admin/users/"+jsonNumber(admin.ID)+"/disable", adminToken); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestAdmin_ForcePasswordReset(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		m := mailer.NewRecorder()
+		router := setupAdminRouter(t, stores, m)
+		adminToken := registerAdmin(t, stores, router)
+		user := registerWithEmail(t, router, "testuser", "test@example.com")
+
+		w := authedRequest(router, "POST", "/admin/users/"+jsonNumber(user["id"])+"/force-password-reset", adminToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d %s", w.Code, w.Body.String())
+		}
+		if w := postLogin(router, "testuser", "password123"); w.Code != http.StatusForbidden {
+			t.Errorf("Expected login to be blocked until reset, got %d", w.Code)
+		}
+
+		reset := jsonRequest(router, "POST", "/password/reset", "", gin.H{
+			"token":        resetTokenFromMail(t, m),
+			"new_password": "brandnew123",
+		})
+		if reset.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d %s", reset.Code, reset.Body.String())
+		}
+		if w := postLogin(router, "testuser", "brandnew123"); w.Code != http.StatusOK {
+			t.Errorf("Expected login with the new password, got %d", w.Code)
+		}
+	})
+}
+
+func TestAdmin_SetRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAdminRouter(t, stores, mailer.NewLogMailer(io.Discard))
+		adminToken := registerAdmin(t, stores, router)
+		session := registerTestUser(t, router, "testuser")
+		path := "/admin/users/" + jsonNumber(session["id"]) + "/role"
+
+		if w := jsonRequest(router, "PUT", path, adminToken, gin.H{"role": "superuser"}); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+		if w := jsonRequest(router, "PUT", path, adminToken, gin.H{"role": "coach"}); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(postLogin(router, "testuser", "password123").Body.Bytes(), &response)
+		claims, err := utils.ValidateToken(response["token"].(string))
+		if err != nil || claims.Role != models.RoleCoach {
+			t.Errorf("Expected a coach token, got %+v %v", claims, err)
+		}
+	})
+}
+
+func TestAdmin_UsageStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAdminRouter(t, stores, mailer.NewLogMailer(io.Discard))
+		adminToken := registerAdmin(t, stores, router)
+		session := registerTestUser(t, router, "testuser")
+		seedHealthData(t, stores, uint(session["id"].(float64)))
+
+		w := authedRequest(router, "GET", "/admin/stats", adminToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d %s", w.Code, w.Body.String())
+		}
+		var stats struct {
+			Users struct {
+				Total  int            `json:"total"`
+				ByRole map[string]int `json:"by_role"`
+				Active int            `json:"active_last_7_days"`
+			} `json:"users"`
+			Logs map[string]int `json:"logs"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &stats)
+		if stats.Users.Total != 2 || stats.Users.ByRole["admin"] != 1 || stats.Users.ByRole["user"] != 1 {
+			t.Errorf("Unexpected user stats %+v", stats.Users)
+		}
+		if stats.Users.Active != 2 {
+			t.Errorf("Expected 2 active users, got %d", stats.Users.Active)
+		}
+		if stats.Logs["water"] == 0 {
+			t.Errorf("Expected water logs to be counted, got %v", stats.Logs)
+		}
+		if strings.Contains(w.Body.String(), "testuser") || strings.Contains(w.Body.String(), session["token"].(string)) {
+			t.Error("Statistics must not identify users")
+		}
+	})
+}
@@ -1,19 +1,23 @@
 package handlers
 
 import (
-	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
-
+	"errors"
 	"net/http"
 	"time"
 
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
 )
 
 type registerRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	Email    string `json:"email"` // optional, used for password reset
 }
 
 type loginRequest struct {
@@ -21,132 +25,227 @@ type loginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-func Register(c *gin.Context, db *gorm.DB) {
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// tokenPair is what a client receives after logging in or refreshing
+type tokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int // access token lifetime in seconds
+}
+
+func (p tokenPair) addTo(response gin.H) {
+	response["token"] = p.AccessToken
+	response["refresh_token"] = p.RefreshToken
+	response["expires_in"] = p.ExpiresIn
+}
+
+// AuthHandler registers users, logs them in and out and refreshes their
+// sessions. The password, account, two-factor and admin handlers use its
+// session helpers.
+type AuthHandler struct {
+	users         store.UserStore
+	profiles      store.ProfileStore
+	refreshTokens store.RefreshTokenStore
+	twoFactors    store.TwoFactorStore
+	lockouts      store.LockoutStore
+	revocations   utils.RevocationStore
+}
+
+func NewAuthHandler(users store.UserStore, profiles store.ProfileStore, refreshTokens store.RefreshTokenStore, twoFactors store.TwoFactorStore, lockouts store.LockoutStore, revocations utils.RevocationStore) *AuthHandler {
+	return &AuthHandler{users: users, profiles: profiles, refreshTokens: refreshTokens, twoFactors: twoFactors, lockouts: lockouts, revocations: revocations}
+}
+
+// newSession mints an access token and the next refresh token of a family
+// without storing the refresh token. An empty familyID starts a new family
+// (a new login session).
+func newSession(user models.User, familyID string) (tokenPair, models.RefreshToken, error) {
+	var err error
+	if familyID == "" {
+		if familyID, err = utils.NewID(); err != nil {
+			return tokenPair{}, models.RefreshToken{}, err
+		}
+	}
+
+	accessToken, err := utils.GenerateSessionToken(user.ID, user.Username, user.Role, familyID)
+	if err != nil {
+		return tokenPair{}, models.RefreshToken{}, err
+	}
+
+	refreshToken, refreshHash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return tokenPair{}, models.RefreshToken{}, err
+	}
+
+	record := models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: refreshHash,
+		ExpiresAt: time.Now().Add(utils.RefreshTokenTTL()),
+	}
+	return tokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(utils.AccessTokenTTL().Seconds()),
+	}, record, nil
+}
+
+// issueTokens starts a new login session
+func (h *AuthHandler) issueTokens(user models.User) (tokenPair, error) {
+	tokens, record, err := newSession(user, "")
+	if err != nil {
+		return tokenPair{}, err
+	}
+	if err := h.refreshTokens.Create(&record); err != nil {
+		return tokenPair{}, err
+	}
+	return tokens, nil
+}
+
+// Register - POST /api/auth/register
+func (h *AuthHandler) Register(c *gin.Context) {
 	var registerReq registerRequest
 
 	// Bind and validate JSON
 	if err := c.ShouldBindJSON(&registerReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.BadRequest(c, err)
 		return
 	}
 
 	// Ensure username meets length requirements
 	if len(registerReq.Username) < 6 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Username must be at least 6 characters long."})
+		apierror.Invalid(c, "username", "min", "Username must be at least 6 characters long.")
 		return
 	} else if len(registerReq.Username) > 50 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Username must not exceed 50 characters."})
+		apierror.Invalid(c, "username", "max", "Username must not exceed 50 characters.")
 		return
 	}
 
 	// Ensure password meets length requirements
-	if len(registerReq.Password) < 6 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Password must be at least 6 characters long."})
+	if problem := passwordProblem(registerReq.Password); problem != "" {
+		apierror.Invalid(c, "password", "password", problem)
 		return
 	}
 
 	// Check if username already exists
-	var existingUser models.User
-	if err := db.Where("username = ?", registerReq.Username).First(&existingUser).Error; err == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Username already taken."})
+	if _, err := h.users.GetByUsername(registerReq.Username); err == nil {
+		apierror.Respond(c, http.StatusConflict, apierror.UsernameTaken, "Username already taken.")
+		return
+	} else if !errors.Is(err, store.ErrNotFound) {
+		serverError(c, "Failed to create new user.", err)
 		return
 	}
 
+	// Email is optional, but must be valid and unused when given
+	var email *string
+	if registerReq.Email != "" {
+		normalized, ok := normalizeEmail(registerReq.Email)
+		if !ok {
+			apierror.Invalid(c, "email", "email", "Invalid email address.")
+			return
+		}
+		if _, err := h.users.GetByEmail(normalized); err == nil {
+			apierror.Respond(c, http.StatusConflict, apierror.EmailTaken, "Email already registered.")
+			return
+		} else if !errors.Is(err, store.ErrNotFound) {
+			serverError(c, "Failed to create new user.", err)
+			return
+		}
+		email = &normalized
+	}
+
 	// Hash the password
 	hashedPassword, err := utils.HashPassword(registerReq.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password."})
+		serverError(c, "Failed to hash password.", err)
 		return
 	}
 
 	// Create new user
 	newUser := models.User{
 		Username:     registerReq.Username,
+		Email:        email,
 		PasswordHash: hashedPassword,
 	}
 
 	// Save new user to database
-	if err := db.Create(&newUser).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create new user."})
+	if err := h.users.Create(&newUser); err != nil {
+		serverError(c, "Failed to create new user.", err)
 		return
 	}
 
-	//generate token
-	token, err := utils.GenerateToken(newUser.ID, newUser.Username)
+	//generate tokens
+	tokens, err := h.issueTokens(newUser)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		serverError(c, "Failed to generate token", err)
 		return
 	}
 
-	// Try to get profile if it exists
-	var profile models.HealthProfile
-	response := gin.H{
-		"id":       newUser.ID,
-		"username": newUser.Username,
-		"token":    token,
-	}
-
-	if err := db.Where("user_id = ?", newUser.ID).First(&profile).Error; err == nil {
-		// Profile exists, add profile fields to response
-		if profile.DateOfBirth != nil {
-			response["dateOfBirth"] = profile.DateOfBirth.Format(time.RFC3339)
-		}
-		response["sex"] = profile.Sex
-		response["height"] = profile.HeightCM
-		response["weight"] = profile.WeightKG
-		if profile.NeckCM != nil {
-			response["neck"] = *profile.NeckCM
-		}
-		if profile.WaistCM != nil {
-			response["waist"] = *profile.WaistCM
-		}
-		if profile.HipsCM != nil {
-			response["hips"] = *profile.HipsCM
-		}
+	response := h.loginResponse(newUser, tokens)
+	if newUser.Email != nil {
+		response["email"] = *newUser.Email
 	}
 
 	// Return success response
 	c.JSON(http.StatusCreated, response)
 }
 
-func Login(c *gin.Context, db *gorm.DB) {
+// Login - POST /api/auth/login
+func (h *AuthHandler) Login(c *gin.Context) {
 	var loginReq loginRequest
 
 	// Bind and validate JSON
 	if err := c.ShouldBindJSON(&loginReq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.BadRequest(c, err)
 		return
 	}
 
-	// Find user by username
-	var user models.User
-	if err := db.Where("username = ?", loginReq.Username).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username."})
+	// Check username and password
+	user, ok := h.authenticate(c, loginReq.Username, loginReq.Password)
+	if !ok {
 		return
 	}
 
-	// Check password
-	if !utils.CheckPasswordHash(user.PasswordHash, loginReq.Password) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid password."})
+	// Accounts pending deletion must be restored first
+	if user.DeletionRequestedAt != nil {
+		apierror.Respond(c, http.StatusForbidden, apierror.AccountPendingDeletion, "Account is scheduled for deletion. Restore it to log in.")
 		return
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Username)
+	// With two-factor authentication the password only earns a challenge
+	if enabled, err := h.twoFactorEnabled(user.ID); err != nil {
+		serverError(c, "Failed to log in.", err)
+		return
+	} else if enabled {
+		sendMFAChallenge(c, user)
+		return
+	}
+
+	loginThrottle.Success(user.Username)
+
+	// Generate JWT and refresh tokens
+	tokens, err := h.issueTokens(user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token."})
+		serverError(c, "Failed to generate token.", err)
 		return
 	}
 
-	// Get profile data if it exists
-	var profile models.HealthProfile
+	// Return success response with token
+	c.JSON(http.StatusOK, h.loginResponse(user, tokens))
+}
+
+// loginResponse describes a freshly logged in user, including their
+// profile if they have one
+func (h *AuthHandler) loginResponse(user models.User, tokens tokenPair) gin.H {
 	response := gin.H{
 		"id":       user.ID,
 		"username": user.Username,
-		"token":    token,
 	}
+	tokens.addTo(response)
 
-	if err := db.Where("user_id = ?", user.ID).First(&profile).Error; err == nil {
+	if profile, err := h.profiles.Get(user.ID); err == nil {
 		// Profile exists, add profile fields to response
 		if profile.DateOfBirth != nil {
 			response["dateOfBirth"] = profile.DateOfBirth.Format(time.RFC3339)
@@ -165,6 +264,125 @@ func Login(c *gin.Context, db *gorm.DB) {
 		}
 	}
 
-	// Return success response with token
+	return response
+}
+
+// Refresh - POST /api/auth/refresh
+// Exchanges a refresh token for a new access token and the next refresh
+// token. Presenting a token that was already exchanged means it was copied,
+// so the whole family is revoked and the user has to log in again.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+
+	stored, err := h.refreshTokens.GetByHash(utils.HashToken(req.RefreshToken))
+	if err != nil {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.InvalidRefreshToken, "Invalid refresh token.")
+		return
+	}
+
+	if stored.RevokedAt != nil {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.RefreshTokenRevoked, "Refresh token has been revoked.")
+		return
+	}
+
+	if stored.UsedAt != nil {
+		h.refreshReused(c, stored.FamilyID)
+		return
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.RefreshTokenExpired, "Refresh token has expired.")
+		return
+	}
+
+	user, err := h.users.Get(stored.UserID)
+	if err != nil || !canLogIn(user) {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.InvalidRefreshToken, "Invalid refresh token.")
+		return
+	}
+
+	tokens, next, err := newSession(user, stored.FamilyID)
+	if err != nil {
+		serverError(c, "Failed to refresh token.", err)
+		return
+	}
+	rotated, err := h.refreshTokens.Rotate(stored.ID, &next)
+	if err != nil {
+		serverError(c, "Failed to refresh token.", err)
+		return
+	}
+	if !rotated {
+		// a concurrent refresh with the same token got there first
+		h.refreshReused(c, stored.FamilyID)
+		return
+	}
+
+	response := gin.H{}
+	tokens.addTo(response)
 	c.JSON(http.StatusOK, response)
 }
+
+// refreshReused revokes the session of a refresh token presented twice
+func (h *AuthHandler) refreshReused(c *gin.Context, familyID string) {
+	if err := h.refreshTokens.RevokeFamily(familyID); err != nil {
+		serverError(c, "Failed to revoke session.", err)
+		return
+	}
+	apierror.Respond(c, http.StatusUnauthorized, apierror.RefreshTokenReused, "Refresh token reuse detected. Please log in again.")
+}
+
+// Logout - POST /api/auth/logout
+// Revokes the presented access token and the refresh-token family of its
+// session.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims, ok := middleware.GetClaims(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		if err := h.revocations.RevokeToken(claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+			serverError(c, "Failed to log out.", err)
+			return
+		}
+	}
+
+	if claims.SessionID != "" {
+		if err := h.refreshTokens.RevokeFamily(claims.SessionID); err != nil {
+			serverError(c, "Failed to log out.", err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// LogoutAll - POST /api/auth/logout-all
+// Revokes every access and refresh token the user currently holds.
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	if err := h.revokeSessions(userID); err != nil {
+		serverError(c, "Failed to log out.", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out of all sessions"})
+}
+
+// revokeSessions ends every session of a user
+func (h *AuthHandler) revokeSessions(userID uint) error {
+	if err := h.revocations.RevokeUser(userID, time.Now()); err != nil {
+		return err
+	}
+	return h.refreshTokens.RevokeUser(userID)
+}
@@ -6,283 +6,555 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
-
-	"gorm.io/gorm/logger"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/metrics"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
 )
 
-func setupTestDB(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent), // ← Add this
-	})
-	if err != nil {
-		t.Fatalf("Failed to connect to test database: %v", err)
-	}
+func newAuthHandler(stores store.Stores) *AuthHandler {
+	return NewAuthHandler(stores.Users, stores.Profiles, stores.RefreshTokens, stores.TwoFactors, stores.Lockouts, stores.Revocations)
+}
 
-	err = db.AutoMigrate(&models.User{}, &models.HealthProfile{})
-	if err != nil {
-		t.Fatalf("Failed to migrate test database: %v", err)
-	}
+// setupAuthRouter serves registration, login and refresh from the stores.
+// Failed logins count towards the shared throttle, so parallel tests use
+// usernames of their own.
+func setupAuthRouter(stores store.Stores) *gin.Engine {
+	return authRoutes(newAuthHandler(stores))
+}
 
-	return db
+func authRoutes(auth *AuthHandler) *gin.Engine {
+	router := gin.New()
+	router.POST("/register", auth.Register)
+	router.POST("/login", auth.Login)
+	router.POST("/refresh", auth.Refresh)
+	return router
+}
+
+// setupSessionRouter adds logging out and a protected /me to the auth
+// routes. AuthMiddleware checks stores.Revocations until the test ends, so
+// tests using it cannot run in parallel.
+func setupSessionRouter(t *testing.T, stores store.Stores) (*gin.Engine, *AuthHandler) {
+	previous := middleware.Revocations()
+	middleware.SetRevocationStore(stores.Revocations)
+	t.Cleanup(func() { middleware.SetRevocationStore(previous) })
+
+	// failed logins from earlier tests must not lock out this one
+	SetLoginThrottle(utils.NewLoginThrottleFromConfig(config.Default().Auth.Lockout))
+
+	auth := newAuthHandler(stores)
+	router := authRoutes(auth)
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.POST("/logout", auth.Logout)
+	protected.POST("/logout-all", auth.LogoutAll)
+	protected.GET("/me", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	return router, auth
 }
 
 func TestRegister_Success(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupTestDB(t)
-	router := gin.New()
-	router.POST("/register", func(c *gin.Context) {
-		Register(c, db)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAuthRouter(stores)
+
+		// create request
+		w := jsonRequest(router, "POST", "/register", "", map[string]string{
+			"username": "testuser",
+			"password": "password123",
+			"email":    "Test@Example.com",
+		})
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response["token"] == nil {
+			t.Error("Expected token in response")
+		}
+
+		if response["username"] != "testuser" {
+			t.Errorf("Expected username 'testuser', got %v", response["username"])
+		}
+
+		if response["id"] == nil {
+			t.Error("Expected id in response")
+		}
+
+		if response["email"] != "test@example.com" {
+			t.Errorf("Expected normalized email, got %v", response["email"])
+		}
+
+		user, err := stores.Users.GetByUsername("testuser")
+		if err != nil || !utils.CheckPasswordHash(user.PasswordHash, "password123") {
+			t.Errorf("Expected the user to be stored with a password hash, got %+v, %v", user, err)
+		}
 	})
-
-	// create request
-	body := map[string]string{
-		"username": "testuser",
-		"password": "password123",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusCreated {
-		t.Errorf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	if response["token"] == nil {
-		t.Error("Expected token in response")
-	}
-
-	if response["username"] != "testuser" {
-		t.Errorf("Expected username 'testuser', got %v", response["username"])
-	}
-
-	if response["id"] == nil {
-		t.Error("Expected id in response")
-	}
 }
 
 func TestRegister_DuplicateUsername(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupTestDB(t)
-
-	// create existing user
-	existingUser := models.User{
-		Username:     "testuser",
-		PasswordHash: "hash",
-	}
-	db.Create(&existingUser)
-
-	router := gin.New()
-	router.POST("/register", func(c *gin.Context) {
-		Register(c, db)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		// create existing user
+		seed(t, stores, &models.User{
+			Username:     "testuser",
+			PasswordHash: "hash",
+		})
+
+		router := setupAuthRouter(stores)
+
+		// request with duplicate username
+		w := jsonRequest(router, "POST", "/register", "", map[string]string{
+			"username": "testuser",
+			"password": "password123",
+		})
+
+		if w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d", w.Code)
+		}
+
+		var response map[string]string
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response["message"] != "Username already taken." {
+			t.Errorf("Expected 'Username already taken.' error, got %s", response["message"])
+		}
 	})
+}
 
-	// request with duplicate username
-	body := map[string]string{
-		"username": "testuser",
-		"password": "password123",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusConflict {
-		t.Errorf("Expected status 409, got %d", w.Code)
-	}
-
-	var response map[string]string
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	if response["error"] != "Username already taken." {
-		t.Errorf("Expected 'Username already taken.' error, got %s", response["error"])
-	}
+func TestRegister_DuplicateEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		email := "test@example.com"
+		seed(t, stores, &models.User{Username: "existing", Email: &email, PasswordHash: "hash"})
+		router := setupAuthRouter(stores)
+
+		w := jsonRequest(router, "POST", "/register", "", map[string]string{
+			"username": "newcomer",
+			"password": "password123",
+			"email":    "TEST@example.com",
+		})
+
+		if w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d", w.Code)
+		}
+	})
 }
 
 func TestRegister_ShortUsername(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupTestDB(t)
-	router := gin.New()
-	router.POST("/register", func(c *gin.Context) {
-		Register(c, db)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAuthRouter(stores)
+
+		// request with short username
+		w := jsonRequest(router, "POST", "/register", "", map[string]string{
+			"username": "user",
+			"password": "password123",
+		})
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
 	})
+}
 
-	// request with short username
-	body := map[string]string{
-		"username": "user",
-		"password": "password123",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
+func TestRegister_ShortPassword(t *testing.T) {
+	// setup
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAuthRouter(stores)
+
+		// request with short password
+		w := jsonRequest(router, "POST", "/register", "", map[string]string{
+			"username": "testuser",
+			"password": "pass",
+		})
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
 
-	router.ServeHTTP(w, req)
+func TestLogin_Success(t *testing.T) {
+	// setup
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAuthRouter(stores)
+
+		// register user
+		registered := registerTestUser(t, router, "loginuser")
+		userID := uint(registered["id"].(float64))
+		seed(t, stores, &models.HealthProfile{UserID: userID, Sex: "female", HeightCM: 165, WeightKG: 60})
+
+		// login
+		w := postLogin(router, "loginuser", "password123")
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response["token"] == nil || response["refresh_token"] == nil {
+			t.Error("Expected tokens in response")
+		}
+		if response["username"] != "loginuser" {
+			t.Errorf("Expected username 'loginuser', got %v", response["username"])
+		}
+		if response["id"] == nil {
+			t.Error("Expected id in response")
+		}
+		if response["sex"] != "female" || response["height"] != 165.0 {
+			t.Errorf("Expected profile fields in response, got %v", response)
+		}
+	})
+}
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", w.Code)
-	}
+func TestLogin_TwoFactorChallenge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAuthRouter(stores)
+
+		registered := registerTestUser(t, router, "mfauser")
+		userID := uint(registered["id"].(float64))
+		if err := stores.TwoFactors.Save(&models.TwoFactor{UserID: userID, Secret: "SECRET", Enabled: true}); err != nil {
+			t.Fatalf("Failed to enable two-factor authentication: %v", err)
+		}
+
+		w := postLogin(router, "mfauser", "password123")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if response["mfa_required"] != true || response["token"] != nil {
+			t.Errorf("Expected only a challenge, got %v", response)
+		}
+	})
 }
 
-func TestRegister_ShortPassword(t *testing.T) {
+// not parallel: it counts a metric other login tests also increment
+func TestLogin_WrongPassword(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupTestDB(t)
-	router := gin.New()
-	router.POST("/register", func(c *gin.Context) {
-		Register(c, db)
-	})
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAuthRouter(stores)
 
-	// request with short password
-	body := map[string]string{
-		"username": "testuser",
-		"password": "pass",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
+		// register user
+		registerTestUser(t, router, "wrongpassuser")
 
-	router.ServeHTTP(w, req)
+		// try login with wrong password
+		failures := metrics.AuthFailures.WithLabelValues(metrics.SourceLogin, "bad_credentials")
+		before := testutil.ToFloat64(failures)
 
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", w.Code)
-	}
+		w := postLogin(router, "wrongpassuser", "wrongpassword")
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+		if got := testutil.ToFloat64(failures) - before; got != 1 {
+			t.Errorf("Expected one bad_credentials failure to be counted, got %v", got)
+		}
+	})
 }
 
-func TestLogin_Success(t *testing.T) {
+func TestLogin_NonexistentUser(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupTestDB(t)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAuthRouter(stores)
 
-	// create a user first
-	router := gin.New()
-	router.POST("/register", func(c *gin.Context) {
-		Register(c, db)
-	})
-	router.POST("/login", func(c *gin.Context) {
-		Login(c, db)
+		// try login with nonexistent user
+		w := postLogin(router, "nonexistent", "password123")
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
 	})
+}
 
-	// register user
-	regBody := map[string]string{
-		"username": "testuser",
-		"password": "password123",
-	}
-	jsonBody, _ := json.Marshal(regBody)
-	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(jsonBody))
+func postLogin(router *gin.Engine, username, password string) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(map[string]string{"username": username, "password": password})
+	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(jsonBody))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestLogin_UniformErrorMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAuthRouter(stores)
+		registerTestUser(t, router, "uniformuser")
+
+		wrongPassword := postLogin(router, "uniformuser", "wrongpassword")
+		unknownUser := postLogin(router, "nobodyhere", "wrongpassword")
+
+		if wrongPassword.Code != http.StatusUnauthorized || unknownUser.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected 401 for both, got %d and %d", wrongPassword.Code, unknownUser.Code)
+		}
+		if wrongPassword.Body.String() != unknownUser.Body.String() {
+			t.Errorf("Responses differ: %s vs %s", wrongPassword.Body.String(), unknownUser.Body.String())
+		}
+	})
+}
 
-	// login
-	loginBody := map[string]string{
-		"username": "testuser",
+// not parallel: it replaces the shared login throttle
+func TestLogin_LocksOutAfterRepeatedFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		SetLoginThrottle(utils.NewLoginThrottleFromConfig(config.Default().Auth.Lockout))
+		router := setupAuthRouter(stores)
+		registerTestUser(t, router, "testuser")
+
+		free := config.Default().Auth.Lockout.FreeAttempts
+		for i := 0; i <= free; i++ {
+			if w := postLogin(router, "testuser", "wrongpassword"); w.Code != http.StatusUnauthorized {
+				t.Fatalf("Attempt %d: expected 401, got %d", i+1, w.Code)
+			}
+		}
+
+		// even the right password is refused while locked
+		w := postLogin(router, "testuser", "password123")
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected 429, got %d", w.Code)
+		}
+		if w.Header().Get("Retry-After") == "" {
+			t.Error("Expected a Retry-After header")
+		}
+
+		events, err := stores.Lockouts.List(0, 10)
+		if err != nil || len(events) != 1 || events[0].Scope != "username" || events[0].Username != "testuser" {
+			t.Errorf("Expected one username lockout event, got %+v", events)
+		}
+
+		// later tests start without the lockout
+		SetLoginThrottle(utils.NewLoginThrottleFromConfig(config.Default().Auth.Lockout))
+	})
+}
+
+// registers a user through the handler and returns the response body
+func registerTestUser(t *testing.T, router *gin.Engine, username string) map[string]interface{} {
+	body, _ := json.Marshal(map[string]string{
+		"username": username,
 		"password": "password123",
-	}
-	jsonBody, _ = json.Marshal(loginBody)
-	req = httptest.NewRequest("POST", "/login", bytes.NewBuffer(jsonBody))
+	})
+	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
-	w = httptest.NewRecorder()
-
+	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Failed to register test user: %d %s", w.Code, w.Body.String())
 	}
 
 	var response map[string]interface{}
 	json.Unmarshal(w.Body.Bytes(), &response)
-
-	if response["token"] == nil {
-		t.Error("Expected token in response")
-	}
-	if response["username"] != "testuser" {
-		t.Errorf("Expected username 'testuser', got %v", response["username"])
-	}
-	if response["id"] == nil {
-		t.Error("Expected id in response")
-	}
+	return response
 }
 
-func TestLogin_WrongPassword(t *testing.T) {
-	// setup
-	gin.SetMode(gin.TestMode)
-	db := setupTestDB(t)
-	router := gin.New()
-	router.POST("/register", func(c *gin.Context) {
-		Register(c, db)
-	})
-	router.POST("/login", func(c *gin.Context) {
-		Login(c, db)
-	})
-
-	// register user
-	regBody := map[string]string{
-		"username": "testuser",
-		"password": "password123",
-	}
-	jsonBody, _ := json.Marshal(regBody)
-	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(jsonBody))
+func postRefresh(router *gin.Engine, refreshToken string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]string{"refresh_token": refreshToken})
+	req := httptest.NewRequest("POST", "/refresh", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
+	return w
+}
 
-	// try login with wrong password
-	loginBody := map[string]string{
-		"username": "testuser",
-		"password": "wrongpassword",
-	}
-	jsonBody, _ = json.Marshal(loginBody)
-	req = httptest.NewRequest("POST", "/login", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
-	w = httptest.NewRecorder()
+func TestRefresh_RotatesToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAuthRouter(stores)
+
+		registered := registerTestUser(t, router, "testuser")
+		refreshToken, _ := registered["refresh_token"].(string)
+		if refreshToken == "" {
+			t.Fatal("Expected refresh_token in register response")
+		}
+
+		w := postRefresh(router, refreshToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response["token"] == nil {
+			t.Error("Expected new access token in response")
+		}
+		next, _ := response["refresh_token"].(string)
+		if next == "" || next == refreshToken {
+			t.Fatalf("Expected a new refresh token, got %v", response["refresh_token"])
+		}
+
+		// the new token belongs to the same family as the old one
+		first, _ := stores.RefreshTokens.GetByHash(utils.HashToken(refreshToken))
+		second, err := stores.RefreshTokens.GetByHash(utils.HashToken(next))
+		if err != nil || first.FamilyID != second.FamilyID {
+			t.Errorf("Expected two tokens in one family, got %+v and %+v", first, second)
+		}
+		if first.UsedAt == nil {
+			t.Error("Expected first refresh token to be marked used")
+		}
+	})
+}
 
-	router.ServeHTTP(w, req)
+func TestRefresh_ReuseRevokesFamily(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAuthRouter(stores)
+
+		registered := registerTestUser(t, router, "testuser")
+		stolen := registered["refresh_token"].(string)
+
+		// legitimate client rotates
+		w := postRefresh(router, stolen)
+		var rotated map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &rotated)
+		current := rotated["refresh_token"].(string)
+
+		// attacker replays the stale token
+		w = postRefresh(router, stolen)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for replayed token, got %d", w.Code)
+		}
+
+		// the legitimate client's current token is now revoked too
+		w = postRefresh(router, current)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 after family revocation, got %d", w.Code)
+		}
+
+		for _, token := range []string{stolen, current} {
+			if stored, _ := stores.RefreshTokens.GetByHash(utils.HashToken(token)); stored.RevokedAt == nil {
+				t.Error("Expected every token in the family to be revoked")
+			}
+		}
+	})
+}
 
-	if w.Code != http.StatusUnauthorized {
-		t.Errorf("Expected status 401, got %d", w.Code)
-	}
+func TestRefresh_UnknownToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAuthRouter(stores)
+
+		w := postRefresh(router, "not-a-real-token")
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+	})
 }
 
-func TestLogin_NonexistentUser(t *testing.T) {
-	// setup
+func TestRefresh_ExpiredToken(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupTestDB(t)
-	router := gin.New()
-	router.POST("/login", func(c *gin.Context) {
-		Login(c, db)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupAuthRouter(stores)
+		createTestUser(t, stores, 1, "testuser")
+
+		token, hash, _ := utils.GenerateOpaqueToken()
+		seed(t, stores, &models.RefreshToken{UserID: 1, FamilyID: "family", TokenHash: hash, ExpiresAt: time.Now().Add(-time.Minute)})
+
+		w := postRefresh(router, token)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for expired token, got %d", w.Code)
+		}
 	})
+}
 
-	// try login with nonexistent user
-	loginBody := map[string]string{
-		"username": "nonexistent",
-		"password": "password123",
-	}
-	jsonBody, _ := json.Marshal(loginBody)
-	req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Content-Type", "application/json")
+func authedRequest(router *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
 	w := httptest.NewRecorder()
-
 	router.ServeHTTP(w, req)
+	return w
+}
 
-	if w.Code != http.StatusUnauthorized {
-		t.Errorf("Expected status 401, got %d", w.Code)
-	}
+func TestLogout_RevokesTokenAndSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router, _ := setupSessionRouter(t, stores)
+
+		session := registerTestUser(t, router, "testuser")
+		token := session["token"].(string)
+
+		if w := authedRequest(router, "POST", "/logout", token); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		// the access token no longer works
+		if w := authedRequest(router, "GET", "/me", token); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 after logout, got %d", w.Code)
+		}
+
+		// neither does the session's refresh token
+		if w := postRefresh(router, session["refresh_token"].(string)); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected refresh to fail after logout, got %d", w.Code)
+		}
+	})
+}
+
+func TestLogoutAll_RevokesEverySession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router, auth := setupSessionRouter(t, stores)
+
+		first := registerTestUser(t, router, "testuser")
+		other := registerTestUser(t, router, "otheruser")
+
+		// second session for the same user
+		user, _ := stores.Users.GetByUsername("testuser")
+		second, err := auth.issueTokens(user)
+		if err != nil {
+			t.Fatalf("Failed to issue second session: %v", err)
+		}
+
+		if w := authedRequest(router, "POST", "/logout-all", first["token"].(string)); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		if w := authedRequest(router, "GET", "/me", second.AccessToken); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected other session's token to be revoked, got %d", w.Code)
+		}
+		if w := postRefresh(router, second.RefreshToken); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected other session's refresh token to be revoked, got %d", w.Code)
+		}
+
+		// other users are unaffected
+		if w := authedRequest(router, "GET", "/me", other["token"].(string)); w.Code != http.StatusOK {
+			t.Errorf("Expected other user's token to still work, got %d", w.Code)
+		}
+	})
 }
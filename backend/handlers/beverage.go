@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+type createBeverageRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+	// HydrationFactor is how much of the volume counts towards the water
+	// goal, from 0 to 2
+	HydrationFactor    *float64 `json:"hydration_factor" binding:"required,gte=0,lte=2"`
+	CaffeineMGPer100ML float64  `json:"caffeine_mg_per_100ml" binding:"gte=0,lte=1000"`
+	KcalPer100ML       float64  `json:"kcal_per_100ml" binding:"gte=0,lte=1000"`
+}
+
+// BeverageHandler serves the beverage catalog water logs refer to
+type BeverageHandler struct {
+	beverages store.BeverageStore
+}
+
+func NewBeverageHandler(beverages store.BeverageStore) *BeverageHandler {
+	return &BeverageHandler{beverages: beverages}
+}
+
+// ListBeverages - GET /api/water/beverages
+func (h *BeverageHandler) ListBeverages(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	beverages, err := h.beverages.List(userID)
+	if err != nil {
+		serverError(c, "Failed to fetch beverages", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, beverages)
+}
+
+// CreateBeverage - POST /api/water/beverages
+func (h *BeverageHandler) CreateBeverage(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	var req createBeverageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		apierror.Invalid(c, "name", "required", "name is required")
+		return
+	}
+
+	// names pick beverages in the app, so they must not repeat
+	existing, err := h.beverages.List(userID)
+	if err != nil {
+		serverError(c, "Failed to fetch beverages", err)
+		return
+	}
+	for _, beverage := range existing {
+		if strings.EqualFold(beverage.Name, req.Name) {
+			apierror.Respond(c, http.StatusConflict, apierror.BeverageNameTaken, "A beverage with that name already exists")
+			return
+		}
+	}
+
+	beverage := models.Beverage{
+		UserID:             &userID,
+		Name:               req.Name,
+		HydrationFactor:    *req.HydrationFactor,
+		CaffeineMGPer100ML: req.CaffeineMGPer100ML,
+		KcalPer100ML:       req.KcalPer100ML,
+	}
+	if err := h.beverages.Create(&beverage); err != nil {
+		serverError(c, "Failed to create beverage", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, beverage)
+}
+
+// DeleteBeverage - DELETE /api/water/beverages/:id
+// Only the user's own beverages can be deleted; their logs are kept.
+func (h *BeverageHandler) DeleteBeverage(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.BeverageNotFound, "Beverage not found")
+		return
+	}
+
+	err = h.beverages.Delete(userID, uint(id))
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Respond(c, http.StatusNotFound, apierror.BeverageNotFound, "Beverage not found")
+		return
+	}
+	if err != nil {
+		serverError(c, "Failed to delete beverage", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Beverage deleted successfully"})
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+func setupBeverageRouter(stores store.Stores) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	beverages := NewBeverageHandler(stores.Beverages)
+	water := NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages)
+
+	router := gin.New()
+	router.Use(middleware.AuthMiddleware())
+	router.GET("/water/beverages", beverages.ListBeverages)
+	router.POST("/water/beverages", beverages.CreateBeverage)
+	router.DELETE("/water/beverages/:id", beverages.DeleteBeverage)
+	router.POST("/water", water.LogWaterIntake)
+	router.GET("/water/summary", water.GetDailySummary)
+	return router
+}
+
+func sendJSON(router *gin.Engine, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// builtinBeverage finds a built-in beverage by name
+func builtinBeverage(t *testing.T, stores store.Stores, name string) models.Beverage {
+	t.Helper()
+	catalog, _ := stores.Beverages.List(0)
+	for _, beverage := range catalog {
+		if beverage.Name == name {
+			return beverage
+		}
+	}
+	t.Fatalf("No built-in beverage %q", name)
+	return models.Beverage{}
+}
+
+func TestBeverages_CreateListDelete(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		router := setupBeverageRouter(stores)
+
+		w := sendJSON(router, "POST", "/water/beverages", token, `{"name": "Oat latte", "hydration_factor": 0.9, "caffeine_mg_per_100ml": 30, "kcal_per_100ml": 50}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var created models.Beverage
+		json.Unmarshal(w.Body.Bytes(), &created)
+		if created.UserID == nil || *created.UserID != 1 || created.HydrationFactor != 0.9 {
+			t.Errorf("Expected the user's own beverage, got %+v", created)
+		}
+
+		if w := sendJSON(router, "POST", "/water/beverages", token, `{"name": "coffee", "hydration_factor": 1}`); w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409 for the name of a built-in beverage, got %d", w.Code)
+		}
+
+		w = sendJSON(router, "GET", "/water/beverages", token, "")
+		var catalog []models.Beverage
+		json.Unmarshal(w.Body.Bytes(), &catalog)
+		if len(catalog) != len(store.BuiltinBeverages())+1 {
+			t.Errorf("Expected the built-in beverages and the new one, got %d", len(catalog))
+		}
+
+		coffee := builtinBeverage(t, stores, "Coffee")
+		if w := sendJSON(router, "DELETE", fmt.Sprintf("/water/beverages/%d", coffee.ID), token, ""); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for a built-in beverage, got %d", w.Code)
+		}
+		if w := sendJSON(router, "DELETE", fmt.Sprintf("/water/beverages/%d", created.ID), token, ""); w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+	})
+}
+
+func TestCreateBeverage_Invalid(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		router := setupBeverageRouter(stores)
+
+		tests := []struct {
+			body  string
+			field string
+		}{
+			{`{"name": "Kombucha"}`, "hydration_factor"},
+			{`{"name": "Kombucha", "hydration_factor": 3}`, "hydration_factor"},
+			{`{"name": "Kombucha", "hydration_factor": 1, "caffeine_mg_per_100ml": -1}`, "caffeine_mg_per_100ml"},
+			{`{"name": "  ", "hydration_factor": 1}`, "name"},
+		}
+		for _, tt := range tests {
+			w := sendJSON(router, "POST", "/water/beverages", token, tt.body)
+			var response apierror.Error
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if w.Code != http.StatusBadRequest || len(response.Details) != 1 || response.Details[0].Field != tt.field {
+				t.Errorf("%s: expected a validation error on %s, got %d %+v", tt.body, tt.field, w.Code, response)
+			}
+		}
+	})
+}
+
+func TestLogWaterIntake_Beverage(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		limit := 150
+		seed(t, stores, &models.HealthProfile{UserID: 1, Sex: "female", HeightCM: 165, WeightKG: 60, CaffeineLimitMG: &limit})
+		router := setupBeverageRouter(stores)
+		coffee := builtinBeverage(t, stores, "Coffee")
+
+		w := sendJSON(router, "POST", "/water", token, fmt.Sprintf(`{"amount_ml": 250, "beverage_id": %d}`, coffee.ID))
+		var logged models.WaterIntake
+		json.Unmarshal(w.Body.Bytes(), &logged)
+		if w.Code != http.StatusCreated || logged.HydrationML != 225 || logged.CaffeineMG != 100 || logged.Kcal != 5 {
+			t.Fatalf("Expected 250ml of coffee to hydrate 225ml with 100mg caffeine, got %d %+v", w.Code, logged)
+		}
+		sendJSON(router, "POST", "/water", token, `{"amount_ml": 500}`)
+
+		w = sendJSON(router, "GET", "/water/summary", token, "")
+		var summary models.WaterIntakeSummary
+		json.Unmarshal(w.Body.Bytes(), &summary)
+		if summary.TotalML != 750 || summary.HydrationML != 725 || summary.CaffeineMG != 100 {
+			t.Errorf("Expected 750ml hydrating 725ml with 100mg caffeine, got %+v", summary)
+		}
+		if summary.CaffeineLimitMG != 150 || summary.CaffeineOverLimit {
+			t.Errorf("Expected 100mg to be within the 150mg limit, got %+v", summary)
+		}
+
+		sendJSON(router, "POST", "/water", token, fmt.Sprintf(`{"amount_ml": 250, "beverage_id": %d}`, coffee.ID))
+		w = sendJSON(router, "GET", "/water/summary", token, "")
+		json.Unmarshal(w.Body.Bytes(), &summary)
+		if summary.CaffeineMG != 200 || !summary.CaffeineOverLimit {
+			t.Errorf("Expected 200mg to be over the limit, got %+v", summary)
+		}
+
+		w = sendJSON(router, "POST", "/water", token, `{"amount_ml": 250, "beverage_id": 9999}`)
+		var response apierror.Error
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if w.Code != http.StatusBadRequest || len(response.Details) != 1 || response.Details[0].Field != "beverage_id" {
+			t.Errorf("Expected a validation error on beverage_id, got %d %+v", w.Code, response)
+		}
+	})
+}
@@ -5,42 +5,43 @@ import (
 
 	"net/http"
 
-	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
-	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
 )
 
-func CalculateCalorieGoal(c *gin.Context) {
+type calorieGoalRequest struct {
+	TargetDirection string `json:"target_direction" binding:"required"` // "lose", "hold", or "gain"
+}
+
+func (h *ProfileHandler) CalculateCalorieGoal(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
 		return
 	}
 
-	var req struct {
-		TargetDirection string `json:"target_direction" binding:"required"` // "lose", "hold", or "gain"
-	}
+	var req calorieGoalRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.BadRequest(c, err)
 		return
 	}
 
 	if req.TargetDirection != "lose" && req.TargetDirection != "hold" && req.TargetDirection != "gain" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "target_direction must be 'lose', 'hold', or 'gain'"})
+		apierror.Invalid(c, "target_direction", "oneof", "target_direction must be 'lose', 'hold', or 'gain'")
 		return
 	}
 
 	// get activity level from health profile
-	var profile models.HealthProfile
-	if err := database.DB.Where("user_id = ?", userID).First(&profile).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve health profile"})
+	profile, err := h.profiles.Get(userID)
+	if err != nil {
+		serverError(c, "Failed to retrieve health profile", err)
 		return
 	}
 
 	tdee := utils.CalculateTDEE(
-		utils.CalculateBMR(profile.WeightKG, profile.HeightCM, utils.CalculateAge(profile.DateOfBirth), profile.Sex),
+		utils.CalculateBMR(profile.WeightKG, profile.HeightCM, utils.ProfileAge(&profile), profile.Sex),
 		profile.ActivityLevel,
 	)
 
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+)
+
+// serverError logs what went wrong and answers with a generic message. The
+// client gets the request ID to quote instead of the error itself, which
+// may reveal SQL or internal state.
+func serverError(c *gin.Context, message string, err error) {
+	middleware.Logger(c).Error(message, "error", err)
+	apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, message)
+}
+
+// NoRoute answers a path no route matches
+func NoRoute(c *gin.Context) {
+	apierror.Respond(c, http.StatusNotFound, apierror.NotFound, "No such endpoint.")
+}
+
+// NoMethod answers a method the path does not support. Gin has already
+// set the Allow header.
+func NoMethod(c *gin.Context) {
+	apierror.Respond(c, http.StatusMethodNotAllowed, apierror.MethodNotAllowed, "Method not allowed for this endpoint.")
+}
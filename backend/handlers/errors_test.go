@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+// brokenWaterStore fails every List the way a database driver would
+type brokenWaterStore struct {
+	store.WaterStore
+}
+
+func (brokenWaterStore) List(userID uint, from, to time.Time) ([]models.WaterIntake, error) {
+	return nil, errors.New(`pq: relation "water_intakes" does not exist`)
+}
+
+func TestServerError_HidesInternalError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		router := gin.New()
+		router.Use(middleware.RequestID(), middleware.AuthMiddleware())
+		router.GET("/water", NewWaterHandler(brokenWaterStore{stores.Water}, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).GetWaterIntakeLogs)
+
+		req := httptest.NewRequest("GET", "/water", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set(middleware.RequestIDHeader, "req-500")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected status 500, got %d", w.Code)
+		}
+		if strings.Contains(w.Body.String(), "water_intakes") {
+			t.Errorf("Response leaks the database error: %s", w.Body.String())
+		}
+
+		var response map[string]string
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if response["code"] != apierror.InternalError || response["message"] != "Failed to fetch water logs" || response["request_id"] != "req-500" {
+			t.Errorf("Unexpected response: %v", response)
+		}
+	})
+}
+
+func TestBindingError_ReportsFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		router := gin.New()
+		router.Use(middleware.RequestID(), middleware.AuthMiddleware())
+		router.POST("/water", NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).LogWaterIntake)
+
+		req := httptest.NewRequest("POST", "/water", strings.NewReader(`{"logged_at": "2026-01-01T08:00:00Z"}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400, got %d", w.Code)
+		}
+		if strings.Contains(w.Body.String(), "logWaterRequest") {
+			t.Errorf("Response leaks validator internals: %s", w.Body.String())
+		}
+
+		var response apierror.Error
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if response.Code != apierror.ValidationFailed || response.RequestID == "" {
+			t.Errorf("Unexpected response: %+v", response)
+		}
+		if len(response.Details) != 1 || response.Details[0].Field != "amount_ml" || response.Details[0].Rule != "required" {
+			t.Errorf("Expected amount_ml to be reported as required, got %+v", response.Details)
+		}
+	})
+}
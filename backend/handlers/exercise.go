@@ -6,38 +6,51 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/metrics"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
 )
 
-func LogExercise(c *gin.Context) {
+type logExerciseRequest struct {
+	Type           string    `json:"type" binding:"required"`     // non-empty
+	Duration       int       `json:"duration" binding:"required"` // in minutes
+	CaloriesBurned int       `json:"calories_burned" binding:"required"`
+	LoggedAt       time.Time `json:"logged_at"`
+}
+
+// ExerciseHandler serves the exercise log
+type ExerciseHandler struct {
+	exercises store.ExerciseStore
+}
+
+func NewExerciseHandler(exercises store.ExerciseStore) *ExerciseHandler {
+	return &ExerciseHandler{exercises: exercises}
+}
+
+func (h *ExerciseHandler) LogExercise(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
 		return
 	}
 
-	var req struct {
-		Type           string    `json:"type" binding:"required"`     // non-empty
-		Duration       int       `json:"duration" binding:"required"` // in minutes
-		CaloriesBurned int       `json:"calories_burned" binding:"required"`
-		LoggedAt       time.Time `json:"logged_at"`
-	}
+	var req logExerciseRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.BadRequest(c, err)
 		return
 	}
 
 	// Validation
 	if req.Duration <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Duration must be positive"})
+		apierror.Invalid(c, "duration", "gt", "Duration must be positive")
 		return
 	}
 
 	if req.CaloriesBurned < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Calories burned cannot be negative"})
+		apierror.Invalid(c, "calories_burned", "gte", "Calories burned cannot be negative")
 		return
 	}
 
@@ -46,7 +59,7 @@ func LogExercise(c *gin.Context) {
 	}
 
 	if req.LoggedAt.After(time.Now()) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot log future exercise"})
+		apierror.Invalid(c, "logged_at", "past", "Cannot log future exercise")
 		return
 	}
 
@@ -58,24 +71,25 @@ func LogExercise(c *gin.Context) {
 		LoggedAt:       req.LoggedAt,
 	}
 
-	if err := database.DB.Create(&exerciseLog).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log exercise"})
+	if err := h.exercises.Create(&exerciseLog); err != nil {
+		serverError(c, "Failed to log exercise", err)
 		return
 	}
+	metrics.LogCreated(metrics.KindExercise)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Exercise logged successfully"})
 }
 
-func GetExerciseLogs(c *gin.Context) {
+func (h *ExerciseHandler) GetExerciseLogs(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
 		return
 	}
 
-	var exerciseLogs []models.ExerciseLog
-	if err := database.DB.Where("user_id = ?", userID).Order("logged_at desc").Limit(30).Find(&exerciseLogs).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve exercise logs"})
+	exerciseLogs, err := h.exercises.Recent(userID, 30)
+	if err != nil {
+		serverError(c, "Failed to retrieve exercise logs", err)
 		return
 	}
 
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+)
+
+// readinessTimeout bounds the database ping so a hung database fails the
+// probe instead of stalling it
+const readinessTimeout = 2 * time.Second
+
+// HealthHandler answers the liveness and readiness probes
+type HealthHandler struct {
+	ping     func(ctx context.Context) error
+	draining atomic.Bool
+}
+
+// NewHealthHandler takes the check readiness depends on, normally a
+// database ping
+func NewHealthHandler(ping func(ctx context.Context) error) *HealthHandler {
+	return &HealthHandler{ping: ping}
+}
+
+// Drain makes readiness fail from now on, so load balancers stop sending
+// new requests while the server shuts down
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// Live - GET /health/live
+// The process is up and serving requests.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "OK"})
+}
+
+// Ready - GET /health/ready
+// The server can handle traffic: it is not shutting down and the database
+// answers.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if h.draining.Load() {
+		apierror.Respond(c, http.StatusServiceUnavailable, apierror.ServiceUnavailable, "Server is shutting down.")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+	if err := h.ping(ctx); err != nil {
+		apierror.Respond(c, http.StatusServiceUnavailable, apierror.ServiceUnavailable, "Database unreachable.")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "OK"})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+)
+
+func setupHealthRouter(h *HealthHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/health/live", h.Live)
+	router.GET("/health/ready", h.Ready)
+	return router
+}
+
+func getStatus(router *gin.Engine, path string) int {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	return w.Code
+}
+
+func TestHealth_ReadyPingsDatabase(t *testing.T) {
+	t.Parallel()
+	var pingErr error
+	router := setupHealthRouter(NewHealthHandler(func(ctx context.Context) error { return pingErr }))
+
+	if code := getStatus(router, "/health/ready"); code != http.StatusOK {
+		t.Errorf("Expected ready with a healthy database, got %d", code)
+	}
+
+	pingErr = errors.New("connection refused")
+	if code := getStatus(router, "/health/ready"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 when the database is down, got %d", code)
+	}
+	if code := getStatus(router, "/health/live"); code != http.StatusOK {
+		t.Errorf("Expected liveness not to depend on the database, got %d", code)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/health/ready", nil))
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response["code"] != apierror.ServiceUnavailable {
+		t.Errorf("Expected code %s, got %v", apierror.ServiceUnavailable, response["code"])
+	}
+}
+
+func TestHealth_DrainFailsReadiness(t *testing.T) {
+	t.Parallel()
+	h := NewHealthHandler(func(ctx context.Context) error { return nil })
+	router := setupHealthRouter(h)
+
+	h.Drain()
+
+	if code := getStatus(router, "/health/ready"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 while draining, got %d", code)
+	}
+	if code := getStatus(router, "/health/live"); code != http.StatusOK {
+		t.Errorf("Expected live while draining, got %d", code)
+	}
+}
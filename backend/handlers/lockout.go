@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/metrics"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// loginThrottle tracks failed password checks; main replaces it with one
+// built from the configuration
+var loginThrottle = utils.NewLoginThrottleFromConfig(config.Default().Auth.Lockout)
+
+// SetLoginThrottle replaces the throttle used by the login endpoints
+func SetLoginThrottle(throttle *utils.LoginThrottle) {
+	loginThrottle = throttle
+}
+
+// LoginThrottle returns the throttle used by the login endpoints
+func LoginThrottle() *utils.LoginThrottle {
+	return loginThrottle
+}
+
+// authenticate checks a username and password for the login endpoints.
+// Unknown usernames and wrong passwords get the same response and cost the
+// same bcrypt work, and every failure counts towards a lockout of both the
+// username and the client IP. On failure the response has been written;
+// on success the caller clears the failures with loginThrottle.Success.
+func (h *AuthHandler) authenticate(c *gin.Context, username, password string) (models.User, bool) {
+	if loginLocked(c, username) {
+		return models.User{}, false
+	}
+
+	user, err := h.users.GetByUsername(username)
+	found := err == nil
+	if found && utils.CheckPasswordHash(user.PasswordHash, password) {
+		// only reveal why an account is blocked to someone who knows its
+		// password
+		if blockedLogin(c, user) {
+			return models.User{}, false
+		}
+		// failures are only cleared once a session is issued, so a known
+		// password does not reset the count for second-factor guesses
+		return user, true
+	}
+	if !found {
+		utils.SimulatePasswordCheck(password)
+	}
+
+	h.recordLoginFailure(c, username)
+	metrics.AuthFailure(metrics.SourceLogin, "bad_credentials")
+	apierror.Respond(c, http.StatusUnauthorized, apierror.InvalidCredentials, "Invalid username or password.")
+	return models.User{}, false
+}
+
+// canLogIn reports whether an admin has blocked the user from logging in.
+// Accounts pending deletion are handled by the callers, since RestoreAccount
+// exists to log into them.
+func canLogIn(user models.User) bool {
+	return user.DisabledAt == nil && !user.PasswordResetRequired
+}
+
+// blockedLogin writes a 403 response if an admin has blocked the user
+func blockedLogin(c *gin.Context, user models.User) bool {
+	switch {
+	case user.DisabledAt != nil:
+		metrics.AuthFailure(metrics.SourceLogin, "disabled")
+		apierror.Respond(c, http.StatusForbidden, apierror.AccountDisabled, "Account has been disabled.")
+		return true
+	case user.PasswordResetRequired:
+		metrics.AuthFailure(metrics.SourceLogin, "reset_required")
+		apierror.Respond(c, http.StatusForbidden, apierror.PasswordResetRequired, "A password reset is required. Use the link we emailed you or request a new one.")
+		return true
+	}
+	return false
+}
+
+// loginLocked writes a 429 response if the username or client IP is
+// locked out
+func loginLocked(c *gin.Context, username string) bool {
+	wait := loginThrottle.Check(username, c.ClientIP())
+	if wait <= 0 {
+		return false
+	}
+	metrics.AuthFailure(metrics.SourceLogin, "locked_out")
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	apierror.Respond(c, http.StatusTooManyRequests, apierror.LoginLocked, "Too many failed login attempts. Try again later.")
+	return true
+}
+
+// recordLoginFailure counts a failed attempt and stores any lockout it
+// causes for review
+func (h *AuthHandler) recordLoginFailure(c *gin.Context, username string) {
+	ip := c.ClientIP()
+	for _, lockout := range loginThrottle.Failure(username, ip) {
+		event := models.LockoutEvent{
+			Scope:       lockout.Scope,
+			Username:    username,
+			IP:          ip,
+			Failures:    lockout.Failures,
+			LockedUntil: lockout.Until,
+		}
+		// the lockout itself is in memory; losing the audit row is not
+		// worth failing the request over
+		if err := h.lockouts.Create(&event); err != nil {
+			middleware.Logger(c).Error("failed to record lockout", "scope", lockout.Scope, "key", lockout.Key, "error", err)
+		}
+	}
+}
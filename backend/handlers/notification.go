@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+const defaultNotificationLimit = 50
+
+type notificationsQuery struct {
+	Limit int `form:"limit" binding:"omitempty,gte=1,lte=100"` // defaults to 50
+}
+
+// NotificationHandler serves the in-app inbox that notify.InboxNotifier
+// fills
+type NotificationHandler struct {
+	notifications store.NotificationStore
+}
+
+func NewNotificationHandler(notifications store.NotificationStore) *NotificationHandler {
+	return &NotificationHandler{notifications: notifications}
+}
+
+// ListNotifications - GET /api/notifications?limit=50
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	var query notificationsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultNotificationLimit
+	}
+
+	notifications, err := h.notifications.List(userID, query.Limit)
+	if err != nil {
+		serverError(c, "Failed to fetch notifications", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// MarkNotificationRead - POST /api/notifications/:id/read
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	notificationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.NotificationNotFound, "Notification not found")
+		return
+	}
+
+	err = h.notifications.MarkRead(userID, uint(notificationID), time.Now())
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Respond(c, http.StatusNotFound, apierror.NotificationNotFound, "Notification not found")
+		return
+	}
+	if err != nil {
+		serverError(c, "Failed to update notification", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+func setupNotificationRouter(stores store.Stores) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	notifications := NewNotificationHandler(stores.Notifications)
+
+	router := gin.New()
+	router.Use(middleware.AuthMiddleware())
+	router.GET("/notifications", notifications.ListNotifications)
+	router.POST("/notifications/:id/read", notifications.MarkNotificationRead)
+	return router
+}
+
+func TestNotifications_ListAndRead(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		createTestUser(t, stores, 2, "other")
+		router := setupNotificationRouter(stores)
+		var ids []uint
+		for i := 1; i <= 3; i++ {
+			n := models.Notification{UserID: 1, Kind: models.NotificationHydrationReminder, Title: fmt.Sprintf("Reminder %d", i)}
+			stores.Notifications.Create(&n)
+			ids = append(ids, n.ID)
+		}
+		others := models.Notification{UserID: 2, Kind: models.NotificationHydrationReminder, Title: "Not yours"}
+		stores.Notifications.Create(&others)
+
+		w := sendJSON(router, "GET", "/notifications?limit=2", token, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var listed []models.Notification
+		json.Unmarshal(w.Body.Bytes(), &listed)
+		if len(listed) != 2 || listed[0].Title != "Reminder 3" || listed[1].Title != "Reminder 2" {
+			t.Errorf("Expected the two newest notifications, got %+v", listed)
+		}
+
+		w = sendJSON(router, "POST", fmt.Sprintf("/notifications/%d/read", ids[0]), token, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		w = sendJSON(router, "GET", "/notifications", token, "")
+		json.Unmarshal(w.Body.Bytes(), &listed)
+		if len(listed) != 3 || listed[2].ReadAt == nil || listed[0].ReadAt != nil {
+			t.Errorf("Expected only the oldest notification to be read, got %+v", listed)
+		}
+
+		for _, path := range []string{fmt.Sprintf("/notifications/%d/read", others.ID), "/notifications/abc/read"} {
+			if w := sendJSON(router, "POST", path, token, ""); w.Code != http.StatusNotFound {
+				t.Errorf("POST %s: expected status 404, got %d", path, w.Code)
+			}
+		}
+
+		if w := sendJSON(router, "GET", "/notifications?limit=0", token, ""); w.Code != http.StatusOK {
+			t.Errorf("Expected limit=0 to mean the default, got %d", w.Code)
+		}
+		if w := sendJSON(router, "GET", "/notifications?limit=101", token, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for limit=101, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,578 @@
+package handlers
+
+import (
+	"io/fs"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/openapi"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// Paths of the API description and its browser
+const (
+	OpenAPIPath = "/api/openapi.json"
+	DocsPath    = "/api/docs"
+)
+
+// Response shapes built with gin.H. Shared ones are named so generated
+// clients get a type for them.
+var (
+	messageResponse = openapi.Named("Message", openapi.Object(map[string]*openapi.Schema{
+		"message": openapi.String(),
+	}, "message"))
+
+	// the profile fields are only present once the user has a profile
+	sessionResponse = openapi.Named("Session", openapi.Object(map[string]*openapi.Schema{
+		"id":            openapi.Integer(),
+		"username":      openapi.String(),
+		"email":         openapi.String().Describe("Only returned by registration"),
+		"token":         openapi.String().Describe("Access token"),
+		"refresh_token": openapi.String(),
+		"expires_in":    openapi.Integer().Describe("Access token lifetime in seconds"),
+		"dateOfBirth":   openapi.DateTime(),
+		"sex":           openapi.String(),
+		"height":        openapi.Number(),
+		"weight":        openapi.Number(),
+		"neck":          openapi.Number(),
+		"waist":         openapi.Number(),
+		"hips":          openapi.Number(),
+	}, "id", "username", "token", "refresh_token", "expires_in"))
+
+	mfaChallengeResponse = openapi.Named("MFAChallenge", openapi.Object(map[string]*openapi.Schema{
+		"mfa_required": openapi.Boolean(),
+		"mfa_token":    openapi.String().Describe("Send to /api/auth/login/2fa with a code"),
+		"expires_in":   openapi.Integer(),
+	}, "mfa_required", "mfa_token", "expires_in"))
+
+	tokensResponse = openapi.Named("Tokens", openapi.Object(map[string]*openapi.Schema{
+		"token":         openapi.String(),
+		"refresh_token": openapi.String(),
+		"expires_in":    openapi.Integer(),
+	}, "token", "refresh_token", "expires_in"))
+
+	profileResponse = openapi.Named("ProfileSummary", openapi.Object(map[string]*openapi.Schema{
+		"id":          openapi.Integer(),
+		"username":    openapi.String(),
+		"dateOfBirth": openapi.DateTime(),
+		"sex":         openapi.String(),
+		"height":      openapi.Number(),
+		"weight":      openapi.Number(),
+		"neck":        openapi.Number(),
+		"waist":       openapi.Number(),
+		"hips":        openapi.Number(),
+	}, "id", "username", "sex", "height", "weight"))
+
+	personalTokenSchema = openapi.Named("PersonalToken", openapi.Object(map[string]*openapi.Schema{
+		"id":           openapi.Integer(),
+		"name":         openapi.String(),
+		"hint":         openapi.String().Describe("Last characters of the token"),
+		"scopes":       openapi.ArrayOf(openapi.String()),
+		"expires_at":   openapi.DateTime(),
+		"last_used_at": openapi.DateTime(),
+		"created_at":   openapi.DateTime(),
+		"token":        openapi.String().Describe("Only returned when the token is created"),
+	}, "id", "name", "hint", "scopes", "created_at"))
+
+	adminUserSchema = openapi.Named("AdminUser", openapi.Object(map[string]*openapi.Schema{
+		"id":                      openapi.Integer(),
+		"username":                openapi.String(),
+		"email":                   openapi.String(),
+		"role":                    openapi.String(),
+		"created_at":              openapi.DateTime(),
+		"disabled_at":             openapi.DateTime(),
+		"deletion_requested_at":   openapi.DateTime(),
+		"password_reset_required": openapi.Boolean(),
+		"reset_email_sent":        openapi.Boolean().Describe("Only returned by force-password-reset"),
+	}, "id", "username", "role", "created_at", "password_reset_required"))
+
+	weightLogChange = openapi.Object(map[string]*openapi.Schema{
+		"message": openapi.String(),
+		"log":     openapi.TypeOf(models.WeightLog{}),
+	}, "message", "log")
+
+	pageQuery  = openapi.Query("page", "Page number, from 1", openapi.Integer())
+	limitQuery = openapi.Query("limit", "Rows per page, at most 200 (default 50)", openapi.Integer())
+	dateQuery  = openapi.Query("date", "Day in YYYY-MM-DD", openapi.Date())
+	tzQuery    = openapi.Query("tz", "IANA time zone to count days in, instead of the profile's (default UTC)", openapi.String())
+)
+
+func ok(body *openapi.Schema) openapi.Reply {
+	return openapi.Reply{Status: http.StatusOK, Body: body}
+}
+
+func fails(statuses ...int) []openapi.Reply {
+	replies := make([]openapi.Reply, len(statuses))
+	for i, status := range statuses {
+		replies[i] = openapi.Reply{Status: status}
+	}
+	return replies
+}
+
+func replies(success openapi.Reply, errorStatuses ...int) []openapi.Reply {
+	return append([]openapi.Reply{success}, fails(errorStatuses...)...)
+}
+
+// APIRoutes lists every route registered by routes.SetupRoutes. A test
+// fails when the two disagree.
+func APIRoutes() []openapi.Route {
+	return []openapi.Route{
+		{
+			Method: "GET", Path: "/.well-known/jwks.json", ID: "getJWKS", Tag: "auth",
+			Summary:   "Public keys that verify access tokens",
+			Responses: replies(ok(openapi.TypeOf(utils.JWKS{}))),
+		},
+		{
+			Method: "GET", Path: OpenAPIPath, ID: "getOpenAPI", Tag: "meta",
+			Summary:   "This document",
+			Responses: replies(ok(openapi.Object(nil))),
+		},
+
+		// auth
+		{
+			Method: "POST", Path: "/api/auth/register", ID: "register", Tag: "auth",
+			Summary:   "Create an account and log in",
+			Body:      openapi.TypeOf(registerRequest{}),
+			Responses: replies(openapi.Reply{Status: http.StatusCreated, Body: sessionResponse}, 400, 409, 429),
+		},
+		{
+			Method: "POST", Path: "/api/auth/login", ID: "login", Tag: "auth",
+			Summary:     "Log in with a username and password",
+			Description: "Users with two-factor authentication get a challenge to finish at /api/auth/login/2fa instead of tokens.",
+			Body:        openapi.TypeOf(loginRequest{}),
+			Responses:   replies(ok(openapi.OneOf(sessionResponse, mfaChallengeResponse)), 400, 401, 403, 429),
+		},
+		{
+			Method: "POST", Path: "/api/auth/login/2fa", ID: "loginTwoFactor", Tag: "auth",
+			Summary:   "Finish a login with a TOTP or recovery code",
+			Body:      openapi.TypeOf(loginTwoFactorRequest{}),
+			Responses: replies(ok(sessionResponse), 400, 401, 403, 429),
+		},
+		{
+			Method: "POST", Path: "/api/auth/refresh", ID: "refreshToken", Tag: "auth",
+			Summary:   "Exchange a refresh token for new tokens",
+			Body:      openapi.TypeOf(refreshRequest{}),
+			Responses: replies(ok(tokensResponse), 400, 401, 429),
+		},
+		{
+			Method: "POST", Path: "/api/auth/password/forgot", ID: "forgotPassword", Tag: "auth",
+			Summary:   "Email a password reset link",
+			Body:      openapi.TypeOf(forgotPasswordRequest{}),
+			Responses: replies(openapi.Reply{Status: http.StatusAccepted, Body: messageResponse}, 400, 429),
+		},
+		{
+			Method: "POST", Path: "/api/auth/password/reset", ID: "resetPassword", Tag: "auth",
+			Summary:   "Set a new password with a reset token",
+			Body:      openapi.TypeOf(resetPasswordRequest{}),
+			Responses: replies(ok(messageResponse), 400, 429),
+		},
+		{
+			Method: "POST", Path: "/api/account/restore", ID: "restoreAccount", Tag: "account",
+			Summary:   "Cancel a pending account deletion and log in",
+			Body:      openapi.TypeOf(loginRequest{}),
+			Responses: replies(ok(openapi.OneOf(sessionResponse, mfaChallengeResponse)), 400, 401, 403, 429),
+		},
+
+		// sessions and security settings
+		{
+			Method: "POST", Path: "/api/auth/logout", ID: "logout", Tag: "auth", Auth: true,
+			Summary:     "Revoke the current access token",
+			Description: "Send the refresh token too to end the whole session.",
+			Responses:   replies(ok(messageResponse), 403),
+		},
+		{
+			Method: "POST", Path: "/api/auth/logout-all", ID: "logoutAll", Tag: "auth", Auth: true,
+			Summary:   "End every session of the user",
+			Responses: replies(ok(messageResponse), 403),
+		},
+		{
+			Method: "PUT", Path: "/api/auth/password", ID: "changePassword", Tag: "auth", Auth: true,
+			Summary:   "Change the password and end other sessions",
+			Body:      openapi.TypeOf(changePasswordRequest{}),
+			Responses: replies(ok(openapi.Object(map[string]*openapi.Schema{"message": openapi.String(), "token": openapi.String(), "refresh_token": openapi.String(), "expires_in": openapi.Integer()}, "message", "token", "refresh_token", "expires_in")), 400, 403, 404),
+		},
+		{
+			Method: "POST", Path: "/api/auth/2fa/setup", ID: "setupTwoFactor", Tag: "two-factor", Auth: true,
+			Summary:   "Start two-factor setup and get the TOTP secret",
+			Responses: replies(ok(openapi.Object(map[string]*openapi.Schema{"secret": openapi.String(), "otpauth_uri": openapi.String()}, "secret", "otpauth_uri")), 403, 409),
+		},
+		{
+			Method: "POST", Path: "/api/auth/2fa/enable", ID: "enableTwoFactor", Tag: "two-factor", Auth: true,
+			Summary:   "Confirm two-factor setup with a code",
+			Body:      openapi.TypeOf(totpCodeRequest{}),
+			Responses: replies(ok(openapi.Object(map[string]*openapi.Schema{"message": openapi.String(), "recovery_codes": openapi.ArrayOf(openapi.String())}, "message", "recovery_codes")), 400, 403, 409),
+		},
+		{
+			Method: "POST", Path: "/api/auth/2fa/disable", ID: "disableTwoFactor", Tag: "two-factor", Auth: true,
+			Summary:   "Turn two-factor authentication off",
+			Body:      openapi.TypeOf(disableTwoFactorRequest{}),
+			Responses: replies(ok(messageResponse), 400, 403),
+		},
+		{
+			Method: "POST", Path: "/api/auth/2fa/recovery-codes", ID: "regenerateRecoveryCodes", Tag: "two-factor", Auth: true,
+			Summary:   "Replace the recovery codes",
+			Body:      openapi.TypeOf(totpCodeRequest{}),
+			Responses: replies(ok(openapi.Object(map[string]*openapi.Schema{"recovery_codes": openapi.ArrayOf(openapi.String())}, "recovery_codes")), 400, 403),
+		},
+		{
+			Method: "POST", Path: "/api/tokens", ID: "createPersonalToken", Tag: "tokens", Auth: true,
+			Summary:   "Create a scoped personal access token",
+			Body:      openapi.TypeOf(createPersonalTokenRequest{}),
+			Responses: replies(openapi.Reply{Status: http.StatusCreated, Body: personalTokenSchema}, 400, 403),
+		},
+		{
+			Method: "GET", Path: "/api/tokens", ID: "listPersonalTokens", Tag: "tokens", Auth: true,
+			Summary:   "List personal access tokens",
+			Responses: replies(ok(openapi.Object(map[string]*openapi.Schema{"tokens": openapi.ArrayOf(personalTokenSchema)}, "tokens")), 403),
+		},
+		{
+			Method: "DELETE", Path: "/api/tokens/:id", ID: "revokePersonalToken", Tag: "tokens", Auth: true,
+			Summary:   "Revoke a personal access token",
+			Responses: replies(ok(messageResponse), 403, 404),
+		},
+		{
+			Method: "DELETE", Path: "/api/account", ID: "deleteAccount", Tag: "account", Auth: true,
+			Summary:     "Delete the account",
+			Description: "With a grace period the account is only scheduled for deletion (202) and can be restored until purge_after.",
+			Body:        openapi.TypeOf(deleteAccountRequest{}),
+			Responses: append([]openapi.Reply{
+				ok(messageResponse),
+				{Status: http.StatusAccepted, Body: openapi.Object(map[string]*openapi.Schema{"message": openapi.String(), "purge_after": openapi.DateTime()}, "message", "purge_after")},
+			}, fails(400, 403)...),
+		},
+
+		// admin
+		{
+			Method: "GET", Path: "/api/admin/users", ID: "adminListUsers", Tag: "admin", Auth: true,
+			Summary: "List users",
+			Query: []openapi.Parameter{
+				openapi.Query("q", "Username or email contains", openapi.String()),
+				openapi.Query("role", "Only users with this role", openapi.String()),
+				openapi.Query("disabled", "Only disabled (true) or enabled (false) users", openapi.Boolean()),
+				pageQuery, limitQuery,
+			},
+			Responses: replies(ok(openapi.Object(map[string]*openapi.Schema{
+				"users": openapi.ArrayOf(adminUserSchema),
+				"total": openapi.Integer(),
+				"page":  openapi.Integer(),
+				"limit": openapi.Integer(),
+			}, "users", "total", "page", "limit")), 400, 403),
+		},
+		{
+			Method: "GET", Path: "/api/admin/users/:id", ID: "adminGetUser", Tag: "admin", Auth: true,
+			Summary:   "Get a user",
+			Responses: replies(ok(adminUserSchema), 403, 404),
+		},
+		{
+			Method: "POST", Path: "/api/admin/users/:id/disable", ID: "adminDisableUser", Tag: "admin", Auth: true,
+			Summary:   "Disable a user and end their sessions",
+			Responses: replies(ok(adminUserSchema), 400, 403, 404),
+		},
+		{
+			Method: "POST", Path: "/api/admin/users/:id/enable", ID: "adminEnableUser", Tag: "admin", Auth: true,
+			Summary:   "Enable a disabled user",
+			Responses: replies(ok(adminUserSchema), 403, 404),
+		},
+		{
+			Method: "POST", Path: "/api/admin/users/:id/force-password-reset", ID: "adminForcePasswordReset", Tag: "admin", Auth: true,
+			Summary:   "Require a password reset before the next login",
+			Responses: replies(ok(adminUserSchema), 400, 403, 404),
+		},
+		{
+			Method: "PUT", Path: "/api/admin/users/:id/role", ID: "adminSetUserRole", Tag: "admin", Auth: true,
+			Summary:   "Change a user's role",
+			Body:      openapi.TypeOf(setRoleRequest{}),
+			Responses: replies(ok(adminUserSchema), 400, 403, 404),
+		},
+		{
+			Method: "GET", Path: "/api/admin/lockouts", ID: "adminListLockouts", Tag: "admin", Auth: true,
+			Summary: "List login lockouts, newest first",
+			Query:   []openapi.Parameter{pageQuery, limitQuery},
+			Responses: replies(ok(openapi.Object(map[string]*openapi.Schema{
+				"lockouts": openapi.ArrayOf(openapi.TypeOf(models.LockoutEvent{})),
+				"page":     openapi.Integer(),
+				"limit":    openapi.Integer(),
+			}, "lockouts", "page", "limit")), 403),
+		},
+		{
+			Method: "GET", Path: "/api/admin/stats", ID: "adminUsageStats", Tag: "admin", Auth: true,
+			Summary: "Usage statistics",
+			Responses: replies(ok(openapi.Object(map[string]*openapi.Schema{
+				"users": openapi.Object(map[string]*openapi.Schema{
+					"total":              openapi.Integer(),
+					"by_role":            openapi.MapOf(openapi.Integer()),
+					"disabled":           openapi.Integer(),
+					"pending_deletion":   openapi.Integer(),
+					"two_factor_enabled": openapi.Integer(),
+					"new_last_7_days":    openapi.Integer(),
+					"new_last_30_days":   openapi.Integer(),
+					"active_last_7_days": openapi.Integer(),
+				}),
+				"logs": openapi.Object(map[string]*openapi.Schema{
+					"water":    openapi.Integer(),
+					"weight":   openapi.Integer(),
+					"exercise": openapi.Integer(),
+				}),
+			}, "users", "logs")), 403),
+		},
+
+		// profile
+		{
+			Method: "GET", Path: "/api/profile", ID: "getProfile", Tag: "profile", Auth: true,
+			Summary:   "Get the health profile",
+			Responses: replies(ok(openapi.TypeOf(models.HealthProfile{})), 403, 404),
+		},
+		{
+			Method: "PUT", Path: "/api/profile", ID: "updateProfile", Tag: "profile", Auth: true,
+			Summary:   "Create or update the health profile",
+			Body:      openapi.TypeOf(models.HealthProfile{}),
+			Responses: replies(ok(profileResponse), 400, 403),
+		},
+		{
+			Method: "GET", Path: "/api/profile/stats", ID: "getProfileStats", Tag: "profile", Auth: true,
+			Summary:   "Age, BMI, body fat, BMR and TDEE from the profile",
+			Responses: replies(ok(openapi.TypeOf(models.ProfileStats{})), 403, 404),
+		},
+		{
+			Method: "POST", Path: "/api/caloriegoal", ID: "calculateCalorieGoal", Tag: "profile", Auth: true,
+			Summary:     "Daily calorie goal for losing, holding or gaining weight",
+			Description: "target_direction is \"lose\", \"hold\" or \"gain\".",
+			Body:        openapi.TypeOf(calorieGoalRequest{}),
+			Responses:   replies(ok(openapi.Object(map[string]*openapi.Schema{"adjusted_calories": openapi.Number()}, "adjusted_calories")), 400, 403),
+		},
+
+		// water
+		{
+			Method: "POST", Path: "/api/water", ID: "logWater", Tag: "water", Auth: true,
+			Summary:     "Log water intake (1 to 5000 ml)",
+			Description: "beverage_id picks a beverage from /api/water/beverages; without it the intake is plain water. The log records the hydration, caffeine and energy of the beverage at the time.",
+			Body:        openapi.TypeOf(logWaterRequest{}),
+			Responses:   replies(openapi.Reply{Status: http.StatusCreated, Body: openapi.TypeOf(models.WaterIntake{})}, 400, 403),
+		},
+		{
+			Method: "GET", Path: "/api/water", ID: "listWater", Tag: "water", Auth: true,
+			Summary:   "List water logs, newest first",
+			Query:     []openapi.Parameter{dateQuery, tzQuery},
+			Responses: replies(ok(openapi.TypeOf([]models.WaterIntake{})), 400, 403),
+		},
+		{
+			Method: "GET", Path: "/api/water/summary", ID: "getWaterSummary", Tag: "water", Auth: true,
+			Summary:     "Total water for a day or a range of days",
+			Description: "Without from, to or granularity: one day, by default today in the user's time zone. With any of them: a WaterIntakeRange of every day from..to (at most 366), grouped by day, week (from Monday) or month, with days that have no logs counted as zero. to defaults to today and from to six days before to.",
+			Query: []openapi.Parameter{
+				dateQuery,
+				openapi.Query("from", "First day of a range, YYYY-MM-DD", openapi.Date()),
+				openapi.Query("to", "Last day of a range, YYYY-MM-DD", openapi.Date()),
+				openapi.Query("granularity", "Period of each entry in a range's series", &openapi.Schema{Type: "string", Enum: []string{"day", "week", "month"}}),
+				tzQuery,
+			},
+			Responses: replies(ok(openapi.OneOf(openapi.TypeOf(models.WaterIntakeSummary{}), openapi.TypeOf(models.WaterIntakeRange{}))), 400, 403),
+		},
+		{
+			Method: "GET", Path: "/api/water/goal", ID: "getWaterGoal", Tag: "water", Auth: true,
+			Summary:     "Water goal for a day (default today in the user's time zone)",
+			Description: "A custom goal is the fixed amount the user set. The automatic goal is worked out from the profile's weight and activity level and that day's exercise minutes, and comes with a breakdown.",
+			Query:       []openapi.Parameter{dateQuery, tzQuery},
+			Responses:   replies(ok(openapi.TypeOf(models.DailyWaterGoal{})), 400, 403),
+		},
+		{
+			Method: "PUT", Path: "/api/water/goal", ID: "setWaterGoal", Tag: "water", Auth: true,
+			Summary:     "Set the water goal from a day onwards",
+			Description: "goal_ml null switches to the automatic goal. effective_from defaults to today and cannot be in the past; earlier days keep the goal they had.",
+			Query:       []openapi.Parameter{tzQuery},
+			Body:        openapi.TypeOf(setWaterGoalRequest{}),
+			Responses:   replies(ok(openapi.TypeOf(models.HydrationGoal{})), 400, 403),
+		},
+		{
+			Method: "PUT", Path: "/api/water/:id", ID: "updateWater", Tag: "water", Auth: true,
+			Summary:     "Replace a water log",
+			Description: "Checked like POST /api/water. A missing logged_at keeps the log's time and a missing beverage_id makes it plain water. The replaced version is kept in the log's history.",
+			Body:        openapi.TypeOf(logWaterRequest{}),
+			Responses:   replies(ok(openapi.TypeOf(models.WaterIntake{})), 400, 403, 404),
+		},
+		{
+			Method: "PATCH", Path: "/api/water/:id", ID: "patchWater", Tag: "water", Auth: true,
+			Summary:     "Change some fields of a water log",
+			Description: "Only the fields given change; \"beverage_id\": null makes the log plain water. A log that keeps its beverage keeps the hydration, caffeine and energy rates it was logged with. The replaced version is kept in the log's history.",
+			Body:        openapi.TypeOf(patchWaterRequest{}),
+			Responses:   replies(ok(openapi.TypeOf(models.WaterIntake{})), 400, 403, 404),
+		},
+		{
+			Method: "GET", Path: "/api/water/:id/history", ID: "getWaterHistory", Tag: "water", Auth: true,
+			Summary:   "A water log with its earlier versions, newest first",
+			Responses: replies(ok(openapi.TypeOf(models.WaterIntakeHistory{})), 403, 404),
+		},
+		{
+			Method: "DELETE", Path: "/api/water/:id", ID: "deleteWater", Tag: "water", Auth: true,
+			Summary:   "Delete a water log",
+			Responses: replies(ok(messageResponse), 403, 404),
+		},
+		{
+			Method: "GET", Path: "/api/water/beverages", ID: "listBeverages", Tag: "water", Auth: true,
+			Summary:   "List the built-in beverages and the user's own, by name",
+			Responses: replies(ok(openapi.TypeOf([]models.Beverage{})), 403),
+		},
+		{
+			Method: "POST", Path: "/api/water/beverages", ID: "createBeverage", Tag: "water", Auth: true,
+			Summary:     "Add a beverage to the user's catalog",
+			Description: "hydration_factor is how much of the volume counts towards the water goal: 1 for water, less for coffee or alcohol, more for milk.",
+			Body:        openapi.TypeOf(createBeverageRequest{}),
+			Responses:   replies(openapi.Reply{Status: http.StatusCreated, Body: openapi.TypeOf(models.Beverage{})}, 400, 403, 409),
+		},
+		{
+			Method: "DELETE", Path: "/api/water/beverages/:id", ID: "deleteBeverage", Tag: "water", Auth: true,
+			Summary:     "Delete one of the user's beverages",
+			Description: "Built-in beverages cannot be deleted. Water logs of the beverage keep their hydration, caffeine and energy.",
+			Responses:   replies(ok(messageResponse), 403, 404),
+		},
+		{
+			Method: "GET", Path: "/api/water/reminders", ID: "listReminders", Tag: "water", Auth: true,
+			Summary:   "List the user's hydration reminder rules, oldest first",
+			Responses: replies(ok(openapi.TypeOf([]models.ReminderRule{})), 403),
+		},
+		{
+			Method: "POST", Path: "/api/water/reminders", ID: "createReminder", Tag: "water", Auth: true,
+			Summary:     "Add a hydration reminder rule",
+			Description: "Reminders are sent between start_time and end_time (HH:MM) in the profile's time zone, at most once per interval_minutes. An interval rule's end_time may be earlier than its start_time for hours that cross midnight; a behind_pace rule's may not, since it paces the calendar day's goal. An interval rule reminds after interval_minutes without a drink; a behind_pace rule reminds when today's intake is more than margin_percent points behind drinking the goal evenly from start_time to end_time. Reminders go to GET /api/notifications and, when the server has one, a webhook.",
+			Body:        openapi.TypeOf(reminderRequest{}),
+			Responses:   replies(openapi.Reply{Status: http.StatusCreated, Body: openapi.TypeOf(models.ReminderRule{})}, 400, 403),
+		},
+		{
+			Method: "PUT", Path: "/api/water/reminders/:id", ID: "updateReminder", Tag: "water", Auth: true,
+			Summary:     "Replace a hydration reminder rule",
+			Description: "Checked like POST /api/water/reminders. enabled defaults to true.",
+			Body:        openapi.TypeOf(reminderRequest{}),
+			Responses:   replies(ok(openapi.TypeOf(models.ReminderRule{})), 400, 403, 404),
+		},
+		{
+			Method: "DELETE", Path: "/api/water/reminders/:id", ID: "deleteReminder", Tag: "water", Auth: true,
+			Summary:   "Delete a hydration reminder rule",
+			Responses: replies(ok(messageResponse), 403, 404),
+		},
+
+		// notifications
+		{
+			Method: "GET", Path: "/api/notifications", ID: "listNotifications", Tag: "notifications", Auth: true,
+			Summary:   "The user's in-app notifications, newest first",
+			Query:     []openapi.Parameter{openapi.Query("limit", "Most notifications to return, at most 100 (default 50)", openapi.Integer())},
+			Responses: replies(ok(openapi.TypeOf([]models.Notification{})), 400, 403),
+		},
+		{
+			Method: "POST", Path: "/api/notifications/:id/read", ID: "readNotification", Tag: "notifications", Auth: true,
+			Summary:     "Mark a notification as read",
+			Description: "Marking it again keeps the first read_at.",
+			Responses:   replies(ok(messageResponse), 403, 404),
+		},
+
+		// weight
+		{
+			Method: "PUT", Path: "/api/weight/add", ID: "addWeight", Tag: "weight", Auth: true,
+			Summary:     "Log a weight",
+			Description: "unit is \"metric\" (kg) or \"imperial\" (lbs) and defaults to the profile's preferred units.",
+			Body:        openapi.TypeOf(AddWeightLogRequest{}),
+			Responses:   replies(ok(weightLogChange), 400, 403),
+		},
+		{
+			Method: "GET", Path: "/api/weight/logs", ID: "listWeights", Tag: "weight", Auth: true,
+			Summary:   "The last 30 weights in the preferred units, newest first",
+			Responses: replies(ok(openapi.Object(map[string]*openapi.Schema{"entries": openapi.TypeOf([]weightLogResponse{})}, "entries")), 403),
+		},
+		{
+			Method: "POST", Path: "/api/weight/modify", ID: "modifyLastWeight", Tag: "weight", Auth: true,
+			Summary:   "Correct the most recent weight",
+			Body:      openapi.TypeOf(ModifyLastWeightRequest{}),
+			Responses: replies(ok(weightLogChange), 400, 403, 404),
+		},
+
+		// exercise
+		{
+			Method: "POST", Path: "/api/exercise/add", ID: "logExercise", Tag: "exercise", Auth: true,
+			Summary:   "Log an exercise",
+			Body:      openapi.TypeOf(logExerciseRequest{}),
+			Responses: replies(ok(messageResponse), 400, 403),
+		},
+		{
+			Method: "GET", Path: "/api/exercise/logs", ID: "listExercises", Tag: "exercise", Auth: true,
+			Summary: "The last 30 exercises, newest first",
+			Responses: replies(ok(openapi.Object(map[string]*openapi.Schema{
+				"exercise_logs": openapi.ArrayOf(openapi.Named("ExerciseLog", openapi.Object(map[string]*openapi.Schema{
+					"id":              openapi.Integer(),
+					"type":            openapi.String(),
+					"duration":        openapi.Integer().Describe("Minutes"),
+					"calories_burned": openapi.Integer(),
+					"logged_at":       openapi.DateTime(),
+				}, "id", "type", "duration", "calories_burned", "logged_at"))),
+			}, "exercise_logs")), 403),
+		},
+	}
+}
+
+// openAPIDocument is built on first use; the routes never change at run
+// time
+var openAPIDocument = sync.OnceValues(func() (*openapi.Document, error) {
+	return openapi.Build(openapi.Info{
+		Title:       "Fitness Tracker API",
+		Version:     "1.0.0",
+		Description: "Every /api route except the public auth endpoints needs a bearer token. Errors share one shape; branch on its code, not on the message.",
+	}, APIRoutes())
+})
+
+// OpenAPIDocument returns the API description served at OpenAPIPath
+func OpenAPIDocument() (*openapi.Document, error) {
+	return openAPIDocument()
+}
+
+// OpenAPI - GET /api/openapi.json
+func OpenAPI(c *gin.Context) {
+	doc, err := openAPIDocument()
+	if err != nil {
+		serverError(c, "Failed to build API description.", err)
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// swaggerInitializer points the bundled Swagger UI at our document instead
+// of its petstore demo
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "` + OpenAPIPath + `",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// SwaggerUI - GET /api/docs/*file
+// Serves the Swagger UI files compiled into the binary
+func SwaggerUI(c *gin.Context) {
+	file := strings.TrimPrefix(c.Param("file"), "/")
+	switch file {
+	case "", "index.html":
+		// read directly; http.FileServer redirects index.html to the directory
+		index, err := fs.ReadFile(swaggerFiles.FS, "index.html")
+		if err != nil {
+			serverError(c, "Failed to load API docs.", err)
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", index)
+		return
+	case "swagger-initializer.js":
+		c.Data(http.StatusOK, "application/javascript; charset=utf-8", []byte(swaggerInitializer))
+		return
+	}
+	if _, err := fs.Stat(swaggerFiles.FS, file); err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.NotFound, "Not found")
+		return
+	}
+	c.FileFromFS(file, http.FS(swaggerFiles.FS))
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func openAPIRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(OpenAPIPath, OpenAPI)
+	router.GET(DocsPath+"/*file", SwaggerUI)
+	return router
+}
+
+func TestOpenAPI_ServesDocument(t *testing.T) {
+	router := openAPIRouter()
+
+	req := httptest.NewRequest("GET", OpenAPIPath, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected JSON, got %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("Expected an OpenAPI 3 document, got %q", doc.OpenAPI)
+	}
+	if doc.Paths["/api/water/{id}"]["delete"] == nil {
+		t.Error("Expected DELETE /api/water/{id} to be described")
+	}
+}
+
+func TestSwaggerUI_PointsAtDocument(t *testing.T) {
+	router := openAPIRouter()
+
+	req := httptest.NewRequest("GET", DocsPath+"/", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "swagger-ui") {
+		t.Errorf("Expected the Swagger UI page, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", DocsPath+"/swagger-initializer.js", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if !strings.Contains(w.Body.String(), OpenAPIPath) {
+		t.Errorf("Expected the initializer to load %s, got %s", OpenAPIPath, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", DocsPath+"/missing.js", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+}
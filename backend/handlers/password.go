@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/mailer"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+type forgotPasswordRequest struct {
+	Identifier string `json:"identifier" binding:"required"` // username or email
+}
+
+type resetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// PasswordHandler changes passwords and resets forgotten ones
+type PasswordHandler struct {
+	auth   *AuthHandler
+	resets store.PasswordResetStore
+	mailer mailer.Mailer
+	cfg    config.AuthConfig
+}
+
+func NewPasswordHandler(auth *AuthHandler, resets store.PasswordResetStore, m mailer.Mailer, cfg config.AuthConfig) *PasswordHandler {
+	return &PasswordHandler{auth: auth, resets: resets, mailer: m, cfg: cfg}
+}
+
+// forgotPasswordTimeout bounds the reset mail sent after the response to a
+// forgotten password request
+const forgotPasswordTimeout = 2 * time.Minute
+
+// pendingResets tracks reset mails still being sent in the background
+var pendingResets sync.WaitGroup
+
+// WaitPendingResets blocks until every reset mail sent in the background
+// has finished or ctx is done. Call it on shutdown before closing the
+// database the mails write their tokens to.
+func WaitPendingResets(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		pendingResets.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// passwordProblem returns a message describing why a new password is not
+// acceptable, or "" if it is
+func passwordProblem(password string) string {
+	if len(password) < 6 {
+		return "Password must be at least 6 characters long."
+	}
+	return ""
+}
+
+// normalizeEmail validates a bare address and lowercases it
+func normalizeEmail(raw string) (string, bool) {
+	addr, err := mail.ParseAddress(raw)
+	if err != nil || addr.Address != strings.TrimSpace(raw) {
+		return "", false
+	}
+	return strings.ToLower(addr.Address), true
+}
+
+// ChangePassword - PUT /api/auth/password
+// Requires the current password. Every existing session is revoked and the
+// caller gets a fresh session in the response.
+func (h *PasswordHandler) ChangePassword(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	var req changePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+
+	user, err := h.auth.users.Get(userID)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.UserNotFound, "User not found.")
+		return
+	}
+
+	if !utils.CheckPasswordHash(user.PasswordHash, req.CurrentPassword) {
+		apierror.Respond(c, http.StatusForbidden, apierror.IncorrectPassword, "Current password is incorrect.")
+		return
+	}
+
+	if problem := passwordProblem(req.NewPassword); problem != "" {
+		apierror.Invalid(c, "new_password", "password", problem)
+		return
+	}
+
+	if err := h.setPassword(user.ID, req.NewPassword); err != nil {
+		serverError(c, "Failed to change password.", err)
+		return
+	}
+
+	tokens, err := h.auth.issueTokens(user)
+	if err != nil {
+		serverError(c, "Failed to generate token.", err)
+		return
+	}
+
+	response := gin.H{"message": "Password changed successfully"}
+	tokens.addTo(response)
+	c.JSON(http.StatusOK, response)
+}
+
+// ForgotPassword - POST /api/auth/password/forgot
+// Mails a single-use reset link. The response is the same whether or not
+// the account exists so it cannot be used to discover usernames, and the
+// mail is sent after responding so timing does not give it away either.
+func (h *PasswordHandler) ForgotPassword(c *gin.Context) {
+	var req forgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+
+	accepted := gin.H{"message": "If the account exists and has an email address, a reset link has been sent."}
+
+	// an identifier with an @ can only be an email address, so a username
+	// that equals someone else's email never matches their account
+	identifier := strings.TrimSpace(req.Identifier)
+	lookup := h.auth.users.GetByUsername
+	if strings.Contains(identifier, "@") {
+		lookup, identifier = h.auth.users.GetByEmail, strings.ToLower(identifier)
+	}
+	user, err := lookup(identifier)
+	if err != nil || user.Email == nil {
+		c.JSON(http.StatusAccepted, accepted)
+		return
+	}
+
+	logger := middleware.Logger(c)
+	pendingResets.Add(1)
+	go func() {
+		defer pendingResets.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), forgotPasswordTimeout)
+		defer cancel()
+		if err := h.sendPasswordReset(ctx, user); err != nil {
+			logger.Error("password reset failed", "user_id", user.ID, "error", err)
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, accepted)
+}
+
+// sendPasswordReset supersedes any outstanding reset token and mails a
+// new one
+func (h *PasswordHandler) sendPasswordReset(ctx context.Context, user models.User) error {
+	token, hash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if err := h.resets.Replace(&models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(h.cfg.PasswordResetTTL.Std()),
+	}); err != nil {
+		return err
+	}
+
+	link := strings.ReplaceAll(h.cfg.ResetURL, "{token}", token)
+	return h.mailer.Send(ctx, mailer.Message{
+		To:      *user.Email,
+		Subject: "Reset your Fitness Tracker password",
+		Body: "Hi " + user.Username + ",\n\n" +
+			"Use the link below to choose a new password. It expires in " + h.cfg.PasswordResetTTL.Std().String() + " and can only be used once.\n\n" +
+			link + "\n\n" +
+			"If you did not ask for this, you can ignore this email.\n",
+	})
+}
+
+// ResetPassword - POST /api/auth/password/reset
+// Redeems a reset token, sets the new password and ends every session.
+func (h *PasswordHandler) ResetPassword(c *gin.Context) {
+	var req resetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+
+	if problem := passwordProblem(req.NewPassword); problem != "" {
+		apierror.Invalid(c, "new_password", "password", problem)
+		return
+	}
+
+	stored, err := h.resets.GetByHash(utils.HashToken(req.Token))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidResetToken, "Invalid or expired reset token.")
+		return
+	}
+	if stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidResetToken, "Invalid or expired reset token.")
+		return
+	}
+
+	// claim the token; a concurrent redemption loses here
+	redeemed, err := h.resets.Redeem(stored.ID)
+	if err != nil {
+		serverError(c, "Failed to reset password.", err)
+		return
+	}
+	if !redeemed {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidResetToken, "Invalid or expired reset token.")
+		return
+	}
+
+	if err := h.setPassword(stored.UserID, req.NewPassword); errors.Is(err, store.ErrNotFound) {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidResetToken, "Invalid or expired reset token.")
+		return
+	} else if err != nil {
+		serverError(c, "Failed to reset password.", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset. Please log in with your new password."})
+}
+
+// setPassword stores a new password hash and revokes all sessions
+func (h *PasswordHandler) setPassword(userID uint, password string) error {
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := h.auth.users.SetPassword(userID, hash); err != nil {
+		return err
+	}
+	return h.auth.revokeSessions(userID)
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/mailer"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+func setupPasswordRouter(t *testing.T, stores store.Stores, m mailer.Mailer) *gin.Engine {
+	router, auth := setupSessionRouter(t, stores)
+	passwords := NewPasswordHandler(auth, stores.PasswordResets, m, config.Default().Auth)
+	router.POST("/password/forgot", func(c *gin.Context) {
+		passwords.ForgotPassword(c)
+		// the mail goes out after the response; wait so tests can read it
+		pendingResets.Wait()
+	})
+	router.POST("/password/reset", passwords.ResetPassword)
+	router.PUT("/password", middleware.AuthMiddleware(), passwords.ChangePassword)
+	return router
+}
+
+func jsonRequest(router *gin.Engine, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	jsonBody, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func registerWithEmail(t *testing.T, router *gin.Engine, username, email string) map[string]interface{} {
+	w := jsonRequest(router, "POST", "/register", "", map[string]string{
+		"username": username,
+		"password": "password123",
+		"email":    email,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Failed to register test user: %d %s", w.Code, w.Body.String())
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return response
+}
+
+// extracts the reset token from the link in the last mail sent
+func resetTokenFromMail(t *testing.T, m *mailer.Recorder) string {
+	sent := m.Sent()
+	if len(sent) == 0 {
+		t.Fatal("Expected a reset email to be sent")
+	}
+	body := sent[len(sent)-1].Body
+	idx := strings.Index(body, "token=")
+	if idx < 0 {
+		t.Fatalf("Expected reset link in email, got %q", body)
+	}
+	return strings.Fields(body[idx+len("token="):])[0]
+}
+
+func TestRegister_InvalidEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupPasswordRouter(t, stores, mailer.NewLogMailer(io.Discard))
+
+		w := jsonRequest(router, "POST", "/register", "", map[string]string{
+			"username": "testuser",
+			"password": "password123",
+			"email":    "not-an-email",
+		})
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestChangePassword_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupPasswordRouter(t, stores, mailer.NewLogMailer(io.Discard))
+
+		session := registerTestUser(t, router, "testuser")
+		oldToken := session["token"].(string)
+
+		w := jsonRequest(router, "PUT", "/password", oldToken, map[string]string{
+			"current_password": "password123",
+			"new_password":     "newpassword456",
+		})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		// the old session is gone, the new one works
+		if w := authedRequest(router, "GET", "/me", oldToken); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected old token to be revoked, got %d", w.Code)
+		}
+		if w := authedRequest(router, "GET", "/me", response["token"].(string)); w.Code != http.StatusOK {
+			t.Errorf("Expected new token to work, got %d", w.Code)
+		}
+
+		// only the new password logs in
+		w = jsonRequest(router, "POST", "/login", "", map[string]string{"username": "testuser", "password": "newpassword456"})
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected login with new password to succeed, got %d", w.Code)
+		}
+	})
+}
+
+func TestChangePassword_WrongCurrentPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupPasswordRouter(t, stores, mailer.NewLogMailer(io.Discard))
+
+		session := registerTestUser(t, router, "testuser")
+
+		w := jsonRequest(router, "PUT", "/password", session["token"].(string), map[string]string{
+			"current_password": "wrongpassword",
+			"new_password":     "newpassword456",
+		})
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", w.Code)
+		}
+	})
+}
+
+func TestChangePassword_TooShort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupPasswordRouter(t, stores, mailer.NewLogMailer(io.Discard))
+
+		session := registerTestUser(t, router, "testuser")
+
+		w := jsonRequest(router, "PUT", "/password", session["token"].(string), map[string]string{
+			"current_password": "password123",
+			"new_password":     "abc",
+		})
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestForgotPassword_SameResponseForUnknownUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		m := mailer.NewRecorder()
+		router := setupPasswordRouter(t, stores, m)
+
+		registerWithEmail(t, router, "testuser", "test@example.com")
+
+		known := jsonRequest(router, "POST", "/password/forgot", "", map[string]string{"identifier": "testuser"})
+		unknown := jsonRequest(router, "POST", "/password/forgot", "", map[string]string{"identifier": "nobody"})
+
+		if known.Code != http.StatusAccepted || unknown.Code != http.StatusAccepted {
+			t.Errorf("Expected 202 for both, got %d and %d", known.Code, unknown.Code)
+		}
+		if known.Body.String() != unknown.Body.String() {
+			t.Errorf("Expected identical responses, got %s and %s", known.Body.String(), unknown.Body.String())
+		}
+
+		sent := m.Sent()
+		if len(sent) != 1 || sent[0].To != "test@example.com" {
+			t.Errorf("Expected one email to test@example.com, got %+v", sent)
+		}
+	})
+}
+
+func TestForgotPassword_EmailIdentifierOnlyMatchesEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		m := mailer.NewRecorder()
+		router := setupPasswordRouter(t, stores, m)
+
+		// a username that is someone else's email address
+		registerWithEmail(t, router, "victim@example.com", "attacker@example.com")
+		registerWithEmail(t, router, "victimuser", "victim@example.com")
+
+		jsonRequest(router, "POST", "/password/forgot", "", map[string]string{"identifier": "victim@example.com"})
+
+		sent := m.Sent()
+		if len(sent) != 1 || sent[0].To != "victim@example.com" {
+			t.Errorf("Expected the reset email to go to the address owner, got %+v", sent)
+		}
+	})
+}
+
+func TestResetPassword_SingleUse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		m := mailer.NewRecorder()
+		router := setupPasswordRouter(t, stores, m)
+
+		session := registerWithEmail(t, router, "testuser", "Test@Example.com")
+		jsonRequest(router, "POST", "/password/forgot", "", map[string]string{"identifier": "test@example.com"})
+		token := resetTokenFromMail(t, m)
+
+		w := jsonRequest(router, "POST", "/password/reset", "", map[string]string{"token": token, "new_password": "resetpass789"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		// existing sessions are logged out
+		if w := authedRequest(router, "GET", "/me", session["token"].(string)); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected existing session to be revoked, got %d", w.Code)
+		}
+
+		// the token cannot be used twice
+		w = jsonRequest(router, "POST", "/password/reset", "", map[string]string{"token": token, "new_password": "another123"})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 on reuse, got %d", w.Code)
+		}
+
+		w = jsonRequest(router, "POST", "/login", "", map[string]string{"username": "testuser", "password": "resetpass789"})
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected login with reset password to succeed, got %d", w.Code)
+		}
+	})
+}
+
+func TestResetPassword_ExpiredToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupPasswordRouter(t, stores, mailer.NewLogMailer(io.Discard))
+
+		user := registerWithEmail(t, router, "testuser", "test@example.com")
+		token, hash, _ := utils.GenerateOpaqueToken()
+		if err := stores.PasswordResets.Replace(&models.PasswordResetToken{
+			UserID:    uint(user["id"].(float64)),
+			TokenHash: hash,
+			ExpiresAt: time.Now().Add(-time.Minute),
+		}); err != nil {
+			t.Fatalf("Failed to store reset token: %v", err)
+		}
+
+		w := jsonRequest(router, "POST", "/password/reset", "", map[string]string{"token": token, "new_password": "resetpass789"})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for expired token, got %d", w.Code)
+		}
+	})
+}
+
+// blockingMailer holds every Send until release is closed
+type blockingMailer struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (m blockingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	close(m.started)
+	<-m.release
+	return nil
+}
+
+func TestWaitPendingResets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		m := blockingMailer{started: make(chan struct{}), release: make(chan struct{})}
+		router := setupAuthRouter(stores)
+		passwords := NewPasswordHandler(newAuthHandler(stores), stores.PasswordResets, m, config.Default().Auth)
+		router.POST("/password/forgot", passwords.ForgotPassword)
+		registerWithEmail(t, router, "testuser", "test@example.com")
+
+		if w := jsonRequest(router, "POST", "/password/forgot", "", map[string]string{"identifier": "testuser"}); w.Code != http.StatusAccepted {
+			t.Fatalf("Expected status 202 before the mail is sent, got %d", w.Code)
+		}
+		<-m.started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := WaitPendingResets(ctx); err != context.DeadlineExceeded {
+			t.Errorf("Expected the wait to give up while the mail is stuck, got %v", err)
+		}
+
+		close(m.release)
+		if err := WaitPendingResets(context.Background()); err != nil {
+			t.Errorf("Expected the wait to finish once the mail is sent, got %v", err)
+		}
+	})
+}
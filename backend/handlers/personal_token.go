@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// maxPersonalTokens caps how many tokens one user can hold
+const maxPersonalTokens = 50
+
+// PersonalTokenHandler lets users manage their personal access tokens
+type PersonalTokenHandler struct {
+	tokens store.PersonalTokenStore
+}
+
+func NewPersonalTokenHandler(tokens store.PersonalTokenStore) *PersonalTokenHandler {
+	return &PersonalTokenHandler{tokens: tokens}
+}
+
+type createPersonalTokenRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes" binding:"required"`
+	ExpiresInDays int      `json:"expires_in_days"` // 0 never expires
+}
+
+// personalTokenResponse describes a token without its secret
+func personalTokenResponse(token models.PersonalAccessToken) gin.H {
+	return gin.H{
+		"id":           token.ID,
+		"name":         token.Name,
+		"hint":         token.Hint,
+		"scopes":       strings.Fields(token.Scopes),
+		"expires_at":   token.ExpiresAt,
+		"last_used_at": token.LastUsedAt,
+		"created_at":   token.CreatedAt,
+	}
+}
+
+// CreatePersonalToken - POST /api/tokens
+// The token itself is only ever returned by this call.
+func (h *PersonalTokenHandler) CreatePersonalToken(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	var req createPersonalTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		apierror.Invalid(c, "name", "max", "Name must be between 1 and 100 characters.")
+		return
+	}
+
+	if len(req.Scopes) == 0 {
+		apierror.Invalid(c, "scopes", "required", "At least one scope is required.")
+		return
+	}
+	scopes := map[string]bool{}
+	for _, scope := range req.Scopes {
+		if !utils.ValidScope(scope) {
+			apierror.Invalid(c, "scopes", "oneof", "Unknown scope: "+scope)
+			return
+		}
+		scopes[scope] = true
+	}
+	granted := make([]string, 0, len(scopes))
+	for scope := range scopes {
+		granted = append(granted, scope)
+	}
+	sort.Strings(granted)
+
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > 365 {
+		apierror.Invalid(c, "expires_in_days", "lte", "expires_in_days must be between 0 and 365.")
+		return
+	}
+
+	count, err := h.tokens.Count(userID)
+	if err != nil {
+		serverError(c, "Failed to create token.", err)
+		return
+	}
+	if count >= maxPersonalTokens {
+		apierror.Respond(c, http.StatusConflict, apierror.TokenLimitReached, "Token limit reached. Revoke an old token first.")
+		return
+	}
+
+	token, hash, err := utils.GeneratePersonalToken()
+	if err != nil {
+		serverError(c, "Failed to create token.", err)
+		return
+	}
+
+	record := models.PersonalAccessToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hash,
+		Hint:      token[len(token)-4:],
+		Scopes:    strings.Join(granted, " "),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		record.ExpiresAt = &expiresAt
+	}
+	if err := h.tokens.Create(&record); err != nil {
+		serverError(c, "Failed to create token.", err)
+		return
+	}
+
+	response := personalTokenResponse(record)
+	response["token"] = token
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListPersonalTokens - GET /api/tokens
+func (h *PersonalTokenHandler) ListPersonalTokens(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	tokens, err := h.tokens.List(userID)
+	if err != nil {
+		serverError(c, "Failed to retrieve tokens.", err)
+		return
+	}
+
+	response := make([]gin.H, len(tokens))
+	for i, token := range tokens {
+		response[i] = personalTokenResponse(token)
+	}
+	c.JSON(http.StatusOK, gin.H{"tokens": response})
+}
+
+// RevokePersonalToken - DELETE /api/tokens/:id
+func (h *PersonalTokenHandler) RevokePersonalToken(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.TokenNotFound, "Token not found.")
+		return
+	}
+	if err := h.tokens.Delete(userID, uint(id)); errors.Is(err, store.ErrNotFound) {
+		apierror.Respond(c, http.StatusNotFound, apierror.TokenNotFound, "Token not found.")
+		return
+	} else if err != nil {
+		serverError(c, "Failed to revoke token.", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Token revoked"})
+}
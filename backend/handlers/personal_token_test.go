@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+func setupPersonalTokenRouter(t *testing.T, stores store.Stores) *gin.Engine {
+	middleware.SetPersonalTokenStore(stores.PersonalTokens)
+	t.Cleanup(func() { middleware.SetPersonalTokenStore(nil) })
+
+	router := setupAuthRouter(stores)
+	tokens := NewPersonalTokenHandler(stores.PersonalTokens)
+	protected := router.Group("")
+	protected.Use(middleware.AuthMiddleware())
+	protected.POST("/tokens", middleware.SessionOnly(), tokens.CreatePersonalToken)
+	protected.GET("/tokens", middleware.SessionOnly(), tokens.ListPersonalTokens)
+	protected.DELETE("/tokens/:id", middleware.SessionOnly(), tokens.RevokePersonalToken)
+	protected.GET("/water", middleware.RequireScope("water"), func(c *gin.Context) {
+		userID, _ := middleware.GetUserID(c)
+		c.JSON(http.StatusOK, gin.H{"user_id": userID})
+	})
+	return router
+}
+
+func TestCreatePersonalToken_AuthenticatesWithScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupPersonalTokenRouter(t, stores)
+		session := registerTestUser(t, router, "testuser")
+		sessionToken := session["token"].(string)
+
+		w := jsonRequest(router, "POST", "/tokens", sessionToken, gin.H{
+			"name":            "notebook",
+			"scopes":          []string{"water:read"},
+			"expires_in_days": 30,
+		})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d %s", w.Code, w.Body.String())
+		}
+		var created map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &created)
+		token := created["token"].(string)
+		if created["expires_at"] == nil {
+			t.Error("Expected an expiry")
+		}
+
+		if w := authedRequest(router, "GET", "/water", token); w.Code != http.StatusOK {
+			t.Errorf("Expected the token to read water, got %d", w.Code)
+		}
+		if w := authedRequest(router, "GET", "/tokens", token); w.Code != http.StatusForbidden {
+			t.Errorf("Expected the token to be refused for token management, got %d", w.Code)
+		}
+
+		stored, err := stores.PersonalTokens.List(uint(session["id"].(float64)))
+		if err != nil || len(stored) != 1 {
+			t.Fatalf("Expected one stored token, got %v, %v", stored, err)
+		}
+		if stored[0].TokenHash == token || strings.Contains(stored[0].TokenHash, token) {
+			t.Error("Token must not be stored in plain text")
+		}
+		if stored[0].LastUsedAt == nil {
+			t.Error("Expected last_used_at to be recorded")
+		}
+	})
+}
+
+func TestCreatePersonalToken_RejectsUnknownScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupPersonalTokenRouter(t, stores)
+		session := registerTestUser(t, router, "testuser")
+
+		w := jsonRequest(router, "POST", "/tokens", session["token"].(string), gin.H{
+			"name":   "admin",
+			"scopes": []string{"account:write"},
+		})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestListAndRevokePersonalTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupPersonalTokenRouter(t, stores)
+		session := registerTestUser(t, router, "testuser")
+		sessionToken := session["token"].(string)
+
+		w := jsonRequest(router, "POST", "/tokens", sessionToken, gin.H{"name": "home", "scopes": []string{"water:read"}})
+		var created map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &created)
+		token := created["token"].(string)
+
+		w = authedRequest(router, "GET", "/tokens", sessionToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		if strings.Contains(w.Body.String(), token) {
+			t.Error("Listing must not reveal the token")
+		}
+		var listed struct {
+			Tokens []map[string]interface{} `json:"tokens"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &listed)
+		if len(listed.Tokens) != 1 || listed.Tokens[0]["name"] != "home" {
+			t.Fatalf("Unexpected list %v", listed.Tokens)
+		}
+
+		path := "/tokens/" + jsonNumber(created["id"])
+		if w := authedRequest(router, "DELETE", path, sessionToken); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		if w := authedRequest(router, "GET", "/water", token); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected a revoked token to be refused, got %d", w.Code)
+		}
+		if w := authedRequest(router, "DELETE", path, sessionToken); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+}
+
+// jsonNumber formats an ID decoded from JSON for use in a path
+func jsonNumber(value interface{}) string {
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
@@ -4,57 +4,73 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func GetProfile(c *gin.Context) {
+// ProfileHandler serves the health profile, the stats derived from it and
+// the calorie goal
+type ProfileHandler struct {
+	profiles store.ProfileStore
+	users    store.UserStore
+}
+
+func NewProfileHandler(profiles store.ProfileStore, users store.UserStore) *ProfileHandler {
+	return &ProfileHandler{profiles: profiles, users: users}
+}
+
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
 	// validate through middleware
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
 		return
 	}
 
 	// if valid, get profile
-	var profile models.HealthProfile
-	if err := database.DB.Where("user_id = ?", userID).First(&profile).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Profile not found"})
+	profile, err := h.profiles.Get(userID)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.ProfileNotFound, "Profile not found")
 		return
 	}
 
 	c.JSON(http.StatusOK, profile)
 }
 
-func UpdateProfile(c *gin.Context) {
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
 		return
 	}
 
 	var req models.HealthProfile
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.BadRequest(c, err)
 		return
 	}
 
 	// validation
-	if req.HeightCM <= 0 || req.WeightKG <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Height and weight must be positive"})
+	if req.HeightCM <= 0 {
+		apierror.Invalid(c, "height_cm", "gt", "Height and weight must be positive")
+		return
+	}
+	if req.WeightKG <= 0 {
+		apierror.Invalid(c, "weight_kg", "gt", "Height and weight must be positive")
 		return
 	}
 
 	if req.Sex != "male" && req.Sex != "female" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Sex must be 'male' or 'female'"})
+		apierror.Invalid(c, "sex", "oneof", "Sex must be 'male' or 'female'")
 		return
 	}
 
 	if req.DateOfBirth != nil && req.DateOfBirth.After(time.Now()) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Date of birth cannot be in the future"})
+		apierror.Invalid(c, "date_of_birth", "past", "Date of birth cannot be in the future")
 		return
 	}
 
@@ -64,13 +80,27 @@ func UpdateProfile(c *gin.Context) {
 	}
 
 	if req.ActivityLevel != "" && !validActivities[req.ActivityLevel] {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activity level"})
+		apierror.Invalid(c, "activity_level", "oneof", "Invalid activity level")
+		return
+	}
+
+	if _, err := utils.LoadTimeZone(req.TimeZone); err != nil {
+		apierror.Invalid(c, "time_zone", "timezone", "Unknown time zone. Use an IANA name such as America/Los_Angeles")
+		return
+	}
+
+	// null keeps the default limit
+	if req.CaffeineLimitMG != nil && *req.CaffeineLimitMG <= 0 {
+		apierror.Invalid(c, "caffeine_limit_mg", "gt", "Caffeine limit must be positive")
+		return
+	}
+	if req.CaffeineLimitMG != nil && *req.CaffeineLimitMG > 2000 {
+		apierror.Invalid(c, "caffeine_limit_mg", "lte", "Caffeine limit too large (max 2000mg)")
 		return
 	}
 
 	// check if profile already exists
-	var profile models.HealthProfile
-	err := database.DB.Where("user_id = ?", userID).First(&profile).Error
+	profile, err := h.profiles.Get(userID)
 
 	if err != nil {
 		// does not exist, create new profile
@@ -85,9 +115,12 @@ func UpdateProfile(c *gin.Context) {
 			HipsCM:         req.HipsCM,
 			ActivityLevel:  req.ActivityLevel,
 			PreferredUnits: req.PreferredUnits,
+			TimeZone:       req.TimeZone,
+
+			CaffeineLimitMG: req.CaffeineLimitMG,
 		}
-		if err := database.DB.Create(&profile).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create profile"})
+		if err := h.profiles.Save(&profile); err != nil {
+			serverError(c, "Failed to create profile", err)
 			return
 		}
 	} else {
@@ -99,18 +132,20 @@ func UpdateProfile(c *gin.Context) {
 		profile.HipsCM = req.HipsCM
 		profile.ActivityLevel = req.ActivityLevel
 		profile.PreferredUnits = req.PreferredUnits
+		profile.TimeZone = req.TimeZone
+		profile.CaffeineLimitMG = req.CaffeineLimitMG
 		profile.UpdatedAt = req.UpdatedAt
 
-		if err := database.DB.Save(&profile).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+		if err := h.profiles.Save(&profile); err != nil {
+			serverError(c, "Failed to update profile", err)
 			return
 		}
 	}
 
 	// Get user information to return combined response
-	var user models.User
-	if err := database.DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve user"})
+	user, err := h.users.Get(userID)
+	if err != nil {
+		serverError(c, "Failed to retrieve user", err)
 		return
 	}
 
@@ -139,16 +174,16 @@ func UpdateProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-func GetStats(c *gin.Context) {
+func (h *ProfileHandler) GetStats(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
 		return
 	}
 
-	var profile models.HealthProfile
-	if err := database.DB.Where("user_id = ?", userID).First(&profile).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Profile not found. Please create a profile first."})
+	profile, err := h.profiles.Get(userID)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.ProfileNotFound, "Profile not found. Please create a profile first.")
 		return
 	}
 
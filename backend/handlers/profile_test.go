@@ -9,40 +9,22 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 
-	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
 )
 
-func setupProfileTestDB(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
-	if err != nil {
-		t.Fatalf("Failed to connect to test database: %v", err)
-	}
-
-	err = db.AutoMigrate(&models.User{}, &models.HealthProfile{}, &models.WaterIntake{})
-	if err != nil {
-		t.Fatalf("Failed to migrate test database: %v", err)
-	}
-
-	database.DB = db
-
-	return db
-}
-
 // creates a user and returns a valid token
-func createTestUser(t *testing.T, db *gorm.DB, userID uint, username string) string {
+func createTestUser(t *testing.T, stores store.Stores, userID uint, username string) string {
 	user := models.User{
 		ID:           userID,
 		Username:     username,
 		PasswordHash: "hash",
 	}
-	db.Create(&user)
+	seed(t, stores, &user)
 
 	token, err := utils.GenerateToken(userID, username)
 	if err != nil {
@@ -52,417 +34,563 @@ func createTestUser(t *testing.T, db *gorm.DB, userID uint, username string) str
 	return token
 }
 
+// seed adds test records through the matching store
+func seed(t *testing.T, stores store.Stores, records ...interface{}) {
+	t.Helper()
+	for _, record := range records {
+		var err error
+		switch r := record.(type) {
+		case *models.User:
+			err = stores.Users.Create(r)
+		case *models.HealthProfile:
+			err = stores.Profiles.Save(r)
+		case *models.RefreshToken:
+			err = stores.RefreshTokens.Create(r)
+		case *models.WaterIntake:
+			// plain water counts in full, as LogWaterIntake records it
+			if r.BeverageID == nil && r.HydrationML == 0 {
+				r.HydrationML = r.AmountML
+			}
+			err = stores.Water.Create(r)
+		case *models.WeightLog:
+			err = stores.Weights.Create(r)
+		case *models.ExerciseLog:
+			err = stores.Exercises.Create(r)
+		case *models.HydrationGoal:
+			err = stores.HydrationGoals.Set(r)
+		case *models.ReminderRule:
+			err = stores.Reminders.Create(r)
+		case *models.Notification:
+			err = stores.Notifications.Create(r)
+		default:
+			t.Fatalf("seed: unsupported record %T", record)
+		}
+		if err != nil {
+			t.Fatalf("seed %T: %v", record, err)
+		}
+	}
+}
+
 func TestGetProfile_NotFound(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/profile", GetProfile)
-
-	// request with valid token but no profile
-	req := httptest.NewRequest("GET", "/profile", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusNotFound {
-		t.Errorf("Expected status 404, got %d", w.Code)
-	}
-
-	var response map[string]string
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	if response["error"] != "Profile not found" {
-		t.Errorf("Expected 'Profile not found' error, got %s", response["error"])
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/profile", NewProfileHandler(stores.Profiles, stores.Users).GetProfile)
+
+		// request with valid token but no profile
+		req := httptest.NewRequest("GET", "/profile", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+
+		var response map[string]string
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response["code"] != apierror.ProfileNotFound {
+			t.Errorf("Expected code %s, got %s", apierror.ProfileNotFound, response["code"])
+		}
+		if response["message"] != "Profile not found" {
+			t.Errorf("Expected 'Profile not found' error, got %s", response["message"])
+		}
+	})
 }
 
 func TestGetProfile_Success(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
-
-	// create a profile
-	dob := time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC)
-	profile := models.HealthProfile{
-		UserID:        1,
-		DateOfBirth:   &dob,
-		Sex:           "male",
-		HeightCM:      180,
-		WeightKG:      75,
-		ActivityLevel: "moderate",
-	}
-	db.Create(&profile)
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/profile", GetProfile)
-
-	// create request
-	req := httptest.NewRequest("GET", "/profile", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	var response models.HealthProfile
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	if response.UserID != 1 {
-		t.Errorf("Expected user_id 1, got %d", response.UserID)
-	}
-
-	if response.HeightCM != 180 {
-		t.Errorf("Expected height 180, got %f", response.HeightCM)
-	}
-
-	if response.WeightKG != 75 {
-		t.Errorf("Expected weight 75, got %f", response.WeightKG)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		// create a profile
+		dob := time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC)
+		profile := models.HealthProfile{
+			UserID:        1,
+			DateOfBirth:   &dob,
+			Sex:           "male",
+			HeightCM:      180,
+			WeightKG:      75,
+			ActivityLevel: "moderate",
+		}
+		seed(t, stores, &profile)
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/profile", NewProfileHandler(stores.Profiles, stores.Users).GetProfile)
+
+		// create request
+		req := httptest.NewRequest("GET", "/profile", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var response models.HealthProfile
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response.UserID != 1 {
+			t.Errorf("Expected user_id 1, got %d", response.UserID)
+		}
+
+		if response.HeightCM != 180 {
+			t.Errorf("Expected height 180, got %f", response.HeightCM)
+		}
+
+		if response.WeightKG != 75 {
+			t.Errorf("Expected weight 75, got %f", response.WeightKG)
+		}
+	})
 }
 
 func TestGetProfile_Unauthorized(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	setupProfileTestDB(t)
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/profile", GetProfile)
-
-	// request without token
-	req := httptest.NewRequest("GET", "/profile", nil)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusUnauthorized {
-		t.Errorf("Expected status 401, got %d", w.Code)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/profile", NewProfileHandler(stores.Profiles, stores.Users).GetProfile)
+
+		// request without token
+		req := httptest.NewRequest("GET", "/profile", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+	})
 }
 
 func TestUpdateProfile_Create(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.PUT("/profile", UpdateProfile)
-
-	// request body
-	body := map[string]interface{}{
-		"date_of_birth":  "1995-03-20T00:00:00Z",
-		"sex":            "female",
-		"height_cm":      165,
-		"weight_kg":      60,
-		"activity_level": "active",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	if response["id"] == nil {
-		t.Fatal("Expected id in response")
-	}
-
-	if response["height"] != float64(165) {
-		t.Errorf("Expected height 165, got %v", response["height"])
-	}
-
-	if response["weight"] != float64(60) {
-		t.Errorf("Expected weight 60, got %v", response["weight"])
-	}
-
-	if response["sex"] != "female" {
-		t.Errorf("Expected sex 'female', got %v", response["sex"])
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.PUT("/profile", NewProfileHandler(stores.Profiles, stores.Users).UpdateProfile)
+
+		// request body
+		body := map[string]interface{}{
+			"date_of_birth":  "1995-03-20T00:00:00Z",
+			"sex":            "female",
+			"height_cm":      165,
+			"weight_kg":      60,
+			"activity_level": "active",
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response["id"] == nil {
+			t.Fatal("Expected id in response")
+		}
+
+		if response["height"] != float64(165) {
+			t.Errorf("Expected height 165, got %v", response["height"])
+		}
+
+		if response["weight"] != float64(60) {
+			t.Errorf("Expected weight 60, got %v", response["weight"])
+		}
+
+		if response["sex"] != "female" {
+			t.Errorf("Expected sex 'female', got %v", response["sex"])
+		}
+	})
 }
 
 func TestUpdateProfile_Update(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
-
-	// create initial profile
-	dob := time.Date(1995, 3, 20, 0, 0, 0, 0, time.UTC)
-	profile := models.HealthProfile{
-		UserID:        1,
-		DateOfBirth:   &dob,
-		Sex:           "female",
-		HeightCM:      165,
-		WeightKG:      60,
-		ActivityLevel: "moderate",
-	}
-	db.Create(&profile)
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.PUT("/profile", UpdateProfile)
-
-	// update profile
-	body := map[string]interface{}{
-		"date_of_birth":  "1995-03-20T00:00:00Z",
-		"sex":            "female",
-		"height_cm":      165,
-		"weight_kg":      58,            // changed weight
-		"activity_level": "very_active", // changed activity
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	if response["weight"] != float64(58) {
-		t.Errorf("Expected updated weight 58, got %v", response["weight"])
-	}
-
-	if response["sex"] != "female" {
-		t.Errorf("Expected sex 'female', got %v", response["sex"])
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		// create initial profile
+		dob := time.Date(1995, 3, 20, 0, 0, 0, 0, time.UTC)
+		profile := models.HealthProfile{
+			UserID:        1,
+			DateOfBirth:   &dob,
+			Sex:           "female",
+			HeightCM:      165,
+			WeightKG:      60,
+			ActivityLevel: "moderate",
+		}
+		seed(t, stores, &profile)
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.PUT("/profile", NewProfileHandler(stores.Profiles, stores.Users).UpdateProfile)
+
+		// update profile
+		body := map[string]interface{}{
+			"date_of_birth":  "1995-03-20T00:00:00Z",
+			"sex":            "female",
+			"height_cm":      165,
+			"weight_kg":      58,            // changed weight
+			"activity_level": "very_active", // changed activity
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response["weight"] != float64(58) {
+			t.Errorf("Expected updated weight 58, got %v", response["weight"])
+		}
+
+		if response["sex"] != "female" {
+			t.Errorf("Expected sex 'female', got %v", response["sex"])
+		}
+	})
 }
 
 func TestUpdateProfile_InvalidSex(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.PUT("/profile", UpdateProfile)
-
-	// create request with invalid sex
-	body := map[string]interface{}{
-		"date_of_birth":  "1995-03-20T00:00:00Z",
-		"sex":            "alien", // invalid
-		"height_cm":      165,
-		"weight_kg":      60,
-		"activity_level": "active",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", w.Code)
-	}
-
-	var response map[string]string
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	if response["error"] != "Sex must be 'male' or 'female'" {
-		t.Errorf("Expected sex validation error, got %s", response["error"])
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.PUT("/profile", NewProfileHandler(stores.Profiles, stores.Users).UpdateProfile)
+
+		// create request with invalid sex
+		body := map[string]interface{}{
+			"date_of_birth":  "1995-03-20T00:00:00Z",
+			"sex":            "alien", // invalid
+			"height_cm":      165,
+			"weight_kg":      60,
+			"activity_level": "active",
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+
+		var response map[string]string
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response["message"] != "Sex must be 'male' or 'female'" {
+			t.Errorf("Expected sex validation error, got %s", response["message"])
+		}
+	})
 }
 
 func TestUpdateProfile_NegativeHeight(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.PUT("/profile", UpdateProfile)
-
-	// request with negative height
-	body := map[string]interface{}{
-		"date_of_birth":  "1995-03-20T00:00:00Z",
-		"sex":            "female",
-		"height_cm":      -10, // invalid
-		"weight_kg":      60,
-		"activity_level": "active",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", w.Code)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.PUT("/profile", NewProfileHandler(stores.Profiles, stores.Users).UpdateProfile)
+
+		// request with negative height
+		body := map[string]interface{}{
+			"date_of_birth":  "1995-03-20T00:00:00Z",
+			"sex":            "female",
+			"height_cm":      -10, // invalid
+			"weight_kg":      60,
+			"activity_level": "active",
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
 }
 
 func TestUpdateProfile_InvalidActivityLevel(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.PUT("/profile", UpdateProfile)
-
-	// request with invalid activity level
-	body := map[string]interface{}{
-		"date_of_birth":  "1995-03-20T00:00:00Z",
-		"sex":            "female",
-		"height_cm":      165,
-		"weight_kg":      60,
-		"activity_level": "super_active", // invalid
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", w.Code)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.PUT("/profile", NewProfileHandler(stores.Profiles, stores.Users).UpdateProfile)
+
+		// request with invalid activity level
+		body := map[string]interface{}{
+			"date_of_birth":  "1995-03-20T00:00:00Z",
+			"sex":            "female",
+			"height_cm":      165,
+			"weight_kg":      60,
+			"activity_level": "super_active", // invalid
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
 }
 
 func TestGetStats_Success(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
-
-	// create a profile
-	dob := time.Date(1994, 5, 15, 0, 0, 0, 0, time.UTC)
-	profile := models.HealthProfile{
-		UserID:        1,
-		DateOfBirth:   &dob,
-		Sex:           "male",
-		HeightCM:      175,
-		WeightKG:      75,
-		ActivityLevel: "moderate",
-	}
-	db.Create(&profile)
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/stats", GetStats)
-
-	// create request
-	req := httptest.NewRequest("GET", "/stats", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	var response models.ProfileStats
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	// check that stats are calculated
-	if response.Age == 0 {
-		t.Error("Expected age to be calculated")
-	}
-
-	if response.BMI == 0 {
-		t.Error("Expected BMI to be calculated")
-	}
-
-	if response.BFP == 0 {
-		t.Error("Expected BFP to be calculated")
-	}
-
-	if response.BMR == 0 {
-		t.Error("Expected BMR to be calculated")
-	}
-
-	if response.TDEE == 0 {
-		t.Error("Expected TDEE to be calculated")
-	}
-
-	// verify BMI calculation (75 / 1.75^2 = 24.49)
-	expectedBMI := 24.49
-	if response.BMI < expectedBMI-0.1 || response.BMI > expectedBMI+0.1 {
-		t.Errorf("Expected BMI around %.2f, got %.2f", expectedBMI, response.BMI)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		// create a profile
+		dob := time.Date(1994, 5, 15, 0, 0, 0, 0, time.UTC)
+		profile := models.HealthProfile{
+			UserID:        1,
+			DateOfBirth:   &dob,
+			Sex:           "male",
+			HeightCM:      175,
+			WeightKG:      75,
+			ActivityLevel: "moderate",
+		}
+		seed(t, stores, &profile)
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/stats", NewProfileHandler(stores.Profiles, stores.Users).GetStats)
+
+		// create request
+		req := httptest.NewRequest("GET", "/stats", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var response models.ProfileStats
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		// check that stats are calculated
+		if response.Age == 0 {
+			t.Error("Expected age to be calculated")
+		}
+
+		if response.BMI == 0 {
+			t.Error("Expected BMI to be calculated")
+		}
+
+		if response.BFP == 0 {
+			t.Error("Expected BFP to be calculated")
+		}
+
+		if response.BMR == 0 {
+			t.Error("Expected BMR to be calculated")
+		}
+
+		if response.TDEE == 0 {
+			t.Error("Expected TDEE to be calculated")
+		}
+
+		// verify BMI calculation (75 / 1.75^2 = 24.49)
+		expectedBMI := 24.49
+		if response.BMI < expectedBMI-0.1 || response.BMI > expectedBMI+0.1 {
+			t.Errorf("Expected BMI around %.2f, got %.2f", expectedBMI, response.BMI)
+		}
+	})
 }
 
 func TestGetStats_NoProfile(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/stats", GetStats)
-
-	// create request (no profile exists)
-	req := httptest.NewRequest("GET", "/stats", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusNotFound {
-		t.Errorf("Expected status 404, got %d", w.Code)
-	}
-
-	var response map[string]string
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	if response["error"] != "Profile not found. Please create a profile first." {
-		t.Errorf("Expected profile not found error, got %s", response["error"])
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/stats", NewProfileHandler(stores.Profiles, stores.Users).GetStats)
+
+		// create request (no profile exists)
+		req := httptest.NewRequest("GET", "/stats", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+
+		var response map[string]string
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response["code"] != apierror.ProfileNotFound {
+			t.Errorf("Expected code %s, got %s", apierror.ProfileNotFound, response["code"])
+		}
+		if response["message"] != "Profile not found. Please create a profile first." {
+			t.Errorf("Expected profile not found error, got %s", response["message"])
+		}
+	})
 }
 
 func TestGetStats_Unauthorized(t *testing.T) {
 	// setup
 	gin.SetMode(gin.TestMode)
-	setupProfileTestDB(t)
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/stats", GetStats)
-
-	// create request without token
-	req := httptest.NewRequest("GET", "/stats", nil)
-	w := httptest.NewRecorder()
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/stats", NewProfileHandler(stores.Profiles, stores.Users).GetStats)
+
+		// create request without token
+		req := httptest.NewRequest("GET", "/stats", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+	})
+}
 
-	router.ServeHTTP(w, req)
+func TestUpdateProfile_TimeZone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.PUT("/profile", NewProfileHandler(stores.Profiles, stores.Users).UpdateProfile)
+
+		update := func(timeZone string) int {
+			body := map[string]interface{}{
+				"sex":       "female",
+				"height_cm": 165,
+				"weight_kg": 60,
+				"time_zone": timeZone,
+			}
+			jsonBody, _ := json.Marshal(body)
+			req := httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		if code := update("Not/AZone"); code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for an unknown zone, got %d", code)
+		}
+		if code := update("America/Los_Angeles"); code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", code)
+		}
+		profile, _ := stores.Profiles.Get(1)
+		if profile.TimeZone != "America/Los_Angeles" {
+			t.Errorf("Expected the zone to be saved, got %q", profile.TimeZone)
+		}
+	})
+}
 
-	if w.Code != http.StatusUnauthorized {
-		t.Errorf("Expected status 401, got %d", w.Code)
-	}
+func TestUpdateProfile_CaffeineLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.PUT("/profile", NewProfileHandler(stores.Profiles, stores.Users).UpdateProfile)
+
+		update := func(limit interface{}) int {
+			body := map[string]interface{}{
+				"sex":               "female",
+				"height_cm":         165,
+				"weight_kg":         60,
+				"caffeine_limit_mg": limit,
+			}
+			jsonBody, _ := json.Marshal(body)
+			req := httptest.NewRequest("PUT", "/profile", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		if code := update(0); code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for a zero limit, got %d", code)
+		}
+		if code := update(200); code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", code)
+		}
+		if profile, _ := stores.Profiles.Get(1); profile.CaffeineLimitMG == nil || *profile.CaffeineLimitMG != 200 {
+			t.Errorf("Expected the limit to be saved, got %v", profile.CaffeineLimitMG)
+		}
+		if code := update(nil); code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", code)
+		}
+		if profile, _ := stores.Profiles.Get(1); profile.CaffeineLimitMG != nil {
+			t.Errorf("Expected null to restore the default limit, got %v", *profile.CaffeineLimitMG)
+		}
+	})
 }
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// reminderRequest creates or replaces a reminder rule
+type reminderRequest struct {
+	Kind    string `json:"kind" binding:"required,oneof=interval behind_pace"`
+	Enabled *bool  `json:"enabled"` // defaults to true
+	// StartTime and EndTime are the waking hours, HH:MM in the user's time
+	// zone. An interval rule's EndTime may be before its StartTime for
+	// hours that cross midnight; a behind_pace rule paces a calendar day's
+	// goal, so its hours cannot.
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+	// IntervalMinutes is how long without a drink before an interval
+	// reminder, and the least time between any two reminders of the rule
+	IntervalMinutes int `json:"interval_minutes" binding:"required,gte=15,lte=720"`
+	// MarginPercent is how many points of the goal a behind_pace rule lets
+	// the user fall behind an even pace
+	MarginPercent int `json:"margin_percent" binding:"gte=0,lte=100"`
+}
+
+// ReminderHandler manages hydration reminder rules. package reminders
+// sends the reminders.
+type ReminderHandler struct {
+	reminders store.ReminderStore
+}
+
+func NewReminderHandler(reminders store.ReminderStore) *ReminderHandler {
+	return &ReminderHandler{reminders: reminders}
+}
+
+// ListReminders - GET /api/water/reminders
+func (h *ReminderHandler) ListReminders(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	rules, err := h.reminders.List(userID)
+	if err != nil {
+		serverError(c, "Failed to fetch reminders", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// CreateReminder - POST /api/water/reminders
+func (h *ReminderHandler) CreateReminder(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	rule := models.ReminderRule{UserID: userID}
+	if !bindReminder(c, &rule) {
+		return
+	}
+
+	if err := h.reminders.Create(&rule); err != nil {
+		serverError(c, "Failed to save reminder", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateReminder - PUT /api/water/reminders/:id
+func (h *ReminderHandler) UpdateReminder(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	ruleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.ReminderNotFound, "Reminder not found")
+		return
+	}
+
+	rule := models.ReminderRule{ID: uint(ruleID), UserID: userID}
+	if !bindReminder(c, &rule) {
+		return
+	}
+
+	err = h.reminders.Update(&rule)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Respond(c, http.StatusNotFound, apierror.ReminderNotFound, "Reminder not found")
+		return
+	}
+	if err != nil {
+		serverError(c, "Failed to save reminder", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteReminder - DELETE /api/water/reminders/:id
+func (h *ReminderHandler) DeleteReminder(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	ruleID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.ReminderNotFound, "Reminder not found")
+		return
+	}
+
+	err = h.reminders.Delete(userID, uint(ruleID))
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Respond(c, http.StatusNotFound, apierror.ReminderNotFound, "Reminder not found")
+		return
+	}
+	if err != nil {
+		serverError(c, "Failed to delete reminder", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reminder deleted successfully"})
+}
+
+// bindReminder reads a reminderRequest into rule. It answers the request
+// itself when it returns false.
+func bindReminder(c *gin.Context, rule *models.ReminderRule) bool {
+	var req reminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return false
+	}
+
+	start, err := time.Parse(utils.TimeOfDayLayout, req.StartTime)
+	if err != nil {
+		apierror.Invalid(c, "start_time", "time", "Invalid time. Use HH:MM, e.g. 08:00")
+		return false
+	}
+	end, err := time.Parse(utils.TimeOfDayLayout, req.EndTime)
+	if err != nil {
+		apierror.Invalid(c, "end_time", "time", "Invalid time. Use HH:MM, e.g. 22:00")
+		return false
+	}
+	if end.Equal(start) {
+		apierror.Invalid(c, "end_time", "nefield", "end_time must differ from start_time")
+		return false
+	}
+	if end.Before(start) && req.Kind == models.ReminderBehindPace {
+		apierror.Invalid(c, "end_time", "gtfield", "end_time must be after start_time; behind_pace hours cannot cross midnight")
+		return false
+	}
+
+	rule.Kind = req.Kind
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	rule.StartTime = start.Format(utils.TimeOfDayLayout)
+	rule.EndTime = end.Format(utils.TimeOfDayLayout)
+	rule.IntervalMinutes = req.IntervalMinutes
+	rule.MarginPercent = req.MarginPercent
+	return true
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+func setupReminderRouter(stores store.Stores) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	reminders := NewReminderHandler(stores.Reminders)
+
+	router := gin.New()
+	router.Use(middleware.AuthMiddleware())
+	router.GET("/water/reminders", reminders.ListReminders)
+	router.POST("/water/reminders", reminders.CreateReminder)
+	router.PUT("/water/reminders/:id", reminders.UpdateReminder)
+	router.DELETE("/water/reminders/:id", reminders.DeleteReminder)
+	return router
+}
+
+func TestReminders_CRUD(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		router := setupReminderRouter(stores)
+
+		w := sendJSON(router, "POST", "/water/reminders", token, `{"kind": "interval", "start_time": "8:00", "end_time": "22:00", "interval_minutes": 90}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var created models.ReminderRule
+		json.Unmarshal(w.Body.Bytes(), &created)
+		if created.ID == 0 || !created.Enabled || created.StartTime != "08:00" || created.IntervalMinutes != 90 {
+			t.Errorf("Expected an enabled rule from 08:00, got %+v", created)
+		}
+		path := fmt.Sprintf("/water/reminders/%d", created.ID)
+
+		w = sendJSON(router, "PUT", path, token, `{"kind": "behind_pace", "enabled": false, "start_time": "09:00", "end_time": "21:00", "interval_minutes": 60, "margin_percent": 15}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		w = sendJSON(router, "GET", "/water/reminders", token, "")
+		var rules []models.ReminderRule
+		json.Unmarshal(w.Body.Bytes(), &rules)
+		if len(rules) != 1 || rules[0].Kind != models.ReminderBehindPace || rules[0].Enabled || rules[0].MarginPercent != 15 {
+			t.Errorf("Expected the updated, disabled rule, got %+v", rules)
+		}
+
+		w = sendJSON(router, "DELETE", path, token, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+		if rules, _ := stores.Reminders.List(1); len(rules) != 0 {
+			t.Errorf("Expected the rule to be deleted, got %+v", rules)
+		}
+	})
+}
+
+func TestReminders_Validation(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		router := setupReminderRouter(stores)
+
+		tests := []struct {
+			name  string
+			body  string
+			field string
+			rule  string
+		}{
+			{"unknown kind", `{"kind": "hourly", "start_time": "08:00", "end_time": "22:00", "interval_minutes": 60}`, "kind", "oneof"},
+			{"short interval", `{"kind": "interval", "start_time": "08:00", "end_time": "22:00", "interval_minutes": 5}`, "interval_minutes", "gte"},
+			{"bad start", `{"kind": "interval", "start_time": "8am", "end_time": "22:00", "interval_minutes": 60}`, "start_time", "time"},
+			{"bad end", `{"kind": "interval", "start_time": "08:00", "end_time": "24:00", "interval_minutes": 60}`, "end_time", "time"},
+			{"end equals start", `{"kind": "interval", "start_time": "08:00", "end_time": "08:00", "interval_minutes": 60}`, "end_time", "nefield"},
+			{"behind_pace past midnight", `{"kind": "behind_pace", "start_time": "22:00", "end_time": "08:00", "interval_minutes": 60}`, "end_time", "gtfield"},
+			{"margin over 100", `{"kind": "behind_pace", "start_time": "08:00", "end_time": "22:00", "interval_minutes": 60, "margin_percent": 101}`, "margin_percent", "lte"},
+		}
+
+		for _, tt := range tests {
+			w := sendJSON(router, "POST", "/water/reminders", token, tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected status 400, got %d. Body: %s", tt.name, w.Code, w.Body.String())
+				continue
+			}
+			var response apierror.Error
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if len(response.Details) != 1 || response.Details[0].Field != tt.field || response.Details[0].Rule != tt.rule {
+				t.Errorf("%s: expected %s to fail %s, got %+v", tt.name, tt.field, tt.rule, response.Details)
+			}
+		}
+
+		if rules, _ := stores.Reminders.List(1); len(rules) != 0 {
+			t.Errorf("Expected no rules to be saved, got %+v", rules)
+		}
+	})
+}
+
+func TestReminders_IntervalPastMidnight(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		router := setupReminderRouter(stores)
+
+		w := sendJSON(router, "POST", "/water/reminders", token, `{"kind": "interval", "start_time": "22:00", "end_time": "06:00", "interval_minutes": 60}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		if rules, _ := stores.Reminders.List(1); len(rules) != 1 || rules[0].StartTime != "22:00" || rules[0].EndTime != "06:00" {
+			t.Errorf("Expected the overnight rule to be saved, got %+v", rules)
+		}
+	})
+}
+
+func TestReminders_OtherUsersRule(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		createTestUser(t, stores, 1, "owner")
+		token := createTestUser(t, stores, 2, "other")
+		router := setupReminderRouter(stores)
+		rule := models.ReminderRule{UserID: 1, Kind: models.ReminderInterval, Enabled: true, StartTime: "08:00", EndTime: "22:00", IntervalMinutes: 90}
+		stores.Reminders.Create(&rule)
+		path := fmt.Sprintf("/water/reminders/%d", rule.ID)
+
+		body := `{"kind": "interval", "start_time": "08:00", "end_time": "22:00", "interval_minutes": 30}`
+		for _, req := range []struct{ method, path string }{
+			{"PUT", path},
+			{"DELETE", path},
+			{"DELETE", "/water/reminders/abc"},
+		} {
+			w := sendJSON(router, req.method, req.path, token, body)
+			if w.Code != http.StatusNotFound {
+				t.Errorf("%s %s: expected status 404, got %d", req.method, req.path, w.Code)
+			}
+		}
+
+		if unchanged, _ := stores.Reminders.Get(1, rule.ID); unchanged.IntervalMinutes != 90 {
+			t.Errorf("Expected the owner's rule to be unchanged, got %+v", unchanged)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database/dbtest"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database/migrations"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+// testBackends open empty stores for one test. Handler tests run against
+// each, so the in-memory fakes cannot drift from the database.
+var testBackends = []struct {
+	name string
+	open func(t *testing.T) store.Stores
+}{
+	{"memory", func(*testing.T) store.Stores { return store.NewMemoryStores() }},
+	{"database", openDatabaseStores},
+}
+
+// openDatabaseStores returns the stores on a migrated test database
+func openDatabaseStores(t *testing.T) store.Stores {
+	db := dbtest.Open(t)
+	// migrated rather than auto-migrated: the built-in beverages come from
+	// a migration
+	if _, err := migrations.New(db).Up(0, false); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+	return database.NewStores(db)
+}
+
+// forEachBackend runs test as a subtest per backend
+func forEachBackend(t *testing.T, test func(t *testing.T, stores store.Stores)) {
+	for _, backend := range testBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			test(t, backend.open(t))
+		})
+	}
+}
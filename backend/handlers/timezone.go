@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// userLocation is the time zone a request counts days in: the tz query
+// parameter if given, else the zone on the user's profile, else UTC. It
+// answers the request itself when it returns false.
+func userLocation(c *gin.Context, profiles store.ProfileStore, userID uint) (*time.Location, bool) {
+	if name, ok := c.GetQuery("tz"); ok {
+		loc, err := utils.LoadTimeZone(name)
+		if err != nil {
+			apierror.Invalid(c, "tz", "timezone", "Unknown time zone. Use an IANA name such as America/Los_Angeles")
+			return nil, false
+		}
+		return loc, true
+	}
+
+	profile, err := profiles.Get(userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return time.UTC, true
+	}
+	if err != nil {
+		serverError(c, "Failed to retrieve health profile", err)
+		return nil, false
+	}
+
+	loc, err := utils.LoadTimeZone(profile.TimeZone)
+	if err != nil {
+		// saved zones are validated, but the zone database can drop names
+		middleware.Logger(c).Warn("unknown profile time zone", "time_zone", profile.TimeZone, "error", err)
+		return time.UTC, true
+	}
+	return loc, true
+}
+
+// dayInLocation reads the date query parameter as a day in loc, defaulting
+// to today there
+func dayInLocation(c *gin.Context, loc *time.Location) (date string, start, end time.Time, ok bool) {
+	date = c.DefaultQuery("date", utils.Today(time.Now(), loc))
+	start, end, err := utils.DayBounds(date, loc)
+	if err != nil {
+		apierror.Invalid(c, "date", "date", "Invalid date format. Use YYYY-MM-DD")
+		return "", time.Time{}, time.Time{}, false
+	}
+	return date, start, end, true
+}
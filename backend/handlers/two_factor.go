@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/metrics"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// how many recovery codes a user gets at a time
+const recoveryCodeCount = 10
+
+type totpCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type disableTwoFactorRequest struct {
+	Password     string `json:"password" binding:"required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type loginTwoFactorRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// TwoFactorHandler lets users turn TOTP two-factor authentication on and
+// off. The second login step is AuthHandler.LoginTwoFactor.
+type TwoFactorHandler struct {
+	auth   *AuthHandler
+	issuer string // shown next to the account in authenticator apps
+}
+
+func NewTwoFactorHandler(auth *AuthHandler, issuer string) *TwoFactorHandler {
+	return &TwoFactorHandler{auth: auth, issuer: issuer}
+}
+
+// twoFactorEnabled reports whether the second login step applies to a user
+func (h *AuthHandler) twoFactorEnabled(userID uint) (bool, error) {
+	twoFactor, err := h.twoFactors.Get(userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil && twoFactor.Enabled, err
+}
+
+// sendMFAChallenge answers a correct password from a user with two-factor
+// authentication enabled
+func sendMFAChallenge(c *gin.Context, user models.User) {
+	token, err := utils.GenerateMFAChallengeToken(user.ID, user.Username)
+	if err != nil {
+		serverError(c, "Failed to generate token.", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"mfa_required": true,
+		"mfa_token":    token,
+		"expires_in":   int(utils.MFAChallengeTTL.Seconds()),
+	})
+}
+
+// verifySecondFactor accepts either a current TOTP code or an unused
+// recovery code. Both are consumed by the store, so two requests racing
+// with the same code cannot both succeed.
+func (h *AuthHandler) verifySecondFactor(userID uint, code, recoveryCode string) (bool, error) {
+	if recoveryCode != "" {
+		return h.twoFactors.UseRecoveryCode(userID, utils.HashRecoveryCode(recoveryCode))
+	}
+
+	twoFactor, err := h.twoFactors.Get(userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := utils.ValidateTOTP(twoFactor.Secret, code, time.Now(), twoFactor.LastStep)
+	if !ok {
+		return false, nil
+	}
+	return h.twoFactors.UseStep(userID, step)
+}
+
+// replaceRecoveryCodes invalidates a user's recovery codes and returns a
+// fresh set
+func (h *AuthHandler) replaceRecoveryCodes(userID uint) ([]string, error) {
+	codes, hashes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.twoFactors.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// SetupTwoFactor - POST /api/auth/2fa/setup
+// Generates a new TOTP secret. It only takes effect once EnableTwoFactor
+// confirms a code from it.
+func (h *TwoFactorHandler) SetupTwoFactor(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	user, err := h.auth.users.Get(userID)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.UserNotFound, "User not found.")
+		return
+	}
+
+	if enabled, err := h.auth.twoFactorEnabled(userID); err != nil {
+		serverError(c, "Failed to set up two-factor authentication.", err)
+		return
+	} else if enabled {
+		apierror.Respond(c, http.StatusConflict, apierror.TwoFactorAlreadyEnabled, "Two-factor authentication is already enabled.")
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		serverError(c, "Failed to set up two-factor authentication.", err)
+		return
+	}
+
+	// replaces any earlier setup that was never confirmed
+	twoFactor := models.TwoFactor{UserID: userID, Secret: secret}
+	if err := h.auth.twoFactors.Save(&twoFactor); err != nil {
+		serverError(c, "Failed to set up two-factor authentication.", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"secret":      secret,
+		"otpauth_uri": utils.TOTPProvisioningURI(h.issuer, user.Username, secret),
+	})
+}
+
+// EnableTwoFactor - POST /api/auth/2fa/enable
+// Confirms the secret from SetupTwoFactor with a code and returns the
+// recovery codes. They are shown only this once.
+func (h *TwoFactorHandler) EnableTwoFactor(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	var req totpCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+
+	twoFactor, err := h.auth.twoFactors.Get(userID)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Respond(c, http.StatusBadRequest, apierror.TwoFactorNotSetUp, "Set up two-factor authentication first.")
+		return
+	}
+	if err != nil {
+		serverError(c, "Failed to enable two-factor authentication.", err)
+		return
+	}
+	if twoFactor.Enabled {
+		apierror.Respond(c, http.StatusConflict, apierror.TwoFactorAlreadyEnabled, "Two-factor authentication is already enabled.")
+		return
+	}
+
+	valid, err := h.auth.verifySecondFactor(userID, req.Code, "")
+	if err != nil {
+		serverError(c, "Failed to enable two-factor authentication.", err)
+		return
+	}
+	if !valid {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidMFACode, "Invalid code.")
+		return
+	}
+
+	if err := h.auth.twoFactors.Enable(userID); err != nil {
+		serverError(c, "Failed to enable two-factor authentication.", err)
+		return
+	}
+
+	codes, err := h.auth.replaceRecoveryCodes(userID)
+	if err != nil {
+		serverError(c, "Failed to generate recovery codes.", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled",
+		"recovery_codes": codes,
+	})
+}
+
+// DisableTwoFactor - POST /api/auth/2fa/disable
+// Requires the password and a TOTP or recovery code.
+func (h *TwoFactorHandler) DisableTwoFactor(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	var req disableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+
+	user, err := h.auth.users.Get(userID)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.UserNotFound, "User not found.")
+		return
+	}
+	if !utils.CheckPasswordHash(user.PasswordHash, req.Password) {
+		apierror.Respond(c, http.StatusForbidden, apierror.IncorrectPassword, "Password is incorrect.")
+		return
+	}
+
+	if enabled, err := h.auth.twoFactorEnabled(userID); err != nil {
+		serverError(c, "Failed to disable two-factor authentication.", err)
+		return
+	} else if !enabled {
+		apierror.Respond(c, http.StatusBadRequest, apierror.TwoFactorNotEnabled, "Two-factor authentication is not enabled.")
+		return
+	}
+
+	valid, err := h.auth.verifySecondFactor(userID, req.Code, req.RecoveryCode)
+	if err != nil {
+		serverError(c, "Failed to disable two-factor authentication.", err)
+		return
+	}
+	if !valid {
+		apierror.Respond(c, http.StatusForbidden, apierror.InvalidMFACode, "Invalid code.")
+		return
+	}
+
+	if err := h.auth.twoFactors.Delete(userID); err != nil {
+		serverError(c, "Failed to disable two-factor authentication.", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes - POST /api/auth/2fa/recovery-codes
+// Replaces all recovery codes after checking a TOTP code.
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	var req totpCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+
+	if enabled, err := h.auth.twoFactorEnabled(userID); err != nil {
+		serverError(c, "Failed to generate recovery codes.", err)
+		return
+	} else if !enabled {
+		apierror.Respond(c, http.StatusBadRequest, apierror.TwoFactorNotEnabled, "Two-factor authentication is not enabled.")
+		return
+	}
+
+	valid, err := h.auth.verifySecondFactor(userID, req.Code, "")
+	if err != nil {
+		serverError(c, "Failed to generate recovery codes.", err)
+		return
+	}
+	if !valid {
+		apierror.Respond(c, http.StatusForbidden, apierror.InvalidMFACode, "Invalid code.")
+		return
+	}
+
+	codes, err := h.auth.replaceRecoveryCodes(userID)
+	if err != nil {
+		serverError(c, "Failed to generate recovery codes.", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// LoginTwoFactor - POST /api/auth/login/2fa
+// Second login step: exchanges the challenge token from Login plus a TOTP
+// or recovery code for a session. Wrong codes count towards the same
+// lockout as wrong passwords.
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req loginTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+	if req.Code == "" && req.RecoveryCode == "" {
+		apierror.Invalid(c, "code", "required", "A code or recovery code is required.")
+		return
+	}
+
+	claims, err := utils.ValidateMFAChallengeToken(req.MFAToken)
+	if err != nil {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.InvalidMFAChallenge, "Invalid or expired challenge token.")
+		return
+	}
+
+	user, err := h.users.Get(claims.UserID)
+	if err != nil || user.DeletionRequestedAt != nil {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.InvalidMFAChallenge, "Invalid or expired challenge token.")
+		return
+	}
+	if blockedLogin(c, user) {
+		return
+	}
+
+	if loginLocked(c, user.Username) {
+		return
+	}
+
+	valid, err := h.verifySecondFactor(user.ID, req.Code, req.RecoveryCode)
+	if err != nil {
+		serverError(c, "Failed to verify code.", err)
+		return
+	}
+	if !valid {
+		h.recordLoginFailure(c, user.Username)
+		metrics.AuthFailure(metrics.SourceLogin, "bad_mfa_code")
+		apierror.Respond(c, http.StatusUnauthorized, apierror.InvalidMFACode, "Invalid code.")
+		return
+	}
+	loginThrottle.Success(user.Username)
+
+	tokens, err := h.issueTokens(user)
+	if err != nil {
+		serverError(c, "Failed to generate token.", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.loginResponse(user, tokens))
+}
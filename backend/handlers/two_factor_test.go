@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+func setupTwoFactorRouter(t *testing.T, stores store.Stores) *gin.Engine {
+	router, auth := setupSessionRouter(t, stores)
+	twoFactor := NewTwoFactorHandler(auth, "Fitness Tracker")
+	router.POST("/login/2fa", auth.LoginTwoFactor)
+	protected := router.Group("/2fa")
+	protected.Use(middleware.AuthMiddleware())
+	protected.POST("/setup", twoFactor.SetupTwoFactor)
+	protected.POST("/enable", twoFactor.EnableTwoFactor)
+	protected.POST("/disable", twoFactor.DisableTwoFactor)
+	protected.POST("/recovery-codes", twoFactor.RegenerateRecoveryCodes)
+	return router
+}
+
+// enrollTwoFactor registers a user and turns on 2FA, returning the secret
+// and recovery codes
+func enrollTwoFactor(t *testing.T, router *gin.Engine) (string, []string) {
+	session := registerTestUser(t, router, "testuser")
+	token := session["token"].(string)
+
+	w := jsonRequest(router, "POST", "/2fa/setup", token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Setup failed: %d %s", w.Code, w.Body.String())
+	}
+	var setup struct {
+		Secret     string `json:"secret"`
+		OTPAuthURI string `json:"otpauth_uri"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &setup)
+
+	// the previous step, so the test can still use the current one
+	code, _ := utils.TOTPCode(setup.Secret, utils.TOTPStep(time.Now())-1)
+	w = jsonRequest(router, "POST", "/2fa/enable", token, gin.H{"code": code})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Enable failed: %d %s", w.Code, w.Body.String())
+	}
+	var enabled struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &enabled)
+	return setup.Secret, enabled.RecoveryCodes
+}
+
+// loginChallenge logs in with the password and returns the MFA token
+func loginChallenge(t *testing.T, router *gin.Engine) string {
+	w := postLogin(router, "testuser", "password123")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Login failed: %d %s", w.Code, w.Body.String())
+	}
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response["mfa_required"] != true || response["token"] != nil {
+		t.Fatalf("Expected an MFA challenge instead of a session, got %v", response)
+	}
+	return response["mfa_token"].(string)
+}
+
+func TestTwoFactor_EnableRequiresValidCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupTwoFactorRouter(t, stores)
+		session := registerTestUser(t, router, "testuser")
+		token := session["token"].(string)
+
+		if w := jsonRequest(router, "POST", "/2fa/setup", token, nil); w.Code != http.StatusOK {
+			t.Fatalf("Setup failed: %d", w.Code)
+		}
+		if w := jsonRequest(router, "POST", "/2fa/enable", token, gin.H{"code": "000000"}); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+
+		// an unconfirmed setup does not change how login works
+		w := postLogin(router, "testuser", "password123")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Login failed: %d", w.Code)
+		}
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if response["token"] == nil {
+			t.Errorf("Expected a session without 2FA, got %v", response)
+		}
+	})
+}
+
+func TestLoginTwoFactor_WithTOTPCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupTwoFactorRouter(t, stores)
+		secret, _ := enrollTwoFactor(t, router)
+
+		mfaToken := loginChallenge(t, router)
+
+		// the challenge token is not a session
+		if w := jsonRequest(router, "POST", "/2fa/setup", mfaToken, nil); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected the challenge token to be refused, got %d", w.Code)
+		}
+
+		if w := jsonRequest(router, "POST", "/login/2fa", "", gin.H{"mfa_token": mfaToken, "code": "000000"}); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for a wrong code, got %d", w.Code)
+		}
+
+		code, _ := utils.TOTPCode(secret, utils.TOTPStep(time.Now()))
+		w := jsonRequest(router, "POST", "/login/2fa", "", gin.H{"mfa_token": mfaToken, "code": code})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d %s", w.Code, w.Body.String())
+		}
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if response["token"] == nil || response["refresh_token"] == nil {
+			t.Errorf("Expected a session, got %v", response)
+		}
+
+		// the same code cannot be used twice
+		if w := jsonRequest(router, "POST", "/login/2fa", "", gin.H{"mfa_token": mfaToken, "code": code}); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected a replayed code to be refused, got %d", w.Code)
+		}
+	})
+}
+
+func TestLoginTwoFactor_RecoveryCodeIsSingleUse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupTwoFactorRouter(t, stores)
+		_, recoveryCodes := enrollTwoFactor(t, router)
+		if len(recoveryCodes) != recoveryCodeCount {
+			t.Fatalf("Expected %d recovery codes, got %d", recoveryCodeCount, len(recoveryCodes))
+		}
+
+		user, _ := stores.Users.GetByUsername("testuser")
+		if used, _ := stores.TwoFactors.UseRecoveryCode(user.ID, recoveryCodes[0]); used {
+			t.Fatal("Recovery codes must not be stored in plain text")
+		}
+
+		mfaToken := loginChallenge(t, router)
+		body := gin.H{"mfa_token": mfaToken, "recovery_code": recoveryCodes[0]}
+		if w := jsonRequest(router, "POST", "/login/2fa", "", body); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d %s", w.Code, w.Body.String())
+		}
+		if w := jsonRequest(router, "POST", "/login/2fa", "", body); w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected a used recovery code to be refused, got %d", w.Code)
+		}
+	})
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := setupTwoFactorRouter(t, stores)
+		secret, recoveryCodes := enrollTwoFactor(t, router)
+
+		user, _ := stores.Users.GetByUsername("testuser")
+		token, _ := utils.GenerateToken(user.ID, user.Username)
+		code, _ := utils.TOTPCode(secret, utils.TOTPStep(time.Now()))
+
+		if w := jsonRequest(router, "POST", "/2fa/disable", token, gin.H{"password": "wrongpassword", "code": code}); w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 for a wrong password, got %d", w.Code)
+		}
+		if w := jsonRequest(router, "POST", "/2fa/disable", token, gin.H{"password": "password123", "code": code}); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d %s", w.Code, w.Body.String())
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(postLogin(router, "testuser", "password123").Body.Bytes(), &response)
+		if response["token"] == nil {
+			t.Errorf("Expected a session once 2FA is off, got %v", response)
+		}
+
+		if used, _ := stores.TwoFactors.UseRecoveryCode(user.ID, utils.HashRecoveryCode(recoveryCodes[0])); used {
+			t.Error("Expected recovery codes to be deleted")
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// patchWaterRequest changes only the fields it has. "beverage_id": null
+// makes the log plain water.
+type patchWaterRequest struct {
+	AmountML   *int       `json:"amount_ml"`
+	LoggedAt   *time.Time `json:"logged_at"`
+	BeverageID *uint      `json:"beverage_id"`
+}
+
+// UpdateWaterLog - PUT /api/water/:id
+// Replaces a log like POST /api/water would log it, except that a missing
+// logged_at keeps the log's time.
+func (h *WaterHandler) UpdateWaterLog(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	previous, ok := h.ownWaterLog(c, userID)
+	if !ok {
+		return
+	}
+
+	var req logWaterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+
+	edited := previous
+	edited.AmountML = req.AmountML
+	edited.BeverageID = req.BeverageID
+	if !req.LoggedAt.IsZero() {
+		edited.LoggedAt = req.LoggedAt
+	}
+	h.saveWaterEdit(c, previous, edited)
+}
+
+// PatchWaterLog - PATCH /api/water/:id
+func (h *WaterHandler) PatchWaterLog(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	previous, ok := h.ownWaterLog(c, userID)
+	if !ok {
+		return
+	}
+
+	var req patchWaterRequest
+	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+	// a null beverage_id and a missing one both decode to nil
+	var fields map[string]json.RawMessage
+	if err := c.ShouldBindBodyWith(&fields, binding.JSON); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+
+	edited := previous
+	if req.AmountML != nil {
+		edited.AmountML = *req.AmountML
+	}
+	if req.LoggedAt != nil {
+		edited.LoggedAt = *req.LoggedAt
+	}
+	if _, ok := fields["beverage_id"]; ok {
+		edited.BeverageID = req.BeverageID
+	}
+	h.saveWaterEdit(c, previous, edited)
+}
+
+// GetWaterLogHistory - GET /api/water/:id/history
+func (h *WaterHandler) GetWaterLogHistory(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	current, ok := h.ownWaterLog(c, userID)
+	if !ok {
+		return
+	}
+
+	edits, err := h.water.Edits(userID, current.ID)
+	if err != nil {
+		serverError(c, "Failed to fetch water log history", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WaterIntakeHistory{Current: current, Edits: edits})
+}
+
+// ownWaterLog fetches the log in the path, responding 404 when it does not
+// exist or belongs to another user
+func (h *WaterHandler) ownWaterLog(c *gin.Context, userID uint) (models.WaterIntake, bool) {
+	logID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.WaterLogNotFound, "Water log not found")
+		return models.WaterIntake{}, false
+	}
+
+	log, err := h.water.Get(userID, uint(logID))
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Respond(c, http.StatusNotFound, apierror.WaterLogNotFound, "Water log not found")
+		return models.WaterIntake{}, false
+	}
+	if err != nil {
+		serverError(c, "Failed to fetch water log", err)
+		return models.WaterIntake{}, false
+	}
+	return log, true
+}
+
+// saveWaterEdit validates an edited log and saves it. A log that keeps its
+// beverage keeps the rates recorded when it was logged; a new beverage is
+// looked up in the catalog. An edit that changes nothing is not recorded.
+func (h *WaterHandler) saveWaterEdit(c *gin.Context, previous, edited models.WaterIntake) {
+	if !validWaterLog(c, edited.AmountML, edited.LoggedAt) {
+		return
+	}
+
+	sameBeverage := previous.BeverageID == nil && edited.BeverageID == nil ||
+		previous.BeverageID != nil && edited.BeverageID != nil && *previous.BeverageID == *edited.BeverageID
+	if sameBeverage {
+		if edited.AmountML == previous.AmountML && edited.LoggedAt.Equal(previous.LoggedAt) {
+			c.JSON(http.StatusOK, previous)
+			return
+		}
+		edited.HydrationML, edited.CaffeineMG, edited.Kcal = utils.ScaleIntake(previous, edited.AmountML)
+	} else {
+		beverage, ok := h.beverage(c, edited.UserID, edited.BeverageID)
+		if !ok {
+			return
+		}
+		edited.HydrationML, edited.CaffeineMG, edited.Kcal = utils.BeverageIntake(edited.AmountML, beverage)
+	}
+
+	err := h.water.Update(&edited)
+	if errors.Is(err, store.ErrNotFound) { // deleted meanwhile
+		apierror.Respond(c, http.StatusNotFound, apierror.WaterLogNotFound, "Water log not found")
+		return
+	}
+	if err != nil {
+		serverError(c, "Failed to update water log", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, edited)
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+func setupWaterEditRouter(stores store.Stores) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	water := NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages)
+
+	router := gin.New()
+	router.Use(middleware.AuthMiddleware())
+	router.PUT("/water/:id", water.UpdateWaterLog)
+	router.PATCH("/water/:id", water.PatchWaterLog)
+	router.GET("/water/:id/history", water.GetWaterLogHistory)
+	return router
+}
+
+func TestUpdateWaterLog_KeepsHistory(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		router := setupWaterEditRouter(stores)
+		loggedAt := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+		log := models.WaterIntake{UserID: 1, AmountML: 2500, LoggedAt: loggedAt}
+		seed(t, stores, &log)
+		path := fmt.Sprintf("/water/%d", log.ID)
+
+		w := sendJSON(router, "PUT", path, token, `{"amount_ml": 250}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var updated models.WaterIntake
+		json.Unmarshal(w.Body.Bytes(), &updated)
+		if updated.ID != log.ID || updated.AmountML != 250 || updated.HydrationML != 250 || !updated.LoggedAt.Equal(loggedAt) {
+			t.Errorf("Expected the log to be 250ml at its original time, got %+v", updated)
+		}
+		if !updated.CreatedAt.Equal(log.CreatedAt) || !updated.UpdatedAt.After(log.UpdatedAt) {
+			t.Errorf("Expected CreatedAt to stay and UpdatedAt to move, got %v and %v", updated.CreatedAt, updated.UpdatedAt)
+		}
+
+		sendJSON(router, "PATCH", path, token, `{"logged_at": "2026-03-10T07:30:00Z"}`)
+
+		w = sendJSON(router, "GET", path+"/history", token, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var history models.WaterIntakeHistory
+		json.Unmarshal(w.Body.Bytes(), &history)
+		if history.Current.AmountML != 250 || !history.Current.LoggedAt.Equal(loggedAt.Add(-30*time.Minute)) {
+			t.Errorf("Expected the current version to be 250ml at 07:30, got %+v", history.Current)
+		}
+		if len(history.Edits) != 2 || history.Edits[0].AmountML != 250 || !history.Edits[0].LoggedAt.Equal(loggedAt) ||
+			history.Edits[1].AmountML != 2500 {
+			t.Errorf("Expected the 250ml and 2500ml versions, newest first, got %+v", history.Edits)
+		}
+
+		// saving the same values again is not an edit
+		sendJSON(router, "PATCH", path, token, `{"amount_ml": 250}`)
+		if edits, _ := stores.Water.Edits(1, log.ID); len(edits) != 2 {
+			t.Errorf("Expected an unchanged log to leave no edit, got %d edits", len(edits))
+		}
+	})
+}
+
+func TestUpdateWaterLog_Validation(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		router := setupWaterEditRouter(stores)
+		log := models.WaterIntake{UserID: 1, AmountML: 250, LoggedAt: time.Now().Add(-time.Hour)}
+		seed(t, stores, &log)
+		path := fmt.Sprintf("/water/%d", log.ID)
+		future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+		tests := []struct {
+			name   string
+			method string
+			body   string
+			field  string
+			rule   string
+		}{
+			{"zero amount", "PUT", `{"amount_ml": 0}`, "amount_ml", "required"},
+			{"negative amount", "PATCH", `{"amount_ml": -5}`, "amount_ml", "gt"},
+			{"too much", "PUT", `{"amount_ml": 5001}`, "amount_ml", "lte"},
+			{"future", "PATCH", `{"logged_at": "` + future + `"}`, "logged_at", "past"},
+			{"unknown beverage", "PATCH", `{"beverage_id": 9999}`, "beverage_id", "exists"},
+		}
+
+		for _, tt := range tests {
+			w := sendJSON(router, tt.method, path, token, tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected status 400, got %d. Body: %s", tt.name, w.Code, w.Body.String())
+				continue
+			}
+			var response apierror.Error
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if len(response.Details) != 1 || response.Details[0].Field != tt.field || response.Details[0].Rule != tt.rule {
+				t.Errorf("%s: expected %s to fail %s, got %+v", tt.name, tt.field, tt.rule, response.Details)
+			}
+		}
+
+		if edits, _ := stores.Water.Edits(1, log.ID); len(edits) != 0 {
+			t.Errorf("Expected rejected edits to leave no history, got %+v", edits)
+		}
+	})
+}
+
+func TestUpdateWaterLog_OtherUsersLog(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		createTestUser(t, stores, 1, "owner")
+		token := createTestUser(t, stores, 2, "other")
+		router := setupWaterEditRouter(stores)
+		log := models.WaterIntake{UserID: 1, AmountML: 250, LoggedAt: time.Now().Add(-time.Hour)}
+		seed(t, stores, &log)
+		path := fmt.Sprintf("/water/%d", log.ID)
+
+		for _, req := range []struct{ method, path, body string }{
+			{"PUT", path, `{"amount_ml": 500}`},
+			{"PATCH", path, `{"amount_ml": 500}`},
+			{"GET", path + "/history", ""},
+			{"PUT", "/water/abc", `{"amount_ml": 500}`},
+		} {
+			w := sendJSON(router, req.method, req.path, token, req.body)
+			if w.Code != http.StatusNotFound {
+				t.Errorf("%s %s: expected status 404, got %d", req.method, req.path, w.Code)
+			}
+		}
+
+		if unchanged, _ := stores.Water.Get(1, log.ID); unchanged.AmountML != 250 {
+			t.Errorf("Expected the owner's log to be unchanged, got %+v", unchanged)
+		}
+	})
+}
+
+func TestUpdateWaterLog_Beverages(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		router := setupWaterEditRouter(stores)
+		coffee := builtinBeverage(t, stores, "Coffee")
+		// logged when coffee counted for less than the catalog says now
+		log := models.WaterIntake{UserID: 1, AmountML: 200, BeverageID: &coffee.ID, HydrationML: 100, CaffeineMG: 60, Kcal: 4, LoggedAt: time.Now().Add(-time.Hour)}
+		seed(t, stores, &log)
+		path := fmt.Sprintf("/water/%d", log.ID)
+
+		var updated models.WaterIntake
+		w := sendJSON(router, "PATCH", path, token, `{"amount_ml": 400}`)
+		json.Unmarshal(w.Body.Bytes(), &updated)
+		if updated.BeverageID == nil || *updated.BeverageID != coffee.ID || updated.HydrationML != 200 || updated.CaffeineMG != 120 || updated.Kcal != 8 {
+			t.Errorf("Expected the recorded coffee rates for 400ml, got %+v", updated)
+		}
+
+		tea := builtinBeverage(t, stores, "Tea")
+		w = sendJSON(router, "PATCH", path, token, fmt.Sprintf(`{"beverage_id": %d}`, tea.ID))
+		json.Unmarshal(w.Body.Bytes(), &updated)
+		if updated.HydrationML != 380 || updated.CaffeineMG != 80 || updated.Kcal != 4 {
+			t.Errorf("Expected 400ml of tea from the catalog, got %+v", updated)
+		}
+
+		w = sendJSON(router, "PATCH", path, token, `{"beverage_id": null}`)
+		json.Unmarshal(w.Body.Bytes(), &updated)
+		if updated.BeverageID != nil || updated.HydrationML != 400 || updated.CaffeineMG != 0 || updated.Kcal != 0 {
+			t.Errorf("Expected null to make the log plain water, got %+v", updated)
+		}
+
+		w = sendJSON(router, "PUT", path, token, fmt.Sprintf(`{"amount_ml": 300, "beverage_id": %d}`, coffee.ID))
+		json.Unmarshal(w.Body.Bytes(), &updated)
+		if updated.HydrationML != 270 || updated.CaffeineMG != 120 {
+			t.Errorf("Expected 300ml of coffee from the catalog, got %+v", updated)
+		}
+	})
+}
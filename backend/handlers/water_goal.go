@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+type setWaterGoalRequest struct {
+	// GoalML is a fixed daily goal; null or missing switches to the
+	// automatic goal
+	GoalML *int `json:"goal_ml" binding:"omitempty,gt=0,lte=10000"`
+	// EffectiveFrom is the first day of the goal, YYYY-MM-DD in the user's
+	// time zone. It defaults to today and cannot be in the past, so days
+	// already counted keep their goal.
+	EffectiveFrom string `json:"effective_from"`
+}
+
+// GetWaterGoal - GET /api/water/goal?date=YYYY-MM-DD&tz=Area/City
+// The date defaults to today in the user's time zone.
+func (h *WaterHandler) GetWaterGoal(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	loc, ok := userLocation(c, h.profiles, userID)
+	if !ok {
+		return
+	}
+	date, startOfDay, endOfDay, ok := dayInLocation(c, loc)
+	if !ok {
+		return
+	}
+
+	profile, err := h.profile(userID)
+	if err != nil {
+		serverError(c, "Failed to retrieve health profile", err)
+		return
+	}
+	goals, err := h.dailyGoals(userID, profile, []string{date}, []time.Time{startOfDay, endOfDay})
+	if err != nil {
+		serverError(c, "Failed to retrieve water goals", err)
+		return
+	}
+	c.JSON(http.StatusOK, goals[0])
+}
+
+// SetWaterGoal - PUT /api/water/goal?tz=Area/City
+func (h *WaterHandler) SetWaterGoal(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
+		return
+	}
+
+	var req setWaterGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+
+	loc, ok := userLocation(c, h.profiles, userID)
+	if !ok {
+		return
+	}
+	today := utils.Today(time.Now(), loc)
+	if req.EffectiveFrom == "" {
+		req.EffectiveFrom = today
+	}
+	if _, _, err := utils.DayBounds(req.EffectiveFrom, loc); err != nil {
+		apierror.Invalid(c, "effective_from", "date", "Invalid date format. Use YYYY-MM-DD")
+		return
+	}
+	// YYYY-MM-DD strings sort in date order
+	if req.EffectiveFrom < today {
+		apierror.Invalid(c, "effective_from", "gte", "effective_from cannot be before today")
+		return
+	}
+
+	goal := models.HydrationGoal{
+		UserID:        userID,
+		GoalML:        req.GoalML,
+		EffectiveFrom: req.EffectiveFrom,
+	}
+	if err := h.goals.Set(&goal); err != nil {
+		serverError(c, "Failed to save water goal", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// dailyGoals is the water goal in effect on each of dates, whose bounds in
+// the user's time zone are given as for store.WaterStore.DailyTotals.
+// profile is nil for users without one.
+func (h *WaterHandler) dailyGoals(userID uint, profile *models.HealthProfile, dates []string, days []time.Time) ([]models.DailyWaterGoal, error) {
+	history, err := h.goals.List(userID)
+	if err != nil {
+		return nil, fmt.Errorf("list water goals: %w", err)
+	}
+
+	goals := make([]models.DailyWaterGoal, len(dates))
+	automatic := false
+	started := 0 // history[:started] took effect on or before the day
+	for i, date := range dates {
+		for started < len(history) && history[started].EffectiveFrom <= date {
+			started++
+		}
+		goals[i] = models.DailyWaterGoal{Date: date, Source: models.WaterGoalAutomatic}
+		if started > 0 {
+			setting := history[started-1]
+			goals[i].EffectiveFrom = setting.EffectiveFrom
+			if setting.GoalML != nil {
+				goals[i].GoalML = *setting.GoalML
+				goals[i].Source = models.WaterGoalCustom
+				continue
+			}
+		}
+		automatic = true
+	}
+	if !automatic {
+		return goals, nil
+	}
+
+	// the automatic goal follows the profile as it is now and each day's
+	// exercise
+	exercises, err := h.exercises.List(userID, days[0], days[len(days)-1])
+	if err != nil {
+		return nil, fmt.Errorf("list exercise logs: %w", err)
+	}
+	minutes := make([]int, len(dates))
+	for _, exercise := range exercises {
+		day := sort.Search(len(days), func(i int) bool { return days[i].After(exercise.LoggedAt) }) - 1
+		minutes[day] += exercise.Duration
+	}
+
+	for i := range goals {
+		if goals[i].Source != models.WaterGoalAutomatic {
+			continue
+		}
+		goalML, breakdown := utils.CalculateWaterGoal(profile, minutes[i])
+		goals[i].GoalML = goalML
+		goals[i].Breakdown = &breakdown
+	}
+	return goals, nil
+}
+
+// profile is the user's health profile, or nil if they have none
+func (h *WaterHandler) profile(userID uint) (*models.HealthProfile, error) {
+	profile, err := h.profiles.Get(userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get health profile: %w", err)
+	}
+	return &profile, nil
+}
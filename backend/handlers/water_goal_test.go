@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+func setupWaterGoalRouter(stores store.Stores) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages)
+
+	router := gin.New()
+	router.Use(middleware.AuthMiddleware())
+	router.GET("/water/summary", handler.GetDailySummary)
+	router.GET("/water/goal", handler.GetWaterGoal)
+	router.PUT("/water/goal", handler.SetWaterGoal)
+	return router
+}
+
+func TestGetWaterGoal_Automatic(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		seed(t, stores,
+			&models.HealthProfile{UserID: 1, Sex: "female", HeightCM: 165, WeightKG: 70, ActivityLevel: "moderate"},
+			&models.ExerciseLog{UserID: 1, Type: "Running", Duration: 20, CaloriesBurned: 200, LoggedAt: time.Now()},
+			&models.ExerciseLog{UserID: 1, Type: "Cycling", Duration: 10, CaloriesBurned: 100, LoggedAt: time.Now()},
+			// another day's exercise does not count
+			&models.ExerciseLog{UserID: 1, Type: "Running", Duration: 60, CaloriesBurned: 600, LoggedAt: time.Now().Add(-48 * time.Hour)},
+		)
+		router := setupWaterGoalRouter(stores)
+
+		req := httptest.NewRequest("GET", "/water/goal", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var goal models.DailyWaterGoal
+		json.Unmarshal(w.Body.Bytes(), &goal)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		// 70kg * 35ml + 500ml for moderate activity + 30 minutes * 12ml
+		if goal.Source != models.WaterGoalAutomatic || goal.GoalML != 3310 {
+			t.Errorf("Expected an automatic goal of 3310ml, got %+v", goal)
+		}
+		if goal.Breakdown == nil || goal.Breakdown.ExerciseMinutes != 30 || goal.Breakdown.BaseML != 2450 {
+			t.Errorf("Expected the breakdown to show 30 exercise minutes, got %+v", goal.Breakdown)
+		}
+	})
+}
+
+func TestSetWaterGoal_Custom(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		seed(t, stores, &models.WaterIntake{UserID: 1, AmountML: 750, LoggedAt: time.Now()})
+		router := setupWaterGoalRouter(stores)
+
+		req := httptest.NewRequest("PUT", "/water/goal", bytes.NewBufferString(`{"goal_ml": 3000}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var saved models.HydrationGoal
+		json.Unmarshal(w.Body.Bytes(), &saved)
+		if w.Code != http.StatusOK || saved.GoalML == nil || *saved.GoalML != 3000 {
+			t.Fatalf("Expected the goal to be saved, got %d %s", w.Code, w.Body.String())
+		}
+		if saved.EffectiveFrom != time.Now().UTC().Format("2006-01-02") {
+			t.Errorf("Expected the goal to start today, got %s", saved.EffectiveFrom)
+		}
+
+		req = httptest.NewRequest("GET", "/water/summary", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var summary models.WaterIntakeSummary
+		json.Unmarshal(w.Body.Bytes(), &summary)
+		if summary.GoalML != 3000 || summary.GoalSource != models.WaterGoalCustom || summary.Percentage != 25 {
+			t.Errorf("Expected 750ml to be 25%% of the 3000ml goal, got %+v", summary)
+		}
+
+		// null goes back to the automatic goal
+		req = httptest.NewRequest("PUT", "/water/goal", bytes.NewBufferString(`{"goal_ml": null}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		req = httptest.NewRequest("GET", "/water/goal", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var goal models.DailyWaterGoal
+		json.Unmarshal(w.Body.Bytes(), &goal)
+		if goal.Source != models.WaterGoalAutomatic || goal.GoalML != 2000 {
+			t.Errorf("Expected the default automatic goal, got %+v", goal)
+		}
+	})
+}
+
+// Summaries of past days use the goal that applied then
+func TestWaterSummary_UsesGoalInEffect(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		ml := func(n int) *int { return &n }
+		for _, goal := range []models.HydrationGoal{
+			{UserID: 1, GoalML: ml(2500), EffectiveFrom: "2026-03-01"},
+			{UserID: 1, GoalML: ml(4000), EffectiveFrom: "2026-03-10"},
+		} {
+			if err := stores.HydrationGoals.Set(&goal); err != nil {
+				t.Fatalf("Failed to set goal: %v", err)
+			}
+		}
+		for _, day := range []string{"2026-02-28", "2026-03-05", "2026-03-10"} {
+			loggedAt, _ := time.Parse(time.DateOnly, day)
+			seed(t, stores, &models.WaterIntake{UserID: 1, AmountML: 1000, LoggedAt: loggedAt.Add(12 * time.Hour)})
+		}
+		router := setupWaterGoalRouter(stores)
+
+		tests := []struct {
+			date       string
+			goal       int
+			source     string
+			percentage float64
+		}{
+			{"2026-02-28", 2000, models.WaterGoalAutomatic, 50},
+			{"2026-03-05", 2500, models.WaterGoalCustom, 40},
+			{"2026-03-10", 4000, models.WaterGoalCustom, 25},
+		}
+		for _, tt := range tests {
+			req := httptest.NewRequest("GET", "/water/summary?date="+tt.date, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var summary models.WaterIntakeSummary
+			json.Unmarshal(w.Body.Bytes(), &summary)
+			if summary.GoalML != tt.goal || summary.GoalSource != tt.source || summary.Percentage != tt.percentage {
+				t.Errorf("%s: expected %dml (%s) at %v%%, got %+v", tt.date, tt.goal, tt.source, tt.percentage, summary)
+			}
+		}
+	})
+}
+
+func TestSetWaterGoal_Invalid(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		router := setupWaterGoalRouter(stores)
+
+		tests := []struct {
+			body  string
+			field string
+		}{
+			{`{"goal_ml": 0}`, "goal_ml"},
+			{`{"goal_ml": 20000}`, "goal_ml"},
+			{`{"goal_ml": 2000, "effective_from": "2020-01-01"}`, "effective_from"},
+			{`{"goal_ml": 2000, "effective_from": "01/02/2099"}`, "effective_from"},
+		}
+		for _, tt := range tests {
+			req := httptest.NewRequest("PUT", "/water/goal", bytes.NewBufferString(tt.body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var response apierror.Error
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if w.Code != http.StatusBadRequest || len(response.Details) != 1 || response.Details[0].Field != tt.field {
+				t.Errorf("%s: expected a validation error on %s, got %d %+v", tt.body, tt.field, w.Code, response)
+			}
+		}
+
+		if history, _ := stores.HydrationGoals.List(1); len(history) != 0 {
+			t.Errorf("Expected no goal to be saved, got %+v", history)
+		}
+	})
+}
@@ -1,41 +1,54 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
-	
+
 	"github.com/gin-gonic/gin"
-	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/metrics"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
 )
 
+type logWaterRequest struct {
+	AmountML   int       `json:"amount_ml" binding:"required"`
+	LoggedAt   time.Time `json:"logged_at"`   // defaults to now
+	BeverageID *uint     `json:"beverage_id"` // defaults to plain water
+}
+
+// WaterHandler serves the water log and goals. Days are counted in the
+// user's time zone, from their profile. The automatic goal also reads the
+// exercise log.
+type WaterHandler struct {
+	water     store.WaterStore
+	profiles  store.ProfileStore
+	goals     store.HydrationGoalStore
+	exercises store.ExerciseStore
+	beverages store.BeverageStore
+}
+
+func NewWaterHandler(water store.WaterStore, profiles store.ProfileStore, goals store.HydrationGoalStore, exercises store.ExerciseStore, beverages store.BeverageStore) *WaterHandler {
+	return &WaterHandler{water: water, profiles: profiles, goals: goals, exercises: exercises, beverages: beverages}
+}
+
 // LogWaterIntake - POST /api/water
-func LogWaterIntake(c *gin.Context) {
+func (h *WaterHandler) LogWaterIntake(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
 		return
 	}
 
-	var req struct {
-		AmountML int       `json:"amount_ml" binding:"required"`
-		LoggedAt time.Time `json:"logged_at"`
-	}
+	var req logWaterRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Validation
-	if req.AmountML <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Amount must be positive"})
-		return
-	}
-
-	if req.AmountML > 5000 { // Max 5 liters at once seems reasonable
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Amount too large (max 5000ml)"})
+		apierror.BadRequest(c, err)
 		return
 	}
 
@@ -44,138 +57,154 @@ func LogWaterIntake(c *gin.Context) {
 		req.LoggedAt = time.Now()
 	}
 
-	// Prevent future dates
-	if req.LoggedAt.After(time.Now()) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot log future water intake"})
+	if !validWaterLog(c, req.AmountML, req.LoggedAt) {
+		return
+	}
+
+	beverage, ok := h.beverage(c, userID, req.BeverageID)
+	if !ok {
 		return
 	}
 
 	waterLog := models.WaterIntake{
-		UserID:   userID,
-		AmountML: req.AmountML,
-		LoggedAt: req.LoggedAt,
+		UserID:     userID,
+		AmountML:   req.AmountML,
+		LoggedAt:   req.LoggedAt,
+		BeverageID: req.BeverageID,
 	}
+	waterLog.HydrationML, waterLog.CaffeineMG, waterLog.Kcal = utils.BeverageIntake(req.AmountML, beverage)
 
-	if err := database.DB.Create(&waterLog).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log water intake"})
+	if err := h.water.Create(&waterLog); err != nil {
+		serverError(c, "Failed to log water intake", err)
 		return
 	}
+	metrics.LogCreated(metrics.KindWater)
 
 	c.JSON(http.StatusCreated, waterLog)
 }
 
-// GetWaterIntakeLogs - GET /api/water?date=YYYY-MM-DD
-func GetWaterIntakeLogs(c *gin.Context) {
+// GetWaterIntakeLogs - GET /api/water?date=YYYY-MM-DD&tz=Area/City
+func (h *WaterHandler) GetWaterIntakeLogs(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
 		return
 	}
 
-	dateStr := c.Query("date") // Optional: filter by date
-
-	var logs []models.WaterIntake
-	query := database.DB.Where("user_id = ?", userID)
-
-	if dateStr != "" {
-		// Parse date and get start/end of day
-		date, err := time.Parse("2006-01-02", dateStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+	var startOfDay, endOfDay time.Time
+	if c.Query("date") != "" { // Optional: filter by date
+		loc, ok := userLocation(c, h.profiles, userID)
+		if !ok {
+			return
+		}
+		if _, startOfDay, endOfDay, ok = dayInLocation(c, loc); !ok {
 			return
 		}
-
-		startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-		endOfDay := startOfDay.Add(24 * time.Hour)
-
-		query = query.Where("logged_at >= ? AND logged_at < ?", startOfDay, endOfDay)
 	}
 
-	query = query.Order("logged_at DESC")
-
-	if err := query.Find(&logs).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch water logs"})
+	logs, err := h.water.List(userID, startOfDay, endOfDay)
+	if err != nil {
+		serverError(c, "Failed to fetch water logs", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, logs)
 }
 
-// GetDailySummary - GET /api/water/summary?date=YYYY-MM-DD
-func GetDailySummary(c *gin.Context) {
+// GetDailySummary - GET /api/water/summary?date=YYYY-MM-DD&tz=Area/City
+// The date defaults to today in the user's time zone. With from, to or
+// granularity it summarises a range instead; see summarizeRange.
+func (h *WaterHandler) GetDailySummary(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
 		return
 	}
 
-	dateStr := c.DefaultQuery("date", time.Now().Format("2006-01-02"))
-
-	date, err := time.Parse("2006-01-02", dateStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+	loc, ok := userLocation(c, h.profiles, userID)
+	if !ok {
 		return
 	}
-
-	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
-
-	var logs []models.WaterIntake
-	err = database.DB.Where("user_id = ? AND logged_at >= ? AND logged_at < ?", 
-		userID, startOfDay, endOfDay).Find(&logs).Error
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch summary"})
+	if isRangeQuery(c) {
+		h.summarizeRange(c, userID, loc)
 		return
 	}
-
-	// Calculate totals
-	totalML := 0
-	for _, log := range logs {
-		totalML += log.AmountML
+	dateStr, startOfDay, endOfDay, ok := dayInLocation(c, loc)
+	if !ok {
+		return
 	}
 
-	// Recommended daily intake: 2000ml (can be customized per user later)
-	goalML := 2000
-	percentage := (float64(totalML) / float64(goalML)) * 100
-
-	summary := models.WaterIntakeSummary{
-		Date:       dateStr,
-		TotalML:    totalML,
-		EntryCount: len(logs),
-		GoalML:     goalML,
-		Percentage: roundToTwo(percentage),
+	summaries, err := h.dailySummaries(userID, []string{dateStr}, []time.Time{startOfDay, endOfDay})
+	if err != nil {
+		serverError(c, "Failed to fetch summary", err)
+		return
 	}
 
-	c.JSON(http.StatusOK, summary)
+	c.JSON(http.StatusOK, summaries[0])
 }
 
 // DeleteWaterLog - DELETE /api/water/:id
-func DeleteWaterLog(c *gin.Context) {
+func (h *WaterHandler) DeleteWaterLog(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Respond(c, http.StatusUnauthorized, apierror.AuthRequired, "Unauthorized")
 		return
 	}
 
-	logID := c.Param("id")
-
-	// Verify ownership before deleting
-	var log models.WaterIntake
-	if err := database.DB.Where("id = ? AND user_id = ?", logID, userID).First(&log).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Water log not found"})
+	logID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.WaterLogNotFound, "Water log not found")
 		return
 	}
 
-	if err := database.DB.Delete(&log).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete water log"})
+	// only the owner's logs can be deleted
+	err = h.water.Delete(userID, uint(logID))
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Respond(c, http.StatusNotFound, apierror.WaterLogNotFound, "Water log not found")
+		return
+	}
+	if err != nil {
+		serverError(c, "Failed to delete water log", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Water log deleted successfully"})
 }
 
-// Helper function
-func roundToTwo(val float64) float64 {
-	return float64(int(val*100+0.5)) / 100
-}
\ No newline at end of file
+// validWaterLog checks a new or edited log and responds when it is invalid
+func validWaterLog(c *gin.Context, amountML int, loggedAt time.Time) bool {
+	if amountML <= 0 {
+		apierror.Invalid(c, "amount_ml", "gt", "Amount must be positive")
+		return false
+	}
+
+	if amountML > 5000 { // Max 5 liters at once seems reasonable
+		apierror.Invalid(c, "amount_ml", "lte", "Amount too large (max 5000ml)")
+		return false
+	}
+
+	// Prevent future dates
+	if loggedAt.After(time.Now()) {
+		apierror.Invalid(c, "logged_at", "past", "Cannot log future water intake")
+		return false
+	}
+	return true
+}
+
+// beverage looks up the beverage of a log, nil for plain water, and
+// responds when the user has no such beverage
+func (h *WaterHandler) beverage(c *gin.Context, userID uint, id *uint) (*models.Beverage, bool) {
+	if id == nil {
+		return nil, true
+	}
+	beverage, err := h.beverages.Get(userID, *id)
+	if errors.Is(err, store.ErrNotFound) {
+		apierror.Invalid(c, "beverage_id", "exists", "Unknown beverage")
+		return nil, false
+	}
+	if err != nil {
+		serverError(c, "Failed to retrieve beverage", err)
+		return nil, false
+	}
+	return &beverage, true
+}
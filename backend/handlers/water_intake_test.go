@@ -10,256 +10,394 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
 )
 
 // Test 1: Log water intake successfully
 func TestLogWaterIntake_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.POST("/water", LogWaterIntake)
-
-	body := map[string]interface{}{
-		"amount_ml": 250,
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/water", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusCreated {
-		t.Errorf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	var response models.WaterIntake
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	if response.AmountML != 250 {
-		t.Errorf("Expected amount 250, got %d", response.AmountML)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/water", NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).LogWaterIntake)
+
+		body := map[string]interface{}{
+			"amount_ml": 250,
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/water", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var response models.WaterIntake
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response.AmountML != 250 {
+			t.Errorf("Expected amount 250, got %d", response.AmountML)
+		}
+	})
 }
 
 // Test 2: Validate amount (negative/zero/too large)
 func TestLogWaterIntake_InvalidAmount(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.POST("/water", LogWaterIntake)
-
-	// Test negative amount
-	body := map[string]interface{}{
-		"amount_ml": -100,
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/water", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400 for negative amount, got %d", w.Code)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/water", NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).LogWaterIntake)
+
+		// Test negative amount
+		body := map[string]interface{}{
+			"amount_ml": -100,
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/water", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for negative amount, got %d", w.Code)
+		}
+	})
 }
 
 // Test 3: Get water logs
 func TestGetWaterIntakeLogs_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
 
-	// Create some logs
-	log1 := models.WaterIntake{UserID: 1, AmountML: 250, LoggedAt: time.Now()}
-	log2 := models.WaterIntake{UserID: 1, AmountML: 500, LoggedAt: time.Now()}
-	db.Create(&log1)
-	db.Create(&log2)
+		// Create some logs
+		log1 := models.WaterIntake{UserID: 1, AmountML: 250, LoggedAt: time.Now()}
+		log2 := models.WaterIntake{UserID: 1, AmountML: 500, LoggedAt: time.Now()}
+		seed(t, stores, &log1)
+		seed(t, stores, &log2)
 
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/water", GetWaterIntakeLogs)
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/water", NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).GetWaterIntakeLogs)
 
-	req := httptest.NewRequest("GET", "/water", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/water", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
 
-	router.ServeHTTP(w, req)
+		router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", w.Code)
-	}
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
 
-	var response []models.WaterIntake
-	json.Unmarshal(w.Body.Bytes(), &response)
+		var response []models.WaterIntake
+		json.Unmarshal(w.Body.Bytes(), &response)
 
-	if len(response) != 2 {
-		t.Errorf("Expected 2 logs, got %d", len(response))
-	}
+		if len(response) != 2 {
+			t.Errorf("Expected 2 logs, got %d", len(response))
+		}
+	})
 }
 
 // Test 4: Get logs with date filter
 func TestGetWaterIntakeLogs_FilterByDate(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
-
-	// Create logs on different days
-	today := time.Now()
-	yesterday := today.Add(-24 * time.Hour)
-
-	logToday := models.WaterIntake{UserID: 1, AmountML: 250, LoggedAt: today}
-	logYesterday := models.WaterIntake{UserID: 1, AmountML: 500, LoggedAt: yesterday}
-	db.Create(&logToday)
-	db.Create(&logYesterday)
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/water", GetWaterIntakeLogs)
-
-	// Request only today's logs
-	todayStr := today.Format("2006-01-02")
-	req := httptest.NewRequest("GET", "/water?date="+todayStr, nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", w.Code)
-	}
-
-	var response []models.WaterIntake
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	if len(response) != 1 {
-		t.Errorf("Expected 1 log for today, got %d", len(response))
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		// Create logs on different days
+		today := time.Now()
+		yesterday := today.Add(-24 * time.Hour)
+
+		logToday := models.WaterIntake{UserID: 1, AmountML: 250, LoggedAt: today}
+		logYesterday := models.WaterIntake{UserID: 1, AmountML: 500, LoggedAt: yesterday}
+		seed(t, stores, &logToday)
+		seed(t, stores, &logYesterday)
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/water", NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).GetWaterIntakeLogs)
+
+		// Request only today's logs
+		todayStr := today.Format("2006-01-02")
+		req := httptest.NewRequest("GET", "/water?date="+todayStr, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var response []models.WaterIntake
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if len(response) != 1 {
+			t.Errorf("Expected 1 log for today, got %d", len(response))
+		}
+	})
 }
 
 // Test 5: Get daily summary
 func TestGetDailySummary_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
-
-	// Create logs for today
-	today := time.Now()
-	log1 := models.WaterIntake{UserID: 1, AmountML: 250, LoggedAt: today}
-	log2 := models.WaterIntake{UserID: 1, AmountML: 500, LoggedAt: today}
-	db.Create(&log1)
-	db.Create(&log2)
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/water/summary", GetDailySummary)
-
-	req := httptest.NewRequest("GET", "/water/summary", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", w.Code)
-	}
-
-	var response models.WaterIntakeSummary
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	if response.TotalML != 750 {
-		t.Errorf("Expected total 750ml, got %d", response.TotalML)
-	}
-
-	if response.EntryCount != 2 {
-		t.Errorf("Expected 2 entries, got %d", response.EntryCount)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		// Create logs for today
+		today := time.Now()
+		log1 := models.WaterIntake{UserID: 1, AmountML: 250, LoggedAt: today}
+		log2 := models.WaterIntake{UserID: 1, AmountML: 500, LoggedAt: today}
+		seed(t, stores, &log1)
+		seed(t, stores, &log2)
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/water/summary", NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).GetDailySummary)
+
+		req := httptest.NewRequest("GET", "/water/summary", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var response models.WaterIntakeSummary
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response.TotalML != 750 {
+			t.Errorf("Expected total 750ml, got %d", response.TotalML)
+		}
+
+		if response.EntryCount != 2 {
+			t.Errorf("Expected 2 entries, got %d", response.EntryCount)
+		}
+	})
 }
 
 // Test 6: Delete water log
 func TestDeleteWaterLog_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
 
-	// Create a log
-	log := models.WaterIntake{UserID: 1, AmountML: 250, LoggedAt: time.Now()}
-	db.Create(&log)
+		// Create a log
+		log := models.WaterIntake{UserID: 1, AmountML: 250, LoggedAt: time.Now()}
+		seed(t, stores, &log)
 
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.DELETE("/water/:id", DeleteWaterLog)
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.DELETE("/water/:id", NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).DeleteWaterLog)
 
-	req := httptest.NewRequest("DELETE", fmt.Sprintf("/water/%d", log.ID), nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
+		req := httptest.NewRequest("DELETE", fmt.Sprintf("/water/%d", log.ID), nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
 
-	router.ServeHTTP(w, req)
+		router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", w.Code)
-	}
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
 
-	// Verify deletion
-	var deletedLog models.WaterIntake
-	err := db.First(&deletedLog, log.ID).Error
-	if err == nil {
-		t.Error("Expected log to be deleted")
-	}
+		// Verify deletion
+		if _, err := stores.Water.Get(1, log.ID); err == nil {
+			t.Error("Expected log to be deleted")
+		}
+	})
 }
 
 // Test 7: Delete - not found
 func TestDeleteWaterLog_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupProfileTestDB(t)
-	token := createTestUser(t, db, 1, "testuser")
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
 
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.DELETE("/water/:id", DeleteWaterLog)
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.DELETE("/water/:id", NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).DeleteWaterLog)
 
-	req := httptest.NewRequest("DELETE", "/water/999", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
+		req := httptest.NewRequest("DELETE", "/water/999", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
 
-	router.ServeHTTP(w, req)
+		router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusNotFound {
-		t.Errorf("Expected status 404, got %d", w.Code)
-	}
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
 }
 
 // Test 8: Unauthorized access
 func TestWaterIntake_Unauthorized(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	setupProfileTestDB(t)
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.POST("/water", LogWaterIntake)
-
-	body := map[string]interface{}{
-		"amount_ml": 250,
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/water", bytes.NewBuffer(jsonBody))
-	// No Authorization header
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusUnauthorized {
-		t.Errorf("Expected status 401, got %d", w.Code)
-	}
-}
\ No newline at end of file
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/water", NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).LogWaterIntake)
+
+		body := map[string]interface{}{
+			"amount_ml": 250,
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/water", bytes.NewBuffer(jsonBody))
+		// No Authorization header
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+	})
+}
+
+// Days are UTC days whatever zone a log was written in
+func TestWaterIntake_DateFilterUsesUTC(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		east := time.FixedZone("UTC+2", 2*60*60)
+		west := time.FixedZone("UTC-5", -5*60*60)
+		// 2026-03-10 22:30 UTC
+		seed(t, stores, &models.WaterIntake{UserID: 1, AmountML: 250, LoggedAt: time.Date(2026, 3, 11, 0, 30, 0, 0, east)})
+		// 2026-03-11 04:30 UTC
+		seed(t, stores, &models.WaterIntake{UserID: 1, AmountML: 500, LoggedAt: time.Date(2026, 3, 10, 23, 30, 0, 0, west)})
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/water", NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).GetWaterIntakeLogs)
+		router.GET("/water/summary", NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).GetDailySummary)
+
+		for date, want := range map[string]int{"2026-03-10": 250, "2026-03-11": 500} {
+			req := httptest.NewRequest("GET", "/water?date="+date, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var logs []models.WaterIntake
+			json.Unmarshal(w.Body.Bytes(), &logs)
+			if len(logs) != 1 || logs[0].AmountML != want {
+				t.Errorf("%s: expected one %dml log, got %+v", date, want, logs)
+			}
+
+			req = httptest.NewRequest("GET", "/water/summary?date="+date, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var summary models.WaterIntakeSummary
+			json.Unmarshal(w.Body.Bytes(), &summary)
+			if summary.TotalML != want || summary.EntryCount != 1 {
+				t.Errorf("%s: expected total %dml in 1 entry, got %+v", date, want, summary)
+			}
+		}
+	})
+}
+
+func TestWaterSummary_UsesProfileTimeZoneAcrossDST(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		if err := stores.Profiles.Save(&models.HealthProfile{UserID: 1, Sex: "female", HeightCM: 165, WeightKG: 60, TimeZone: "America/Los_Angeles"}); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+
+		for _, log := range []struct {
+			at     string
+			amount int
+		}{
+			{"2026-03-08T07:30:00Z", 1},   // 23:30 PST on the 7th
+			{"2026-03-08T08:30:00Z", 10},  // 00:30 PST on the 8th
+			{"2026-03-09T06:30:00Z", 100}, // 23:30 PDT on the 8th, a 23 hour day
+			{"2026-03-09T07:30:00Z", 1000},
+			{"2026-11-01T07:00:00Z", 20},  // midnight PDT on the 1st
+			{"2026-11-02T07:59:00Z", 200}, // 23:59 PST on the 1st, a 25 hour day
+			{"2026-11-02T08:00:00Z", 2000},
+		} {
+			loggedAt, _ := time.Parse(time.RFC3339, log.at)
+			seed(t, stores, &models.WaterIntake{UserID: 1, AmountML: log.amount, LoggedAt: loggedAt})
+		}
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/water/summary", NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).GetDailySummary)
+
+		tests := []struct {
+			query string
+			total int
+		}{
+			{"date=2026-03-08", 110},
+			{"date=2026-11-01", 220},
+			// the tz parameter overrides the profile
+			{"date=2026-03-08&tz=UTC", 11},
+			{"date=2026-11-01&tz=Europe/Berlin", 20},
+		}
+		for _, tt := range tests {
+			req := httptest.NewRequest("GET", "/water/summary?"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var summary models.WaterIntakeSummary
+			json.Unmarshal(w.Body.Bytes(), &summary)
+			if w.Code != http.StatusOK || summary.TotalML != tt.total {
+				t.Errorf("%s: expected total %dml, got %d %+v", tt.query, tt.total, w.Code, summary)
+			}
+		}
+	})
+}
+
+func TestWaterSummary_RejectsUnknownTimeZone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/water/summary", NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages).GetDailySummary)
+
+		req := httptest.NewRequest("GET", "/water/summary?tz=Pacific/Atlantis", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response apierror.Error
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if w.Code != http.StatusBadRequest || len(response.Details) != 1 || response.Details[0].Field != "tz" {
+			t.Errorf("Expected a validation error on tz, got %d %+v", w.Code, response)
+		}
+	})
+}
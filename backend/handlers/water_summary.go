@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// maxSummaryDays bounds a range summary; each day is a term of the query
+const maxSummaryDays = 366
+
+type waterRangeQuery struct {
+	From        string `form:"from"` // defaults to six days before to
+	To          string `form:"to"`   // defaults to today
+	Granularity string `form:"granularity" binding:"omitempty,oneof=day week month"`
+}
+
+func isRangeQuery(c *gin.Context) bool {
+	return c.Query("from") != "" || c.Query("to") != "" || c.Query("granularity") != ""
+}
+
+// summarizeRange answers GET /api/water/summary?from=&to=&granularity=
+// with every day from..to in the user's time zone, grouped by day, week or
+// month. Days without logs count as zero.
+func (h *WaterHandler) summarizeRange(c *gin.Context, userID uint, loc *time.Location) {
+	var query waterRangeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.BadRequest(c, err)
+		return
+	}
+	if query.Granularity == "" {
+		query.Granularity = "day"
+	}
+	if query.To == "" {
+		query.To = utils.Today(time.Now(), loc)
+	}
+	to, _, err := utils.DayBounds(query.To, loc)
+	if err != nil {
+		apierror.Invalid(c, "to", "date", "Invalid date format. Use YYYY-MM-DD")
+		return
+	}
+	if query.From == "" {
+		query.From = to.AddDate(0, 0, -6).Format(utils.DateLayout)
+	}
+	from, _, err := utils.DayBounds(query.From, loc)
+	if err != nil {
+		apierror.Invalid(c, "from", "date", "Invalid date format. Use YYYY-MM-DD")
+		return
+	}
+	if from.After(to) {
+		apierror.Invalid(c, "from", "lte", "from must not be after to")
+		return
+	}
+	if !to.Before(from.AddDate(0, 0, maxSummaryDays)) {
+		apierror.Invalid(c, "to", "max", "A summary covers at most 366 days")
+		return
+	}
+
+	dates, days, err := utils.DayRange(query.From, query.To, loc)
+	if err != nil {
+		serverError(c, "Failed to fetch summary", err)
+		return
+	}
+	daily, err := h.dailySummaries(userID, dates, days)
+	if err != nil {
+		serverError(c, "Failed to fetch summary", err)
+		return
+	}
+
+	result := models.WaterIntakeRange{
+		From:        query.From,
+		To:          query.To,
+		Granularity: query.Granularity,
+		Series:      []models.WaterIntakePeriod{},
+		Days:        len(daily),
+		BestDay:     daily[0],
+		WorstDay:    daily[0],
+	}
+	lastKey := ""
+	for i, day := range daily {
+		if key := periodKey(days[i], query.Granularity); key != lastKey {
+			result.Series = append(result.Series, models.WaterIntakePeriod{Start: day.Date})
+			lastKey = key
+		}
+		period := &result.Series[len(result.Series)-1]
+		period.End = day.Date
+		period.Days++
+		period.TotalML += day.TotalML
+		period.HydrationML += day.HydrationML
+		period.CaffeineMG += day.CaffeineMG
+		period.EntryCount += day.EntryCount
+		period.GoalML += day.GoalML
+
+		result.TotalML += day.TotalML
+		result.HydrationML += day.HydrationML
+		result.CaffeineMG += day.CaffeineMG
+		if day.HydrationML >= day.GoalML {
+			period.GoalHitDays++
+			result.GoalHitDays++
+		}
+		if day.CaffeineOverLimit {
+			result.CaffeineOverLimitDays++
+		}
+		if day.Percentage > result.BestDay.Percentage {
+			result.BestDay = day
+		}
+		if day.Percentage < result.WorstDay.Percentage {
+			result.WorstDay = day
+		}
+	}
+	for i := range result.Series {
+		period := &result.Series[i]
+		period.Percentage = utils.RoundToTwo(float64(period.HydrationML) / float64(period.GoalML) * 100)
+		period.AverageML = utils.RoundToTwo(float64(period.TotalML) / float64(period.Days))
+		period.CaffeineMG = utils.RoundToTwo(period.CaffeineMG)
+	}
+	result.AverageML = utils.RoundToTwo(float64(result.TotalML) / float64(result.Days))
+	result.CaffeineMG = utils.RoundToTwo(result.CaffeineMG)
+
+	c.JSON(http.StatusOK, result)
+}
+
+// dailySummaries totals each of dates, whose bounds are given as for
+// store.WaterStore.DailyTotals, against the goal of that day. The sums are
+// made by the database.
+func (h *WaterHandler) dailySummaries(userID uint, dates []string, days []time.Time) ([]models.WaterIntakeSummary, error) {
+	totals, err := h.water.DailyTotals(userID, days)
+	if err != nil {
+		return nil, fmt.Errorf("sum water logs: %w", err)
+	}
+	profile, err := h.profile(userID)
+	if err != nil {
+		return nil, err
+	}
+	// the goal in effect each day, so past days keep their percentage
+	goals, err := h.dailyGoals(userID, profile, dates, days)
+	if err != nil {
+		return nil, err
+	}
+
+	caffeineLimit := utils.CaffeineLimit(profile)
+	summaries := make([]models.WaterIntakeSummary, len(dates))
+	for i, goal := range goals {
+		summaries[i] = models.WaterIntakeSummary{
+			Date:            dates[i],
+			GoalML:          goal.GoalML,
+			GoalSource:      goal.Source,
+			CaffeineLimitMG: caffeineLimit,
+		}
+	}
+	for _, total := range totals {
+		summary := &summaries[total.Day]
+		summary.TotalML = total.TotalML
+		summary.HydrationML = total.HydrationML
+		summary.CaffeineMG = utils.RoundToTwo(total.CaffeineMG)
+		summary.EntryCount = total.EntryCount
+		summary.CaffeineOverLimit = summary.CaffeineMG > float64(caffeineLimit)
+	}
+	for i := range summaries {
+		// beverages count by how well they hydrate
+		summaries[i].Percentage = utils.RoundToTwo(float64(summaries[i].HydrationML) / float64(summaries[i].GoalML) * 100)
+	}
+	return summaries, nil
+}
+
+// TodaySummary is what GET /api/water/summary returns for the day of now
+// in loc, for callers outside a request such as the reminder scheduler
+func (h *WaterHandler) TodaySummary(userID uint, loc *time.Location, now time.Time) (models.WaterIntakeSummary, error) {
+	date := utils.Today(now, loc)
+	start, end, err := utils.DayBounds(date, loc)
+	if err != nil {
+		return models.WaterIntakeSummary{}, err
+	}
+	summaries, err := h.dailySummaries(userID, []string{date}, []time.Time{start, end})
+	if err != nil {
+		return models.WaterIntakeSummary{}, err
+	}
+	return summaries[0], nil
+}
+
+// periodKey is the same for days in the same period of a granularity
+func periodKey(day time.Time, granularity string) string {
+	switch granularity {
+	case "week":
+		year, week := day.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case "month":
+		return day.Format("2006-01")
+	}
+	return day.Format(utils.DateLayout)
+}
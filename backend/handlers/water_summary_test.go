@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+// seedWaterRange logs 6000ml between Saturday 2026-02-28 and Tuesday
+// 2026-03-10 for a user with the default 2000ml goal
+func seedWaterRange(t *testing.T, stores store.Stores) string {
+	t.Helper()
+	token := createTestUser(t, stores, 1, "testuser")
+	for _, log := range []struct {
+		at     string
+		amount int
+	}{
+		{"2026-03-02T08:00:00Z", 1000},
+		{"2026-03-02T20:00:00Z", 1000},
+		{"2026-03-04T12:00:00Z", 1000},
+		{"2026-03-09T12:00:00Z", 3000},
+		{"2026-03-11T12:00:00Z", 5000}, // after the range
+	} {
+		loggedAt, _ := time.Parse(time.RFC3339, log.at)
+		seed(t, stores, &models.WaterIntake{UserID: 1, AmountML: log.amount, LoggedAt: loggedAt})
+	}
+	return token
+}
+
+func getWaterRange(t *testing.T, stores store.Stores, token, query string) (int, models.WaterIntakeRange) {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/water/summary?"+query, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	setupWaterGoalRouter(stores).ServeHTTP(w, req)
+
+	var summary models.WaterIntakeRange
+	json.Unmarshal(w.Body.Bytes(), &summary)
+	return w.Code, summary
+}
+
+func TestWaterSummaryRange_Days(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := seedWaterRange(t, stores)
+		code, summary := getWaterRange(t, stores, token, "from=2026-02-28&to=2026-03-10")
+		if code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", code)
+		}
+		if summary.Days != 11 || len(summary.Series) != 11 {
+			t.Fatalf("Expected a zero-filled series of 11 days, got %+v", summary)
+		}
+		if summary.TotalML != 6000 || summary.AverageML != 545.45 || summary.GoalHitDays != 2 {
+			t.Errorf("Expected 6000ml, 545.45ml a day and 2 days at goal, got %+v", summary)
+		}
+		if day := summary.Series[2]; day.Start != "2026-03-02" || day.End != "2026-03-02" || day.TotalML != 2000 || day.EntryCount != 2 || day.Percentage != 100 {
+			t.Errorf("Expected 2000ml in 2 entries on 2026-03-02, got %+v", day)
+		}
+		if day := summary.Series[1]; day.TotalML != 0 || day.GoalML != 2000 {
+			t.Errorf("Expected an empty 2026-03-01 with its goal, got %+v", day)
+		}
+		if summary.BestDay.Date != "2026-03-09" || summary.BestDay.Percentage != 150 {
+			t.Errorf("Expected 2026-03-09 to be the best day, got %+v", summary.BestDay)
+		}
+		// the earliest of the empty days
+		if summary.WorstDay.Date != "2026-02-28" || summary.WorstDay.TotalML != 0 {
+			t.Errorf("Expected 2026-02-28 to be the worst day, got %+v", summary.WorstDay)
+		}
+	})
+}
+
+func TestWaterSummaryRange_WeeksAndMonths(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := seedWaterRange(t, stores)
+		tests := []struct {
+			granularity string
+			periods     []models.WaterIntakePeriod
+		}{
+			{"week", []models.WaterIntakePeriod{
+				{Start: "2026-02-28", End: "2026-03-01", Days: 2, GoalML: 4000},
+				{Start: "2026-03-02", End: "2026-03-08", Days: 7, TotalML: 3000, HydrationML: 3000, EntryCount: 3, GoalML: 14000, Percentage: 21.43, AverageML: 428.57, GoalHitDays: 1},
+				{Start: "2026-03-09", End: "2026-03-10", Days: 2, TotalML: 3000, HydrationML: 3000, EntryCount: 1, GoalML: 4000, Percentage: 75, AverageML: 1500, GoalHitDays: 1},
+			}},
+			{"month", []models.WaterIntakePeriod{
+				{Start: "2026-02-28", End: "2026-02-28", Days: 1, GoalML: 2000},
+				{Start: "2026-03-01", End: "2026-03-10", Days: 10, TotalML: 6000, HydrationML: 6000, EntryCount: 4, GoalML: 20000, Percentage: 30, AverageML: 600, GoalHitDays: 2},
+			}},
+		}
+		for _, tt := range tests {
+			code, summary := getWaterRange(t, stores, token, "from=2026-02-28&to=2026-03-10&granularity="+tt.granularity)
+			if code != http.StatusOK || summary.Granularity != tt.granularity || len(summary.Series) != len(tt.periods) {
+				t.Errorf("%s: expected %d periods, got %d %+v", tt.granularity, len(tt.periods), code, summary)
+				continue
+			}
+			for i, period := range tt.periods {
+				if summary.Series[i] != period {
+					t.Errorf("%s: expected %+v, got %+v", tt.granularity, period, summary.Series[i])
+				}
+			}
+		}
+	})
+}
+
+func TestWaterSummaryRange_Invalid(t *testing.T) {
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createTestUser(t, stores, 1, "testuser")
+		router := setupWaterGoalRouter(stores)
+
+		tests := []struct {
+			query string
+			field string
+		}{
+			{"granularity=year", "granularity"},
+			{"from=2026-03-10&to=2026-03-01", "from"},
+			{"from=2025-01-01&to=2026-01-02", "to"},
+			{"from=March&to=2026-03-01", "from"},
+			{"to=2026-13-01", "to"},
+		}
+		for _, tt := range tests {
+			req := httptest.NewRequest("GET", "/water/summary?"+tt.query, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var response apierror.Error
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if w.Code != http.StatusBadRequest || len(response.Details) != 1 || response.Details[0].Field != tt.field {
+				t.Errorf("%s: expected a validation error on %s, got %d %+v", tt.query, tt.field, w.Code, response)
+			}
+		}
+
+		// a full year is allowed
+		code, summary := getWaterRange(t, stores, token, "from=2024-01-01&to=2024-12-31&granularity=month")
+		if code != http.StatusOK || summary.Days != 366 || len(summary.Series) != 12 {
+			t.Errorf("Expected 366 days in 12 months, got %d %+v", code, summary.Series)
+		}
+	})
+}
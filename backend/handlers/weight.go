@@ -5,8 +5,10 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/metrics"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
 
 	"github.com/gin-gonic/gin"
@@ -24,24 +26,46 @@ type ModifyLastWeightRequest struct {
 	LoggedAt *time.Time `json:"logged_at"`
 }
 
-func AddWeightLog(c *gin.Context) {
+// weightLogResponse is a weight log entry in the user's preferred units
+type weightLogResponse struct {
+	ID       uint      `json:"id"`
+	UserID   uint      `json:"user_id"`
+	Weight   float64   `json:"weight"`
+	Unit     string    `json:"unit"`
+	LoggedAt time.Time `json:"logged_at"`
+}
+
+// WeightHandler serves the weight log. Weights are stored in kg and shown
+// in the units the user's profile prefers.
+type WeightHandler struct {
+	weights  store.WeightStore
+	profiles store.ProfileStore
+}
+
+func NewWeightHandler(weights store.WeightStore, profiles store.ProfileStore) *WeightHandler {
+	return &WeightHandler{weights: weights, profiles: profiles}
+}
+
+// preferredUnits returns the user's display units, metric without a profile
+func (h *WeightHandler) preferredUnits(userID uint) string {
+	if profile, err := h.profiles.Get(userID); err == nil {
+		return profile.PreferredUnits
+	}
+	return "metric"
+}
+
+func (h *WeightHandler) AddWeightLog(c *gin.Context) {
 	userID := c.GetUint("userID")
 
 	var req AddWeightLogRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request: " + err.Error(),
-		})
+		apierror.BadRequest(c, err)
 
 		return
 	}
 
 	// Get user's preferred units from health profile
-	var profile models.HealthProfile
-	preferredUnits := "metric" // default
-	if err := database.GetDB().Where("user_id = ?", userID).First(&profile).Error; err == nil {
-		preferredUnits = profile.PreferredUnits
-	}
+	preferredUnits := h.preferredUnits(userID)
 
 	// If unit is not specified in request, use user's preferred units
 	unit := req.Unit
@@ -62,13 +86,12 @@ func AddWeightLog(c *gin.Context) {
 		LoggedAt: loggedAt,
 	}
 
-	if err := database.GetDB().Create(&weightLog).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to add weight log: " + err.Error(),
-		})
+	if err := h.weights.Create(&weightLog); err != nil {
+		serverError(c, "Failed to add weight log", err)
 
 		return
 	}
+	metrics.LogCreated(metrics.KindWeight)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Weight log added successfully",
@@ -76,43 +99,23 @@ func AddWeightLog(c *gin.Context) {
 	})
 }
 
-func GetWeightLogs(c *gin.Context) {
+func (h *WeightHandler) GetWeightLogs(c *gin.Context) {
 	userID := c.GetUint("userID")
 
 	// Get user's preferred units from health profile
-	var profile models.HealthProfile
-	preferredUnits := "metric" // default
-	if err := database.GetDB().Where("user_id = ?", userID).First(&profile).Error; err == nil {
-		preferredUnits = profile.PreferredUnits
-	}
-
-	var weightLogs []models.WeightLog
-	result := database.GetDB().Where(
-		"user_id = ?", userID,
-	).Order(
-		"logged_at DESC",
-	).Limit(30).Find(&weightLogs)
+	preferredUnits := h.preferredUnits(userID)
 
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch weight logs: " + result.Error.Error(),
-		})
+	weightLogs, err := h.weights.Recent(userID, 30)
+	if err != nil {
+		serverError(c, "Failed to fetch weight logs", err)
 
 		return
 	}
 
 	// Convert weights to user's preferred units for display
-	type WeightLogResponse struct {
-		ID       uint      `json:"id"`
-		UserID   uint      `json:"user_id"`
-		Weight   float64   `json:"weight"`
-		Unit     string    `json:"unit"`
-		LoggedAt time.Time `json:"logged_at"`
-	}
-
-	response := make([]WeightLogResponse, len(weightLogs))
+	response := make([]weightLogResponse, len(weightLogs))
 	for i, log := range weightLogs {
-		response[i] = WeightLogResponse{
+		response[i] = weightLogResponse{
 			ID:       log.ID,
 			UserID:   log.UserID,
 			Weight:   utils.ConvertWeightFromKg(log.WeightKG, preferredUnits),
@@ -126,35 +129,21 @@ func GetWeightLogs(c *gin.Context) {
 	})
 }
 
-func ModifyLastWeight(c *gin.Context) {
+func (h *WeightHandler) ModifyLastWeight(c *gin.Context) {
 	userID := c.GetUint("userID")
 
 	// Get user's preferred units from health profile
-	var profile models.HealthProfile
-	preferredUnits := "metric" // default
-	if err := database.GetDB().Where("user_id = ?", userID).First(&profile).Error; err == nil {
-		preferredUnits = profile.PreferredUnits
-	}
-
-	var weightLogs []models.WeightLog
-	result := database.GetDB().Where(
-		"user_id = ?", userID,
-	).Order(
-		"logged_at DESC",
-	).Limit(1).Find(&weightLogs)
+	preferredUnits := h.preferredUnits(userID)
 
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch weight logs: " + result.Error.Error(),
-		})
+	weightLogs, err := h.weights.Recent(userID, 1)
+	if err != nil {
+		serverError(c, "Failed to fetch weight logs", err)
 
 		return
 	}
 
 	if len(weightLogs) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "No weight logs found to modify",
-		})
+		apierror.Respond(c, http.StatusNotFound, apierror.WeightLogNotFound, "No weight logs found to modify")
 
 		return
 	}
@@ -162,9 +151,7 @@ func ModifyLastWeight(c *gin.Context) {
 	lastLog := weightLogs[0]
 	var req ModifyLastWeightRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request: " + err.Error(),
-		})
+		apierror.BadRequest(c, err)
 
 		return
 	}
@@ -181,10 +168,8 @@ func ModifyLastWeight(c *gin.Context) {
 		lastLog.LoggedAt = *req.LoggedAt
 	}
 
-	if err := database.GetDB().Save(&lastLog).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to modify weight log: " + err.Error(),
-		})
+	if err := h.weights.Update(&lastLog); err != nil {
+		serverError(c, "Failed to modify weight log", err)
 
 		return
 	}
@@ -9,47 +9,27 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 
-	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
 )
 
-func setupWeightTestDB(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
-	})
-	if err != nil {
-		t.Fatalf("Failed to connect to test database: %v", err)
-	}
-
-	err = db.AutoMigrate(&models.User{}, &models.HealthProfile{}, &models.WeightLog{})
-	if err != nil {
-		t.Fatalf("Failed to migrate test database: %v", err)
-	}
-
-	database.DB = db
-	return db
-}
-
-func createWeightTestUser(t *testing.T, db *gorm.DB, userID uint, username string, preferredUnits string) string {
+func createWeightTestUser(t *testing.T, stores store.Stores, userID uint, username string, preferredUnits string) string {
 	user := models.User{
 		ID:           userID,
 		Username:     username,
 		PasswordHash: "hash",
 	}
-	db.Create(&user)
+	seed(t, stores, &user)
 
 	// Create health profile with preferred units
 	profile := models.HealthProfile{
 		UserID:         userID,
 		PreferredUnits: preferredUnits,
 	}
-	db.Create(&profile)
+	seed(t, stores, &profile)
 
 	token, err := utils.GenerateToken(userID, username)
 	if err != nil {
@@ -59,571 +39,603 @@ func createWeightTestUser(t *testing.T, db *gorm.DB, userID uint, username strin
 	return token
 }
 
-func TestAddWeightLog_Success_Metric(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token := createWeightTestUser(t, db, 1, "testuser", "metric")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.POST("/weight", AddWeightLog)
-
-	// Create request with metric weight
-	body := map[string]interface{}{
-		"weight": 70.5,
-		"unit":   "metric",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/weight", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	var response map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	if response["message"] != "Weight log added successfully" {
-		t.Errorf("Expected success message")
+// latestWeightLog returns the user's newest weight log
+func latestWeightLog(t *testing.T, stores store.Stores, userID uint) models.WeightLog {
+	t.Helper()
+	logs, err := stores.Weights.Recent(userID, 1)
+	if err != nil || len(logs) == 0 {
+		t.Fatalf("Expected a weight log for user %d, got %v", userID, err)
 	}
+	return logs[0]
+}
 
-	// Verify weight was stored correctly in kg
-	var weightLog models.WeightLog
-	db.First(&weightLog)
-	if weightLog.WeightKG != 70.5 {
-		t.Errorf("Expected weight 70.5 kg, got %v", weightLog.WeightKG)
-	}
+func TestAddWeightLog_Success_Metric(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createWeightTestUser(t, stores, 1, "testuser", "metric")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/weight", NewWeightHandler(stores.Weights, stores.Profiles).AddWeightLog)
+
+		// Create request with metric weight
+		body := map[string]interface{}{
+			"weight": 70.5,
+			"unit":   "metric",
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/weight", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		if response["message"] != "Weight log added successfully" {
+			t.Errorf("Expected success message")
+		}
+
+		// Verify weight was stored correctly in kg
+		weightLog := latestWeightLog(t, stores, 1)
+		if weightLog.WeightKG != 70.5 {
+			t.Errorf("Expected weight 70.5 kg, got %v", weightLog.WeightKG)
+		}
+	})
 }
 
 func TestAddWeightLog_Success_Imperial(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token := createWeightTestUser(t, db, 1, "testuser", "imperial")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.POST("/weight", AddWeightLog)
-
-	// Create request with imperial weight (220.46 lbs = 100 kg)
-	body := map[string]interface{}{
-		"weight": 220.462262185,
-		"unit":   "imperial",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/weight", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	// Verify weight was converted to kg correctly
-	var weightLog models.WeightLog
-	db.First(&weightLog)
-	expectedKG := 100.0
-	if weightLog.WeightKG < expectedKG-0.01 || weightLog.WeightKG > expectedKG+0.01 {
-		t.Errorf("Expected weight ~100 kg, got %v", weightLog.WeightKG)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createWeightTestUser(t, stores, 1, "testuser", "imperial")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/weight", NewWeightHandler(stores.Weights, stores.Profiles).AddWeightLog)
+
+		// Create request with imperial weight (220.46 lbs = 100 kg)
+		body := map[string]interface{}{
+			"weight": 220.462262185,
+			"unit":   "imperial",
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/weight", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		// Verify weight was converted to kg correctly
+		weightLog := latestWeightLog(t, stores, 1)
+		expectedKG := 100.0
+		if weightLog.WeightKG < expectedKG-0.01 || weightLog.WeightKG > expectedKG+0.01 {
+			t.Errorf("Expected weight ~100 kg, got %v", weightLog.WeightKG)
+		}
+	})
 }
 
 func TestAddWeightLog_DefaultsToPreferredUnits(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token := createWeightTestUser(t, db, 1, "testuser", "imperial")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.POST("/weight", AddWeightLog)
-
-	// Create request without specifying unit (should use user's preferred units)
-	body := map[string]interface{}{
-		"weight": 220.462262185,
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/weight", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	// Verify weight was treated as lbs and converted to kg
-	var weightLog models.WeightLog
-	db.First(&weightLog)
-	expectedKG := 100.0
-	if weightLog.WeightKG < expectedKG-0.01 || weightLog.WeightKG > expectedKG+0.01 {
-		t.Errorf("Expected weight ~100 kg (converted from lbs), got %v", weightLog.WeightKG)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createWeightTestUser(t, stores, 1, "testuser", "imperial")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/weight", NewWeightHandler(stores.Weights, stores.Profiles).AddWeightLog)
+
+		// Create request without specifying unit (should use user's preferred units)
+		body := map[string]interface{}{
+			"weight": 220.462262185,
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/weight", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		// Verify weight was treated as lbs and converted to kg
+		weightLog := latestWeightLog(t, stores, 1)
+		expectedKG := 100.0
+		if weightLog.WeightKG < expectedKG-0.01 || weightLog.WeightKG > expectedKG+0.01 {
+			t.Errorf("Expected weight ~100 kg (converted from lbs), got %v", weightLog.WeightKG)
+		}
+	})
 }
 
 func TestAddWeightLog_InvalidWeight(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token := createWeightTestUser(t, db, 1, "testuser", "metric")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.POST("/weight", AddWeightLog)
-
-	// Create request with invalid weight (negative)
-	body := map[string]interface{}{
-		"weight": -10,
-		"unit":   "metric",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/weight", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", w.Code)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createWeightTestUser(t, stores, 1, "testuser", "metric")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/weight", NewWeightHandler(stores.Weights, stores.Profiles).AddWeightLog)
+
+		// Create request with invalid weight (negative)
+		body := map[string]interface{}{
+			"weight": -10,
+			"unit":   "metric",
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/weight", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
 }
 
 func TestAddWeightLog_MissingWeight(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token := createWeightTestUser(t, db, 1, "testuser", "metric")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.POST("/weight", AddWeightLog)
-
-	// Create request without weight
-	body := map[string]interface{}{
-		"unit": "metric",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/weight", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", w.Code)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createWeightTestUser(t, stores, 1, "testuser", "metric")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/weight", NewWeightHandler(stores.Weights, stores.Profiles).AddWeightLog)
+
+		// Create request without weight
+		body := map[string]interface{}{
+			"unit": "metric",
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/weight", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
 }
 
 func TestAddWeightLog_CustomLoggedAt(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token := createWeightTestUser(t, db, 1, "testuser", "metric")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.POST("/weight", AddWeightLog)
-
-	// Create request with custom logged_at timestamp
-	customTime := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
-	body := map[string]interface{}{
-		"weight":    75.0,
-		"unit":      "metric",
-		"logged_at": customTime.Format(time.RFC3339),
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/weight", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-	requestStartedAt := time.Now()
-
-	router.ServeHTTP(w, req)
-	requestFinishedAt := time.Now()
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	// Verify server time was used (custom client time is ignored)
-	var weightLog models.WeightLog
-	db.First(&weightLog)
-	if weightLog.LoggedAt.Before(requestStartedAt) || weightLog.LoggedAt.After(requestFinishedAt.Add(1*time.Second)) {
-		t.Errorf("Expected logged_at to use server time between %v and %v, got %v", requestStartedAt, requestFinishedAt, weightLog.LoggedAt)
-	}
-
-	if weightLog.LoggedAt.Equal(customTime) {
-		t.Errorf("Expected logged_at to ignore client custom time %v", customTime)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createWeightTestUser(t, stores, 1, "testuser", "metric")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/weight", NewWeightHandler(stores.Weights, stores.Profiles).AddWeightLog)
+
+		// Create request with custom logged_at timestamp
+		customTime := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+		body := map[string]interface{}{
+			"weight":    75.0,
+			"unit":      "metric",
+			"logged_at": customTime.Format(time.RFC3339),
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/weight", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		requestStartedAt := time.Now()
+
+		router.ServeHTTP(w, req)
+		requestFinishedAt := time.Now()
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		// Verify server time was used (custom client time is ignored)
+		weightLog := latestWeightLog(t, stores, 1)
+		if weightLog.LoggedAt.Before(requestStartedAt) || weightLog.LoggedAt.After(requestFinishedAt.Add(1*time.Second)) {
+			t.Errorf("Expected logged_at to use server time between %v and %v, got %v", requestStartedAt, requestFinishedAt, weightLog.LoggedAt)
+		}
+
+		if weightLog.LoggedAt.Equal(customTime) {
+			t.Errorf("Expected logged_at to ignore client custom time %v", customTime)
+		}
+	})
 }
 
 func TestModifyLastWeight_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token := createWeightTestUser(t, db, 1, "testuser", "metric")
-
-	// Seed a single log to modify
-	original := models.WeightLog{
-		UserID:   1,
-		WeightKG: 70.0,
-		LoggedAt: time.Now().Add(-1 * time.Hour),
-	}
-	db.Create(&original)
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.POST("/weight/modify", ModifyLastWeight)
-
-	body := map[string]interface{}{
-		"weight": 72.5,
-		"unit":   "metric",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/weight/modify", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	var updated models.WeightLog
-	db.First(&updated, original.ID)
-	if updated.WeightKG != 72.5 {
-		t.Errorf("Expected updated weight 72.5 kg, got %v", updated.WeightKG)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createWeightTestUser(t, stores, 1, "testuser", "metric")
+
+		// Seed a single log to modify
+		original := models.WeightLog{
+			UserID:   1,
+			WeightKG: 70.0,
+			LoggedAt: time.Now().Add(-1 * time.Hour),
+		}
+		seed(t, stores, &original)
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/weight/modify", NewWeightHandler(stores.Weights, stores.Profiles).ModifyLastWeight)
+
+		body := map[string]interface{}{
+			"weight": 72.5,
+			"unit":   "metric",
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/weight/modify", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		updated, _ := stores.Weights.Get(1, original.ID)
+		if updated.WeightKG != 72.5 {
+			t.Errorf("Expected updated weight 72.5 kg, got %v", updated.WeightKG)
+		}
+	})
 }
 
 func TestModifyLastWeight_NoLogs(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token := createWeightTestUser(t, db, 1, "testuser", "metric")
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.POST("/weight/modify", ModifyLastWeight)
-
-	body := map[string]interface{}{
-		"weight": 72.5,
-		"unit":   "metric",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/weight/modify", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusNotFound {
-		t.Errorf("Expected status 404 when no logs exist, got %d. Body: %s", w.Code, w.Body.String())
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createWeightTestUser(t, stores, 1, "testuser", "metric")
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/weight/modify", NewWeightHandler(stores.Weights, stores.Profiles).ModifyLastWeight)
+
+		body := map[string]interface{}{
+			"weight": 72.5,
+			"unit":   "metric",
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/weight/modify", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 when no logs exist, got %d. Body: %s", w.Code, w.Body.String())
+		}
+	})
 }
 
 func TestModifyLastWeight_UserIsolation(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token1 := createWeightTestUser(t, db, 1, "user1", "metric")
-	createWeightTestUser(t, db, 2, "user2", "metric")
-
-	log1 := models.WeightLog{UserID: 1, WeightKG: 70.0, LoggedAt: time.Now()}
-	log2 := models.WeightLog{UserID: 2, WeightKG: 80.0, LoggedAt: time.Now()}
-	db.Create(&log1)
-	db.Create(&log2)
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.POST("/weight/modify", ModifyLastWeight)
-
-	body := map[string]interface{}{
-		"weight": 71.0,
-		"unit":   "metric",
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/weight/modify", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token1)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	var user1Log models.WeightLog
-	db.First(&user1Log, log1.ID)
-	if user1Log.WeightKG != 71.0 {
-		t.Errorf("Expected user1 weight to be updated to 71.0, got %v", user1Log.WeightKG)
-	}
-
-	var user2Log models.WeightLog
-	db.First(&user2Log, log2.ID)
-	if user2Log.WeightKG != 80.0 {
-		t.Errorf("Expected user2 weight to remain 80.0, got %v", user2Log.WeightKG)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token1 := createWeightTestUser(t, stores, 1, "user1", "metric")
+		createWeightTestUser(t, stores, 2, "user2", "metric")
+
+		log1 := models.WeightLog{UserID: 1, WeightKG: 70.0, LoggedAt: time.Now()}
+		log2 := models.WeightLog{UserID: 2, WeightKG: 80.0, LoggedAt: time.Now()}
+		seed(t, stores, &log1)
+		seed(t, stores, &log2)
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/weight/modify", NewWeightHandler(stores.Weights, stores.Profiles).ModifyLastWeight)
+
+		body := map[string]interface{}{
+			"weight": 71.0,
+			"unit":   "metric",
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/weight/modify", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token1)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		user1Log, _ := stores.Weights.Get(1, log1.ID)
+		if user1Log.WeightKG != 71.0 {
+			t.Errorf("Expected user1 weight to be updated to 71.0, got %v", user1Log.WeightKG)
+		}
+
+		user2Log, _ := stores.Weights.Get(2, log2.ID)
+		if user2Log.WeightKG != 80.0 {
+			t.Errorf("Expected user2 weight to remain 80.0, got %v", user2Log.WeightKG)
+		}
+	})
 }
 
 func TestModifyLastWeight_DefaultsToPreferredUnits(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token := createWeightTestUser(t, db, 1, "testuser", "imperial")
-
-	original := models.WeightLog{
-		UserID:   1,
-		WeightKG: 90.0,
-		LoggedAt: time.Now().Add(-1 * time.Hour),
-	}
-	db.Create(&original)
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.POST("/weight/modify", ModifyLastWeight)
-
-	// No unit specified -> should default to preferred imperial units
-	body := map[string]interface{}{
-		"weight": 220.462262185,
-	}
-	jsonBody, _ := json.Marshal(body)
-	req := httptest.NewRequest("POST", "/weight/modify", bytes.NewBuffer(jsonBody))
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	var updated models.WeightLog
-	db.First(&updated, original.ID)
-	expectedKG := 100.0
-	if updated.WeightKG < expectedKG-0.01 || updated.WeightKG > expectedKG+0.01 {
-		t.Errorf("Expected updated weight ~100 kg (from preferred imperial), got %v", updated.WeightKG)
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createWeightTestUser(t, stores, 1, "testuser", "imperial")
+
+		original := models.WeightLog{
+			UserID:   1,
+			WeightKG: 90.0,
+			LoggedAt: time.Now().Add(-1 * time.Hour),
+		}
+		seed(t, stores, &original)
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.POST("/weight/modify", NewWeightHandler(stores.Weights, stores.Profiles).ModifyLastWeight)
+
+		// No unit specified -> should default to preferred imperial units
+		body := map[string]interface{}{
+			"weight": 220.462262185,
+		}
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/weight/modify", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		updated, _ := stores.Weights.Get(1, original.ID)
+		expectedKG := 100.0
+		if updated.WeightKG < expectedKG-0.01 || updated.WeightKG > expectedKG+0.01 {
+			t.Errorf("Expected updated weight ~100 kg (from preferred imperial), got %v", updated.WeightKG)
+		}
+	})
 }
 
 func TestGetWeightLogs_Success_Metric(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token := createWeightTestUser(t, db, 1, "testuser", "metric")
-
-	// Add some weight logs
-	logs := []models.WeightLog{
-		{UserID: 1, WeightKG: 70.0, LoggedAt: time.Now().Add(-48 * time.Hour)},
-		{UserID: 1, WeightKG: 71.5, LoggedAt: time.Now().Add(-24 * time.Hour)},
-		{UserID: 1, WeightKG: 72.0, LoggedAt: time.Now()},
-	}
-	for _, log := range logs {
-		db.Create(&log)
-	}
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/weight-logs", GetWeightLogs)
-
-	req := httptest.NewRequest("GET", "/weight-logs", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	var response map[string][]map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	entries := response["entries"]
-	if len(entries) != 3 {
-		t.Errorf("Expected 3 entries, got %d", len(entries))
-	}
-
-	// Verify weights are returned in metric (same as stored)
-	if entries[0]["weight"] != 72.0 {
-		t.Errorf("Expected weight 72.0, got %v", entries[0]["weight"])
-	}
-
-	// Verify unit is included
-	if entries[0]["unit"] != "metric" {
-		t.Errorf("Expected unit 'metric', got %v", entries[0]["unit"])
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createWeightTestUser(t, stores, 1, "testuser", "metric")
+
+		// Add some weight logs
+		logs := []models.WeightLog{
+			{UserID: 1, WeightKG: 70.0, LoggedAt: time.Now().Add(-48 * time.Hour)},
+			{UserID: 1, WeightKG: 71.5, LoggedAt: time.Now().Add(-24 * time.Hour)},
+			{UserID: 1, WeightKG: 72.0, LoggedAt: time.Now()},
+		}
+		for _, log := range logs {
+			seed(t, stores, &log)
+		}
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/weight-logs", NewWeightHandler(stores.Weights, stores.Profiles).GetWeightLogs)
+
+		req := httptest.NewRequest("GET", "/weight-logs", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var response map[string][]map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		entries := response["entries"]
+		if len(entries) != 3 {
+			t.Errorf("Expected 3 entries, got %d", len(entries))
+		}
+
+		// Verify weights are returned in metric (same as stored)
+		if entries[0]["weight"] != 72.0 {
+			t.Errorf("Expected weight 72.0, got %v", entries[0]["weight"])
+		}
+
+		// Verify unit is included
+		if entries[0]["unit"] != "metric" {
+			t.Errorf("Expected unit 'metric', got %v", entries[0]["unit"])
+		}
+	})
 }
 
 func TestGetWeightLogs_Success_Imperial(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token := createWeightTestUser(t, db, 1, "testuser", "imperial")
-
-	// Add weight logs (stored in kg)
-	logs := []models.WeightLog{
-		{UserID: 1, WeightKG: 100.0, LoggedAt: time.Now()},
-	}
-	for _, log := range logs {
-		db.Create(&log)
-	}
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/weight-logs", GetWeightLogs)
-
-	req := httptest.NewRequest("GET", "/weight-logs", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
-
-	var response map[string][]map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	entries := response["entries"]
-	if len(entries) != 1 {
-		t.Errorf("Expected 1 entry, got %d", len(entries))
-	}
-
-	// Verify weight was converted to lbs (100 kg = ~220.46 lbs)
-	expectedLbs := 220.462262185
-	actualWeight := entries[0]["weight"].(float64)
-	if actualWeight < expectedLbs-0.01 || actualWeight > expectedLbs+0.01 {
-		t.Errorf("Expected weight ~220.46 lbs, got %v", actualWeight)
-	}
-
-	// Verify unit is imperial
-	if entries[0]["unit"] != "imperial" {
-		t.Errorf("Expected unit 'imperial', got %v", entries[0]["unit"])
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createWeightTestUser(t, stores, 1, "testuser", "imperial")
+
+		// Add weight logs (stored in kg)
+		logs := []models.WeightLog{
+			{UserID: 1, WeightKG: 100.0, LoggedAt: time.Now()},
+		}
+		for _, log := range logs {
+			seed(t, stores, &log)
+		}
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/weight-logs", NewWeightHandler(stores.Weights, stores.Profiles).GetWeightLogs)
+
+		req := httptest.NewRequest("GET", "/weight-logs", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+
+		var response map[string][]map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		entries := response["entries"]
+		if len(entries) != 1 {
+			t.Errorf("Expected 1 entry, got %d", len(entries))
+		}
+
+		// Verify weight was converted to lbs (100 kg = ~220.46 lbs)
+		expectedLbs := 220.462262185
+		actualWeight := entries[0]["weight"].(float64)
+		if actualWeight < expectedLbs-0.01 || actualWeight > expectedLbs+0.01 {
+			t.Errorf("Expected weight ~220.46 lbs, got %v", actualWeight)
+		}
+
+		// Verify unit is imperial
+		if entries[0]["unit"] != "imperial" {
+			t.Errorf("Expected unit 'imperial', got %v", entries[0]["unit"])
+		}
+	})
 }
 
 func TestGetWeightLogs_Empty(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token := createWeightTestUser(t, db, 1, "testuser", "metric")
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createWeightTestUser(t, stores, 1, "testuser", "metric")
 
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/weight-logs", GetWeightLogs)
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/weight-logs", NewWeightHandler(stores.Weights, stores.Profiles).GetWeightLogs)
 
-	req := httptest.NewRequest("GET", "/weight-logs", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/weight-logs", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
 
-	router.ServeHTTP(w, req)
+		router.ServeHTTP(w, req)
 
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
-	}
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
 
-	var response map[string][]map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
+		var response map[string][]map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
 
-	entries := response["entries"]
-	if len(entries) != 0 {
-		t.Errorf("Expected 0 entries, got %d", len(entries))
-	}
+		entries := response["entries"]
+		if len(entries) != 0 {
+			t.Errorf("Expected 0 entries, got %d", len(entries))
+		}
+	})
 }
 
 func TestGetWeightLogs_UserIsolation(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token1 := createWeightTestUser(t, db, 1, "user1", "metric")
-	createWeightTestUser(t, db, 2, "user2", "metric")
-
-	// Add weight logs for both users
-	logs := []models.WeightLog{
-		{UserID: 1, WeightKG: 70.0, LoggedAt: time.Now()},
-		{UserID: 2, WeightKG: 80.0, LoggedAt: time.Now()},
-	}
-	for _, log := range logs {
-		db.Create(&log)
-	}
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/weight-logs", GetWeightLogs)
-
-	// Request as user 1
-	req := httptest.NewRequest("GET", "/weight-logs", nil)
-	req.Header.Set("Authorization", "Bearer "+token1)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", w.Code)
-	}
-
-	var response map[string][]map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	entries := response["entries"]
-	if len(entries) != 1 {
-		t.Errorf("Expected 1 entry for user 1, got %d", len(entries))
-	}
-
-	// Verify only user 1's data is returned
-	if entries[0]["weight"] != 70.0 {
-		t.Errorf("Expected weight 70.0, got %v", entries[0]["weight"])
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token1 := createWeightTestUser(t, stores, 1, "user1", "metric")
+		createWeightTestUser(t, stores, 2, "user2", "metric")
+
+		// Add weight logs for both users
+		logs := []models.WeightLog{
+			{UserID: 1, WeightKG: 70.0, LoggedAt: time.Now()},
+			{UserID: 2, WeightKG: 80.0, LoggedAt: time.Now()},
+		}
+		for _, log := range logs {
+			seed(t, stores, &log)
+		}
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/weight-logs", NewWeightHandler(stores.Weights, stores.Profiles).GetWeightLogs)
+
+		// Request as user 1
+		req := httptest.NewRequest("GET", "/weight-logs", nil)
+		req.Header.Set("Authorization", "Bearer "+token1)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var response map[string][]map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		entries := response["entries"]
+		if len(entries) != 1 {
+			t.Errorf("Expected 1 entry for user 1, got %d", len(entries))
+		}
+
+		// Verify only user 1's data is returned
+		if entries[0]["weight"] != 70.0 {
+			t.Errorf("Expected weight 70.0, got %v", entries[0]["weight"])
+		}
+	})
 }
 
 func TestGetWeightLogs_OrderedDescending(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	db := setupWeightTestDB(t)
-	token := createWeightTestUser(t, db, 1, "testuser", "metric")
-
-	// Add weight logs in non-chronological order
-	logs := []models.WeightLog{
-		{UserID: 1, WeightKG: 70.0, LoggedAt: time.Now().Add(-48 * time.Hour)},
-		{UserID: 1, WeightKG: 72.0, LoggedAt: time.Now()},
-		{UserID: 1, WeightKG: 71.0, LoggedAt: time.Now().Add(-24 * time.Hour)},
-	}
-	for _, log := range logs {
-		db.Create(&log)
-	}
-
-	router := gin.New()
-	router.Use(middleware.AuthMiddleware())
-	router.GET("/weight-logs", GetWeightLogs)
-
-	req := httptest.NewRequest("GET", "/weight-logs", nil)
-	req.Header.Set("Authorization", "Bearer "+token)
-	w := httptest.NewRecorder()
-
-	router.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", w.Code)
-	}
-
-	var response map[string][]map[string]interface{}
-	json.Unmarshal(w.Body.Bytes(), &response)
-
-	entries := response["entries"]
-
-	// Verify entries are ordered by logged_at DESC (most recent first)
-	if entries[0]["weight"] != 72.0 {
-		t.Errorf("Expected most recent weight (72.0) first, got %v", entries[0]["weight"])
-	}
-	if entries[1]["weight"] != 71.0 {
-		t.Errorf("Expected second most recent weight (71.0), got %v", entries[1]["weight"])
-	}
-	if entries[2]["weight"] != 70.0 {
-		t.Errorf("Expected oldest weight (70.0) last, got %v", entries[2]["weight"])
-	}
+	t.Parallel()
+	forEachBackend(t, func(t *testing.T, stores store.Stores) {
+		token := createWeightTestUser(t, stores, 1, "testuser", "metric")
+
+		// Add weight logs in non-chronological order
+		logs := []models.WeightLog{
+			{UserID: 1, WeightKG: 70.0, LoggedAt: time.Now().Add(-48 * time.Hour)},
+			{UserID: 1, WeightKG: 72.0, LoggedAt: time.Now()},
+			{UserID: 1, WeightKG: 71.0, LoggedAt: time.Now().Add(-24 * time.Hour)},
+		}
+		for _, log := range logs {
+			seed(t, stores, &log)
+		}
+
+		router := gin.New()
+		router.Use(middleware.AuthMiddleware())
+		router.GET("/weight-logs", NewWeightHandler(stores.Weights, stores.Profiles).GetWeightLogs)
+
+		req := httptest.NewRequest("GET", "/weight-logs", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		var response map[string][]map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+
+		entries := response["entries"]
+
+		// Verify entries are ordered by logged_at DESC (most recent first)
+		if entries[0]["weight"] != 72.0 {
+			t.Errorf("Expected most recent weight (72.0) first, got %v", entries[0]["weight"])
+		}
+		if entries[1]["weight"] != 71.0 {
+			t.Errorf("Expected second most recent weight (71.0), got %v", entries[1]["weight"])
+		}
+		if entries[2]["weight"] != 70.0 {
+			t.Errorf("Expected oldest weight (70.0) last, got %v", entries[2]["weight"])
+		}
+	})
 }
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_RunsUntilStopped(t *testing.T) {
+	scheduler := NewScheduler()
+
+	var runs atomic.Int32
+	scheduler.Every("count", 5*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	time.Sleep(40 * time.Millisecond)
+	scheduler.Stop()
+
+	stopped := runs.Load()
+	if stopped == 0 {
+		t.Fatal("Expected job to run at least once")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if runs.Load() != stopped {
+		t.Error("Expected job not to run after Stop")
+	}
+}
+
+func TestScheduler_KeepsRunningAfterError(t *testing.T) {
+	scheduler := NewScheduler()
+	defer scheduler.Stop()
+
+	var runs atomic.Int32
+	scheduler.Every("failing", 5*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return errors.New("boom")
+	})
+
+	time.Sleep(40 * time.Millisecond)
+	if runs.Load() < 2 {
+		t.Errorf("Expected failing job to keep running, ran %d times", runs.Load())
+	}
+}
@@ -0,0 +1,25 @@
+// Package logging builds the application's structured logger
+package logging
+
+import (
+	"io"
+	"log/slog"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+)
+
+// New returns a logger writing to w in the configured format. The config
+// must already be validated.
+func New(cfg config.LogConfig, w io.Writer) *slog.Logger {
+	var level slog.Level
+	// "debug", "info", "warn" and "error" are all names slog accepts
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		level = slog.LevelInfo
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	if cfg.Format == "text" {
+		return slog.New(slog.NewTextHandler(w, options))
+	}
+	return slog.New(slog.NewJSONHandler(w, options))
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+)
+
+func TestNew_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(config.LogConfig{Level: "warn", Format: "json"}, &buf)
+
+	logger.Info("dropped")
+	logger.Warn("kept", "user_id", 7)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected only the warning to be logged, got %q", buf.String())
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("Expected a JSON line, got %q", lines[0])
+	}
+	if entry["msg"] != "kept" || entry["level"] != "WARN" || entry["user_id"] != float64(7) {
+		t.Errorf("Unexpected entry %v", entry)
+	}
+}
+
+func TestNew_Text(t *testing.T) {
+	var buf bytes.Buffer
+	New(config.LogConfig{Level: "debug", Format: "text"}, &buf).Debug("hello", "k", "v")
+
+	if !strings.Contains(buf.String(), "msg=hello k=v") {
+		t.Errorf("Expected a text line, got %q", buf.String())
+	}
+}
@@ -0,0 +1,43 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogMailer writes messages to a writer instead of sending them. Used for
+// local development.
+type LogMailer struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewLogMailer writes to out, or to the standard logger when out is nil
+func NewLogMailer(out io.Writer) *LogMailer {
+	if out == nil {
+		out = log.Writer()
+	}
+	return &LogMailer{out: out}
+}
+
+// NewFileMailer appends messages to the file at path
+func NewFileMailer(path string) (*LogMailer, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open mail log: %w", err)
+	}
+	return NewLogMailer(file), nil
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(m.out, "---- mail %s ----\nTo: %s\nSubject: %s\n\n%s\n", time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+	return err
+}
@@ -0,0 +1,107 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+)
+
+func TestLogMailer_Writes(t *testing.T) {
+	var out bytes.Buffer
+	m := NewLogMailer(&out)
+
+	msg := Message{To: "a@example.com", Subject: "Hello", Body: "Body text"}
+	if err := m.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "To: a@example.com") || !strings.Contains(out.String(), "Body text") {
+		t.Errorf("Expected message in output, got %q", out.String())
+	}
+}
+
+func TestNew_FileMailer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	m, err := New(config.MailConfig{Driver: "log", LogFile: path})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	m.Send(context.Background(), Message{To: "b@example.com", Subject: "Reset", Body: "link"})
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "b@example.com") {
+		t.Errorf("Expected message in mail log, got %q", string(data))
+	}
+}
+
+func TestNew_UnknownDriver(t *testing.T) {
+	if _, err := New(config.MailConfig{Driver: "pigeon"}); err == nil {
+		t.Error("Expected error for unknown driver")
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	m := NewSMTPMailer(config.SMTPConfig{Host: "smtp.example.com", Port: 587, From: "noreply@example.com"})
+
+	var gotAddr string
+	var gotTo []string
+	var gotMsg []byte
+	m.send = func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, msg
+		return nil
+	}
+
+	err := m.Send(context.Background(), Message{To: "c@example.com", Subject: "Hi", Body: "there"})
+	if err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	if gotAddr != "smtp.example.com:587" {
+		t.Errorf("Expected relay address smtp.example.com:587, got %s", gotAddr)
+	}
+	if len(gotTo) != 1 || gotTo[0] != "c@example.com" {
+		t.Errorf("Unexpected recipients %v", gotTo)
+	}
+	if !strings.Contains(string(gotMsg), "Subject: Hi\r\n") || !strings.HasSuffix(string(gotMsg), "\r\n\r\nthere") {
+		t.Errorf("Unexpected message %q", string(gotMsg))
+	}
+}
+
+func TestSendMail_StopsAtContextDeadline(t *testing.T) {
+	// a relay that accepts the connection but never greets
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = sendMail(ctx, ln.Addr().String(), nil, "noreply@example.com", []string{"d@example.com"}, []byte("body"))
+	if err == nil {
+		t.Fatal("Expected an error from a relay that never answers")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected send to give up at the context deadline, took %v", elapsed)
+	}
+}
@@ -1,44 +1,137 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/handlers"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/jobs"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/logging"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/mailer"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/metrics"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/notify"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/reminders"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/routes"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
 )
 
 func main() {
-	// Initialize database
+	// backend migrate ... manages the schema without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	// Load configuration (file is optional, environment overrides it)
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	makeAdmin := flag.String("make-admin", "", "give the named user the admin role and exit")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatal("Invalid configuration:\n", err)
+	}
+
+	logger := logging.New(cfg.Log, os.Stderr)
+	slog.SetDefault(logger)
+
+	if err := utils.ConfigureJWT(cfg.JWT); err != nil {
+		log.Fatal("Failed to load JWT signing keys: ", err)
+	}
 
-	database.InitDatabase()
+	// stop on Ctrl-C or when the orchestrator asks us to
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// a second signal during the drain delay or shutdown ends the process
+	// at once
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
 
-	// Get database instance
-	db := database.GetDB()
-	if db == nil {
-		log.Fatal("Database not initialized")
+	if err := run(ctx, cfg, logger, *makeAdmin); err != nil {
+		logger.Error("server failed", "error", err)
+		os.Exit(1)
 	}
+}
 
+// run starts the server and blocks until ctx is cancelled and everything
+// has shut down. Resources are released through defers, so it returns
+// errors instead of exiting.
+func run(ctx context.Context, cfg *config.Config, logger *slog.Logger, makeAdmin string) error {
 	// Connect database and run migrations
-	database.Connect()
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to set up database: %w", err)
+	}
+	defer func() {
+		if err := database.Close(db); err != nil {
+			logger.Error("failed to close database", "error", err)
+		}
+	}()
+
+	if makeAdmin != "" {
+		if err := database.SetRole(db, makeAdmin, models.RoleAdmin); err != nil {
+			return fmt.Errorf("failed to make admin: %w", err)
+		}
+		logger.Info("user is now an admin", "username", makeAdmin)
+		return nil
+	}
+
+	stores := database.NewStores(db)
+
+	// Share token revocations between instances through the database
+	middleware.SetRevocationStore(stores.Revocations)
+	middleware.SetPersonalTokenStore(stores.PersonalTokens)
+
+	loginThrottle := utils.NewLoginThrottleFromConfig(cfg.Auth.Lockout)
+	handlers.SetLoginThrottle(loginThrottle)
 
-	//Create a new Gin router with default middleware (logger and recovery)
-	r := gin.Default()
+	// Background jobs; stopped after the HTTP server so no request sees
+	// them half gone, and before the database closes
+	scheduler := jobs.NewScheduler()
+	defer scheduler.Stop()
+	scheduler.Every("prune-revocations", time.Hour, func(ctx context.Context) error {
+		return stores.Revocations.Prune(time.Now())
+	})
+	scheduler.Every("prune-login-attempts", 10*time.Minute, func(ctx context.Context) error {
+		loginThrottle.Prune(time.Now())
+		return nil
+	})
+	scheduler.Every("prune-rate-limits", time.Minute, func(ctx context.Context) error {
+		return middleware.RateLimits().Prune(time.Now())
+	})
+	scheduler.Every("purge-deleted-accounts", time.Hour, func(ctx context.Context) error {
+		purged, err := database.PurgeScheduledDeletions(db, time.Now().Add(-cfg.Account.DeletionGracePeriod()))
+		if purged > 0 {
+			logger.Info("purged deleted accounts", "count", purged)
+		}
+		return err
+	})
 
-	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:4200", "http://127.0.0.1:4200"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	}))
+	// Every request gets an ID first so the access log and any panic can
+	// carry it. Recovery goes last so a panic is still logged and counted
+	// as a 500.
+	r := gin.New()
+	r.Use(middleware.RequestID(), middleware.RequestLogger(logger), middleware.Metrics(), middleware.Recovery())
 
-	if err := r.SetTrustedProxies([]string{"127.0.0.1", "::1"}); err != nil {
-		log.Fatal("Failed to set trusted proxies:", err)
+	r.Use(middleware.CORS(cfg.CORS))
+
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return fmt.Errorf("failed to set trusted proxies: %w", err)
 	}
 
 	// Define routes
@@ -46,14 +139,45 @@ func main() {
 		c.JSON(200, gin.H{"message": "Fitness Tracker API"})
 	})
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "OK"})
+	// liveness says the process is up; readiness also needs the database
+	health := handlers.NewHealthHandler(func(ctx context.Context) error {
+		return database.Ping(ctx, db)
 	})
+	r.GET("/health", health.Live)
+	r.GET("/health/live", health.Live)
+	r.GET("/health/ready", health.Ready)
+
+	if cfg.Metrics.Enabled {
+		r.GET("/metrics", middleware.MetricsToken(cfg.Metrics.Token), gin.WrapH(metrics.Handler()))
+	}
+
+	mail, err := mailer.New(cfg.Mail)
+	if err != nil {
+		return fmt.Errorf("failed to set up mailer: %w", err)
+	}
+
+	// hydration reminders go to the in-app inbox and the optional webhook
+	if cfg.Reminders.Enabled {
+		progress := handlers.NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages)
+		dispatcher := reminders.NewDispatcher(stores.Reminders, stores.Users, stores.Profiles, stores.Water, progress, notify.New(cfg.Reminders, stores.Notifications))
+		scheduler.Every("hydration-reminders", cfg.Reminders.CheckInterval.Std(), dispatcher.Run)
+	}
+
+	routes.SetupRoutes(r, stores, cfg, mail)
+
+	listener, err := net.Listen("tcp", cfg.Server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to start server: %w", err)
+	}
 
-	routes.SetupRoutes(r, db)
+	logger.Info("server starting", "addr", listener.Addr().String(), "environment", cfg.Environment)
+	serveErr := serve(ctx, newHTTPServer(cfg.Server, r), listener, cfg.Server.DrainDelay.Std(), cfg.Server.ShutdownTimeout.Std(), health.Drain)
 
-	log.Println("Server starting on :8080")
-	if err := r.Run(":8080"); err != nil {
-		log.Fatal("Failed to start server:", err)
+	// reset mails still going out write to the database closed below
+	waitCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Std())
+	defer cancel()
+	if err := handlers.WaitPendingResets(waitCtx); err != nil {
+		logger.Warn("gave up waiting for password reset mails", "error", err)
 	}
+	return serveErr
 }
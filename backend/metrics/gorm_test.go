@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+func TestGormPlugin_TimesQueries(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.Use(GormPlugin{}); err != nil {
+		t.Fatalf("install plugin: %v", err)
+	}
+	if err := db.AutoMigrate(&widget{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	if err := db.Create(&widget{Name: "a"}).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	var found widget
+	if err := db.First(&found).Error; err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	for _, operation := range []string{"create", "query"} {
+		histogram := DBQueryDuration.WithLabelValues(operation, "widgets")
+		if count := sampleCount(t, histogram); count == 0 {
+			t.Errorf("Expected %s on widgets to be timed", operation)
+		}
+	}
+
+	// a missing row is a normal answer, not a failed query
+	errorsBefore := testutil.ToFloat64(DBQueryErrors.WithLabelValues("query", "widgets"))
+	db.First(&found, 999)
+	if got := testutil.ToFloat64(DBQueryErrors.WithLabelValues("query", "widgets")); got != errorsBefore {
+		t.Errorf("Expected record not found to be ignored, errors went from %v to %v", errorsBefore, got)
+	}
+
+	db.Exec("SELECT * FROM missing_table")
+	if testutil.ToFloat64(DBQueryErrors.WithLabelValues("raw", "unknown")) == 0 {
+		t.Error("Expected the failed raw query to be counted")
+	}
+}
+
+func sampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	t.Helper()
+	var m dto.Metric
+	if err := observer.(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("read histogram: %v", err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
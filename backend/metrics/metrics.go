@@ -0,0 +1,107 @@
+// Package metrics defines the Prometheus metrics the API exports at
+// /metrics. The collectors are package level so any package can count
+// events without having them passed in.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "fitness"
+
+// Registry holds every metric below plus the Go runtime and process
+// collectors. A registry of our own keeps tests and other libraries from
+// adding to what we export.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts finished requests. route is the Gin route
+	// template, never the raw path, so IDs do not create new series.
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// DBQueryDuration times every GORM operation; see GormPlugin
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency by operation and table.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Failed database queries by operation and table. Missing rows are not counted.",
+	}, []string{"operation", "table"})
+
+	// AuthFailures counts rejected credentials, e.g. reason="invalid_token"
+	// from AuthMiddleware or reason="bad_password" from Login
+	AuthFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_failures_total",
+		Help:      "Rejected authentication attempts by source and reason.",
+	}, []string{"source", "reason"})
+
+	// LogsCreated counts health entries users record: water, weight and
+	// exercise
+	LogsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logs_created_total",
+		Help:      "Health log entries created by kind.",
+	}, []string{"kind"})
+)
+
+// Auth failure sources
+const (
+	SourceMiddleware = "middleware"
+	SourceLogin      = "login"
+)
+
+// Log kinds for LogsCreated
+const (
+	KindWater    = "water"
+	KindWeight   = "weight"
+	KindExercise = "exercise"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		DBQueryDuration,
+		DBQueryErrors,
+		AuthFailures,
+		LogsCreated,
+	)
+}
+
+// AuthFailure counts one rejected authentication attempt
+func AuthFailure(source, reason string) {
+	AuthFailures.WithLabelValues(source, reason).Inc()
+}
+
+// LogCreated counts one new health log entry
+func LogCreated(kind string) {
+	LogsCreated.WithLabelValues(kind).Inc()
+}
+
+// Handler serves the registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -1,55 +1,148 @@
 package middleware
 
 import (
-    "net/http"
+	"net/http"
 	"strings"
-	
-	"github.com/gin-gonic/gin"
+	"time"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/metrics"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+	"github.com/gin-gonic/gin"
 )
 
+// revocations is consulted for every token; main swaps in the database
+// store so all instances share it
+var revocations utils.RevocationStore = utils.NewMemoryRevocationStore()
+
+// SetRevocationStore replaces the store used by AuthMiddleware
+func SetRevocationStore(store utils.RevocationStore) {
+	revocations = store
+}
+
+// Revocations returns the store used by AuthMiddleware
+func Revocations() utils.RevocationStore {
+	return revocations
+}
+
+// personalTokens authenticates personal access tokens; until main sets it
+// they are refused
+var personalTokens utils.PersonalTokenStore
+
+// SetPersonalTokenStore enables personal access tokens in AuthMiddleware
+func SetPersonalTokenStore(store utils.PersonalTokenStore) {
+	personalTokens = store
+}
+
 func AuthMiddleware() gin.HandlerFunc {
-    return func(c *gin.Context) {
-        // get header
-        authHeader := c.GetHeader("Authorization")
-        // make sure header exists
-        if authHeader == "" {
-            c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing authorization header"})
-            c.Abort()
-            return
-        }
-
-        // extract token
-        tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-        // make sure format was right
-        if tokenString == authHeader {
-            c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format."})
-            c.Abort()
-            return
-        }
-
-        // validate token
-        claims, err := utils.ValidateToken(tokenString)
-        if err != nil {
-            c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-            c.Abort()
-            return
-        }
-
-        // token is valid, set user ID in context
-        c.Set("userID", claims.UserID)
-        // continue
-        c.Next()
-    }
+	return func(c *gin.Context) {
+		// get header
+		authHeader := c.GetHeader("Authorization")
+		// make sure header exists
+		if authHeader == "" {
+			metrics.AuthFailure(metrics.SourceMiddleware, "missing_header")
+			apierror.Abort(c, http.StatusUnauthorized, apierror.AuthRequired, "Missing authorization header")
+			return
+		}
+
+		// extract token
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		// make sure format was right
+		if tokenString == authHeader {
+			metrics.AuthFailure(metrics.SourceMiddleware, "bad_format")
+			apierror.Abort(c, http.StatusUnauthorized, apierror.InvalidToken, "Invalid authorization format.")
+			return
+		}
+
+		// personal access tokens are looked up instead of verified
+		if utils.IsPersonalToken(tokenString) {
+			authenticatePersonalToken(c, tokenString)
+			return
+		}
+
+		// validate token
+		claims, err := utils.ValidateToken(tokenString)
+		if err != nil || claims.Type != "" {
+			metrics.AuthFailure(metrics.SourceMiddleware, "invalid_token")
+			apierror.Abort(c, http.StatusUnauthorized, apierror.InvalidToken, "Invalid or expired token")
+			return
+		}
+
+		// make sure the token was not logged out
+		revoked, err := revocations.IsRevoked(claims)
+		if err != nil {
+			Logger(c).Error("Failed to verify token", "error", err)
+			apierror.Abort(c, http.StatusInternalServerError, apierror.InternalError, "Failed to verify token")
+			return
+		}
+		if revoked {
+			metrics.AuthFailure(metrics.SourceMiddleware, "revoked_token")
+			apierror.Abort(c, http.StatusUnauthorized, apierror.TokenRevoked, "Token has been revoked")
+			return
+		}
+
+		// token is valid, set user ID in context
+		c.Set("userID", claims.UserID)
+		c.Set("claims", claims)
+		// continue
+		c.Next()
+	}
+}
+
+func authenticatePersonalToken(c *gin.Context, tokenString string) {
+	if personalTokens == nil {
+		metrics.AuthFailure(metrics.SourceMiddleware, "invalid_personal_token")
+		apierror.Abort(c, http.StatusUnauthorized, apierror.InvalidToken, "Invalid or expired token")
+		return
+	}
+
+	token, err := personalTokens.Authenticate(tokenString, time.Now())
+	if err != nil {
+		Logger(c).Error("Failed to verify token", "error", err)
+		apierror.Abort(c, http.StatusInternalServerError, apierror.InternalError, "Failed to verify token")
+		return
+	}
+	if token == nil {
+		metrics.AuthFailure(metrics.SourceMiddleware, "invalid_personal_token")
+		apierror.Abort(c, http.StatusUnauthorized, apierror.InvalidToken, "Invalid or expired token")
+		return
+	}
+
+	c.Set("userID", token.UserID)
+	c.Set("personalToken", token)
+	c.Next()
 }
 
 // helper to get userID from context
 func GetUserID(c *gin.Context) (uint, bool) {
-    userID, exists := c.Get("userID")
-    if !exists {
-        return 0, false
-    }
-    
-    id, ok := userID.(uint)
-    return id, ok
-}
\ No newline at end of file
+	userID, exists := c.Get("userID")
+	if !exists {
+		return 0, false
+	}
+
+	id, ok := userID.(uint)
+	return id, ok
+}
+
+// helper to get the validated token claims from context
+func GetClaims(c *gin.Context) (*utils.Claims, bool) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return nil, false
+	}
+
+	typed, ok := claims.(*utils.Claims)
+	return typed, ok
+}
+
+// helper to get the personal access token a request was made with; false
+// for requests made with a login session
+func GetPersonalToken(c *gin.Context) (*utils.PersonalToken, bool) {
+	token, exists := c.Get("personalToken")
+	if !exists {
+		return nil, false
+	}
+
+	typed, ok := token.(*utils.PersonalToken)
+	return typed, ok
+}
@@ -28,7 +28,7 @@ func TestAuthMiddleware_MissingToken(t *testing.T) {
 		t.Errorf("Expected status 401, got %d", w.Code)
 	}
 
-	expected := `{"error":"Missing authorization header"}`
+	expected := `{"code":"AUTH_REQUIRED","message":"Missing authorization header"}`
 	if w.Body.String() != expected {
 		t.Errorf("Expected body %s, got %s", expected, w.Body.String())
 	}
@@ -107,4 +107,61 @@ func TestAuthMiddleware_MalformedHeader(t *testing.T) {
 	if w.Code != http.StatusUnauthorized {
 		t.Errorf("Expected status 401, got %d", w.Code)
 	}
-}
\ No newline at end of file
+}
+func TestAuthMiddleware_RevokedToken(t *testing.T) {
+	// setup
+	gin.SetMode(gin.TestMode)
+	store := utils.NewMemoryRevocationStore()
+	previous := Revocations()
+	SetRevocationStore(store)
+	defer SetRevocationStore(previous)
+
+	router := gin.New()
+	router.Use(AuthMiddleware())
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(200, gin.H{"message": "success"})
+	})
+
+	token, err := utils.GenerateToken(123, "test@example.com")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	claims, _ := utils.ValidateToken(token)
+	store.RevokeToken(claims.ID, claims.UserID, claims.ExpiresAt.Time)
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+
+	expected := `{"code":"TOKEN_REVOKED","message":"Token has been revoked"}`
+	if w.Body.String() != expected {
+		t.Errorf("Expected body %s, got %s", expected, w.Body.String())
+	}
+}
+
+func TestAuthMiddleware_RejectsMFAChallengeToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AuthMiddleware())
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(200, gin.H{"message": "success"})
+	})
+
+	// a challenge token only proves the password; it is not a session
+	token, _ := utils.GenerateMFAChallengeToken(1, "testuser")
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+)
+
+func setupCORSRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(config.Default().CORS))
+	router.GET("/api/profile", func(c *gin.Context) {
+		c.Header(RequestIDHeader, "req-123")
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestCORS_PreflightAllowsPatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(config.Default().CORS))
+	router.PATCH("/api/water/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	origin := config.Default().CORS.AllowOrigins[0]
+	req := httptest.NewRequest("OPTIONS", "/api/water/1", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", "PATCH")
+	req.Header.Set("Access-Control-Request-Headers", "Content-Type, Authorization")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected preflight to succeed with 204, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != origin {
+		t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", origin, got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Methods"); !strings.Contains(got, "PATCH") {
+		t.Errorf("Expected PATCH in Access-Control-Allow-Methods, got %q", got)
+	}
+}
+
+func TestCORS_RequestID(t *testing.T) {
+	router := setupCORSRouter()
+	origin := config.Default().CORS.AllowOrigins[0]
+
+	// clients may send their own request ID
+	req := httptest.NewRequest("OPTIONS", "/api/profile", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	req.Header.Set("Access-Control-Request-Headers", RequestIDHeader)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected preflight with %s to succeed with 204, got %d", RequestIDHeader, w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(strings.ToLower(got), strings.ToLower(RequestIDHeader)) {
+		t.Errorf("Expected %s in Access-Control-Allow-Headers, got %q", RequestIDHeader, got)
+	}
+
+	// and read the one the server answers with
+	req = httptest.NewRequest("GET", "/api/profile", nil)
+	req.Header.Set("Origin", origin)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Access-Control-Expose-Headers"); !strings.Contains(strings.ToLower(got), strings.ToLower(RequestIDHeader)) {
+		t.Errorf("Expected %s in Access-Control-Expose-Headers, got %q", RequestIDHeader, got)
+	}
+}
+
+func TestCORS_ExposesRateLimitHeaders(t *testing.T) {
+	router := setupCORSRouter()
+
+	req := httptest.NewRequest("GET", "/api/profile", nil)
+	req.Header.Set("Origin", config.Default().CORS.AllowOrigins[0])
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	exposed := strings.ToLower(w.Header().Get("Access-Control-Expose-Headers"))
+	for _, header := range []string{"Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy"} {
+		if !strings.Contains(exposed, strings.ToLower(header)) {
+			t.Errorf("Expected %s in Access-Control-Expose-Headers, got %q", header, exposed)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// RequestIDHeader carries the request ID in both directions, so a client
+// or proxy can choose one and support can find the matching log lines
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength stops clients from stuffing the logs through the header
+const maxRequestIDLength = 128
+
+// RequestID keeps a well-formed X-Request-ID from the client and makes up
+// one otherwise, then echoes it on the response
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			generated, err := utils.NewID()
+			if err != nil {
+				apierror.Abort(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error.")
+				return
+			}
+			id = generated
+		}
+
+		c.Set("requestID", id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' || r == ':') {
+			return false
+		}
+	}
+	return true
+}
+
+// GetRequestID returns the ID assigned by RequestID, or "" outside of it
+func GetRequestID(c *gin.Context) string {
+	return c.GetString("requestID")
+}
+
+// RequestLogger writes one access log entry per request and gives handlers
+// a logger tagged with the request ID (see Logger). It must come after
+// RequestID.
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestLogger := logger.With("request_id", GetRequestID(c))
+		c.Set("logger", requestLogger)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"bytes", c.Writer.Size(),
+			"client_ip", c.ClientIP(),
+		}
+		if userID, ok := GetUserID(c); ok {
+			attrs = append(attrs, "user_id", userID)
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		requestLogger.Log(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// Logger returns the request's logger, or the default logger outside of
+// RequestLogger
+func Logger(c *gin.Context) *slog.Logger {
+	if logger, ok := c.Get("logger"); ok {
+		if l, ok := logger.(*slog.Logger); ok {
+			return l
+		}
+	}
+	return slog.Default()
+}
+
+// Recovery turns a panic into a 500 with the request ID and logs the stack
+// instead of crashing the server
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// net/http uses this panic to abort a response on purpose
+			if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(recovered)
+			}
+
+			Logger(c).Error("panic while handling request", "panic", recovered, "stack", string(debug.Stack()))
+			apierror.Abort(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error.")
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func loggingRouter(buf *bytes.Buffer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+	router := gin.New()
+	router.Use(RequestID(), RequestLogger(logger), Recovery())
+	return router
+}
+
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line is not JSON: %q", line)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRequestID_KeepsValidHeader(t *testing.T) {
+	var buf bytes.Buffer
+	router := loggingRouter(&buf)
+	var seen string
+	router.GET("/test", func(c *gin.Context) {
+		seen = GetRequestID(c)
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set(RequestIDHeader, "client-abc.123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if seen != "client-abc.123" {
+		t.Errorf("Expected handler to see the client ID, got %q", seen)
+	}
+	if got := w.Header().Get(RequestIDHeader); got != "client-abc.123" {
+		t.Errorf("Expected response header to echo the ID, got %q", got)
+	}
+}
+
+func TestRequestID_ReplacesMissingOrInvalidHeader(t *testing.T) {
+	for _, header := range []string{"", "has spaces", "new\nline", strings.Repeat("a", maxRequestIDLength+1)} {
+		var buf bytes.Buffer
+		router := loggingRouter(&buf)
+		router.GET("/test", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+		req := httptest.NewRequest("GET", "/test", nil)
+		if header != "" {
+			req.Header[RequestIDHeader] = []string{header}
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		got := w.Header().Get(RequestIDHeader)
+		if got == "" || got == header {
+			t.Errorf("Expected a generated ID for header %q, got %q", header, got)
+		}
+	}
+}
+
+func TestRequestLogger_AccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	router := loggingRouter(&buf)
+	router.GET("/items/:id", func(c *gin.Context) {
+		c.Set("userID", uint(7))
+		Logger(c).Info("inside handler")
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+
+	req := httptest.NewRequest("GET", "/items/42", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	entries := logEntries(t, &buf)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d: %s", len(entries), buf.String())
+	}
+
+	// handler logs carry the request ID too
+	if entries[0]["msg"] != "inside handler" || entries[0]["request_id"] != "req-1" {
+		t.Errorf("Unexpected handler entry: %v", entries[0])
+	}
+
+	access := entries[1]
+	want := map[string]any{
+		"msg":        "request",
+		"level":      "WARN",
+		"request_id": "req-1",
+		"method":     "GET",
+		"route":      "/items/:id",
+		"path":       "/items/42",
+		"status":     float64(http.StatusNotFound),
+		"user_id":    float64(7),
+	}
+	for key, value := range want {
+		if access[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, access[key])
+		}
+	}
+	if _, ok := access["latency_ms"]; !ok {
+		t.Error("Expected latency_ms in the access log")
+	}
+}
+
+func TestRecovery_ReturnsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	router := loggingRouter(&buf)
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest("GET", "/panic", nil)
+	req.Header.Set(RequestIDHeader, "req-panic")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status 500, got %d", w.Code)
+	}
+	expected := `{"code":"INTERNAL_ERROR","message":"Internal server error.","request_id":"req-panic"}`
+	if w.Body.String() != expected {
+		t.Errorf("Expected body %s, got %s", expected, w.Body.String())
+	}
+
+	entries := logEntries(t, &buf)
+	if len(entries) == 0 || entries[0]["panic"] != "boom" || entries[0]["stack"] == nil {
+		t.Errorf("Expected the panic to be logged with its stack, got %s", buf.String())
+	}
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/metrics"
+)
+
+func TestMetrics_LabelsByRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Metrics())
+	router.GET("/metrics-test/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	counter := metrics.HTTPRequests.WithLabelValues("GET", "/metrics-test/:id", "204")
+	before := testutil.ToFloat64(counter)
+	for _, path := range []string{"/metrics-test/1", "/metrics-test/2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	if got := testutil.ToFloat64(counter) - before; got != 2 {
+		t.Errorf("Expected 2 requests on the route template, got %v", got)
+	}
+
+	unmatched := metrics.HTTPRequests.WithLabelValues("GET", "unmatched", "404")
+	before = testutil.ToFloat64(unmatched)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/wp-login.php", nil))
+	if got := testutil.ToFloat64(unmatched) - before; got != 1 {
+		t.Errorf("Expected unknown paths to share the unmatched label, got %v", got)
+	}
+}
+
+func TestMetricsToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/metrics", MetricsToken("scrape-me"), gin.WrapH(metrics.Handler()))
+
+	tests := []struct {
+		header string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"Bearer scrape-me", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("Authorization %q: expected status %d, got %d", tt.header, tt.status, w.Code)
+		}
+		if w.Code == http.StatusOK && !strings.Contains(w.Body.String(), "go_goroutines") {
+			t.Error("Expected the Prometheus exposition format")
+		}
+	}
+}
+
+func TestAuthMiddleware_CountsFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AuthMiddleware())
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	counter := metrics.AuthFailures.WithLabelValues(metrics.SourceMiddleware, "invalid_token")
+	before := testutil.ToFloat64(counter)
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer invalid-token")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got := testutil.ToFloat64(counter) - before; got != 1 {
+		t.Errorf("Expected one invalid_token failure, got %v", got)
+	}
+}
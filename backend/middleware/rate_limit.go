@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// rateLimits holds the token buckets for RateLimit; a shared store can be
+// swapped in so every instance enforces the same limits
+var rateLimits utils.RateLimitStore = utils.NewMemoryRateLimitStore()
+
+// SetRateLimitStore replaces the store used by RateLimit
+func SetRateLimitStore(store utils.RateLimitStore) {
+	rateLimits = store
+}
+
+// RateLimits returns the store used by RateLimit
+func RateLimits() utils.RateLimitStore {
+	return rateLimits
+}
+
+// RateLimitKey picks the bucket a request is charged to. An empty key lets
+// the request through unlimited.
+type RateLimitKey func(c *gin.Context) string
+
+// ByClientIP charges requests to the client address. Behind a proxy this
+// is only right if the proxy is in the router's trusted proxies.
+func ByClientIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser charges requests to the authenticated user, so users behind one
+// NAT do not share a limit. It must run after AuthMiddleware.
+func ByUser(c *gin.Context) string {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return ""
+	}
+	return "user:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+// RateLimit answers 429 once the caller's bucket under policy is empty.
+// Every limited response carries the RateLimit-* headers. A policy with no
+// requests disables the limit.
+func RateLimit(policy utils.RateLimitPolicy, key RateLimitKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if policy.Requests <= 0 || policy.Period <= 0 {
+			c.Next()
+			return
+		}
+		k := key(c)
+		if k == "" {
+			c.Next()
+			return
+		}
+
+		result, err := rateLimits.Take(k, policy, time.Now())
+		if err != nil {
+			// an unavailable store should not take the API down with it
+			Logger(c).Error("rate limit store failed", "policy", policy.Name, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(policy.Requests))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Header("RateLimit-Policy", strconv.Itoa(policy.Requests)+";w="+strconv.Itoa(ceilSeconds(policy.Period)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			apierror.Abort(c, http.StatusTooManyRequests, apierror.RateLimited, "Too many requests. Try again later.")
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+func rateLimitedRouter(t *testing.T, key RateLimitKey, setup ...gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	SetRateLimitStore(utils.NewMemoryRateLimitStore())
+	t.Cleanup(func() { SetRateLimitStore(utils.NewMemoryRateLimitStore()) })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if err := router.SetTrustedProxies([]string{"10.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	router.Use(setup...)
+	router.Use(RateLimit(utils.RateLimitPolicy{Name: "test", Requests: 2, Period: time.Minute}, key))
+	router.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestRateLimit_ByClientIP(t *testing.T) {
+	router := rateLimitedRouter(t, ByClientIP)
+
+	send := func(remote, forwarded string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = remote + ":1234"
+		if forwarded != "" {
+			req.Header.Set("X-Forwarded-For", forwarded)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := send("192.0.2.1", ""); w.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected status 200, got %d", i+1, w.Code)
+		}
+	}
+
+	w := send("192.0.2.1", "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429, got %d", w.Code)
+	}
+	headers := map[string]string{
+		"Retry-After":         "30",
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "60",
+		"RateLimit-Policy":    "2;w=60",
+	}
+	for name, want := range headers {
+		if got := w.Header().Get(name); got != want {
+			t.Errorf("Expected %s %q, got %q", name, want, got)
+		}
+	}
+
+	// a trusted proxy forwards for a different client, which has its own
+	// bucket; an untrusted one cannot pick a fresh address for itself
+	if w := send("10.0.0.1", "198.51.100.7"); w.Code != http.StatusOK {
+		t.Errorf("Expected the forwarded client to be allowed, got %d", w.Code)
+	}
+	if w := send("192.0.2.1", "198.51.100.8"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected X-Forwarded-For from an untrusted peer to be ignored, got %d", w.Code)
+	}
+}
+
+func TestRateLimit_ByUser(t *testing.T) {
+	var userID uint
+	router := rateLimitedRouter(t, ByUser, func(c *gin.Context) {
+		if userID != 0 {
+			c.Set("userID", userID)
+		}
+		c.Next()
+	})
+
+	send := func(id uint) int {
+		userID = id
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+		return w.Code
+	}
+
+	send(1)
+	send(1)
+	if code := send(1); code != http.StatusTooManyRequests {
+		t.Errorf("Expected user 1 to be limited, got %d", code)
+	}
+	if code := send(2); code != http.StatusOK {
+		t.Errorf("Expected user 2 to have their own bucket, got %d", code)
+	}
+	// nothing to key on: not limited here, AuthMiddleware refuses these
+	for i := 0; i < 3; i++ {
+		if code := send(0); code != http.StatusOK {
+			t.Errorf("Expected anonymous requests to pass, got %d", code)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+)
+
+// RequireRole lets a request through only if its session belongs to one of
+// the given roles. It must run after AuthMiddleware. Personal access tokens
+// carry no role and are always refused.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := GetClaims(c)
+		if !ok {
+			apierror.Abort(c, http.StatusForbidden, apierror.Forbidden, "Insufficient permissions")
+			return
+		}
+
+		role := claims.Role
+		if role == "" {
+			role = "user"
+		}
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		apierror.Abort(c, http.StatusForbidden, apierror.Forbidden, "Insufficient permissions")
+	}
+}
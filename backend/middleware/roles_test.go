@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+func TestRequireRole(t *testing.T) {
+	router := setupScopeRouter(t)
+	router.GET("/admin", RequireRole("admin"), func(c *gin.Context) {
+		c.JSON(200, gin.H{"message": "success"})
+	})
+
+	admin, _ := utils.GenerateSessionToken(1, "root", "admin", "")
+	coach, _ := utils.GenerateSessionToken(2, "coach", "coach", "")
+	user, _ := utils.GenerateToken(3, "someone")
+
+	if code := requestWithToken(router, "GET", "/admin", admin); code != http.StatusOK {
+		t.Errorf("Expected admin to pass, got %d", code)
+	}
+	if code := requestWithToken(router, "GET", "/admin", coach); code != http.StatusForbidden {
+		t.Errorf("Expected coach to be refused, got %d", code)
+	}
+	if code := requestWithToken(router, "GET", "/admin", user); code != http.StatusForbidden {
+		t.Errorf("Expected user to be refused, got %d", code)
+	}
+	if code := requestWithToken(router, "GET", "/admin", utils.PersonalTokenPrefix+"reader"); code != http.StatusForbidden {
+		t.Errorf("Expected a personal access token to be refused, got %d", code)
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+)
+
+// RequireScope limits personal access tokens on a route group to one
+// resource: reads (GET and HEAD) need "<resource>:read" and everything else
+// "<resource>:write". Login sessions have every scope.
+func RequireScope(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := resource + ":write"
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = resource + ":read"
+		}
+		checkScope(c, scope)
+	}
+}
+
+// RequireExactScope is RequireScope for routes whose method does not say
+// whether they change anything, e.g. a calculation behind a POST
+func RequireExactScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		checkScope(c, scope)
+	}
+}
+
+// SessionOnly refuses personal access tokens, for routes that manage the
+// account itself (passwords, sessions, tokens)
+func SessionOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := GetPersonalToken(c); ok {
+			apierror.Abort(c, http.StatusForbidden, apierror.SessionRequired, "Personal access tokens cannot be used here")
+			return
+		}
+		c.Next()
+	}
+}
+
+func checkScope(c *gin.Context, scope string) {
+	if token, ok := GetPersonalToken(c); ok && !token.Allows(scope) {
+		apierror.Abort(c, http.StatusForbidden, apierror.ScopeMissing, "Token is missing the "+scope+" scope")
+		return
+	}
+	c.Next()
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// fakePersonalTokens knows a fixed set of tokens
+type fakePersonalTokens map[string]*utils.PersonalToken
+
+func (f fakePersonalTokens) Authenticate(token string, now time.Time) (*utils.PersonalToken, error) {
+	return f[token], nil
+}
+
+func setupScopeRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	SetPersonalTokenStore(fakePersonalTokens{
+		utils.PersonalTokenPrefix + "reader": {ID: 1, UserID: 7, Scopes: []string{"water:read"}},
+	})
+	t.Cleanup(func() { SetPersonalTokenStore(nil) })
+
+	ok := func(c *gin.Context) { c.JSON(200, gin.H{"message": "success"}) }
+	router := gin.New()
+	router.Use(AuthMiddleware())
+	router.GET("/water", RequireScope("water"), ok)
+	router.POST("/water", RequireScope("water"), ok)
+	router.GET("/weight", RequireScope("weight"), ok)
+	router.POST("/password", SessionOnly(), ok)
+	return router
+}
+
+func requestWithToken(router *gin.Engine, method, path, token string) int {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRequireScope_PersonalToken(t *testing.T) {
+	router := setupScopeRouter(t)
+	token := utils.PersonalTokenPrefix + "reader"
+
+	if code := requestWithToken(router, "GET", "/water", token); code != http.StatusOK {
+		t.Errorf("Expected water:read to allow GET, got %d", code)
+	}
+	if code := requestWithToken(router, "POST", "/water", token); code != http.StatusForbidden {
+		t.Errorf("Expected POST to need water:write, got %d", code)
+	}
+	if code := requestWithToken(router, "GET", "/weight", token); code != http.StatusForbidden {
+		t.Errorf("Expected weight to need its own scope, got %d", code)
+	}
+	if code := requestWithToken(router, "POST", "/password", token); code != http.StatusForbidden {
+		t.Errorf("Expected session-only routes to refuse tokens, got %d", code)
+	}
+	if code := requestWithToken(router, "GET", "/water", utils.PersonalTokenPrefix+"unknown"); code != http.StatusUnauthorized {
+		t.Errorf("Expected an unknown token to be refused, got %d", code)
+	}
+}
+
+func TestRequireScope_SessionHasEveryScope(t *testing.T) {
+	router := setupScopeRouter(t)
+	token, _ := utils.GenerateToken(7, "testuser")
+
+	for _, route := range [][2]string{{"GET", "/water"}, {"POST", "/water"}, {"GET", "/weight"}, {"POST", "/password"}} {
+		if code := requestWithToken(router, route[0], route[1], token); code != http.StatusOK {
+			t.Errorf("%s %s: expected status 200, got %d", route[0], route[1], code)
+		}
+	}
+}
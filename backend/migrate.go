@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/database/migrations"
+)
+
+const migrateUsage = `usage: backend migrate [status|up|down] [flags]
+
+  status   list migrations and whether they are applied (default)
+  up       apply pending migrations (all, or up to -to)
+  down     revert the newest applied migrations (-steps, default 1)
+
+flags:
+`
+
+// runMigrate implements the "migrate" subcommand and returns the exit code
+func runMigrate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, migrateUsage)
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	dryRun := fs.Bool("dry-run", false, "show the SQL that would run, then roll it back")
+	target := fs.Int("to", 0, "with up: stop after this version")
+	steps := fs.Int("steps", 1, "with down: how many migrations to revert")
+
+	// the action may come before or after the flags
+	action := "status"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintln(stderr, "Invalid configuration:\n", err)
+		return 1
+	}
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		fmt.Fprintln(stderr, "Failed to connect to database:", err)
+		return 1
+	}
+	defer database.Close(db)
+	migrator := migrations.New(db)
+
+	var results []migrations.Result
+	switch action {
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			fmt.Fprintln(stderr, "Failed to read migrations:", err)
+			return 1
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(stdout, "%04d  %-30s  %s\n", status.Version, status.Name, applied)
+		}
+		return 0
+	case "up":
+		results, err = migrator.Up(*target, *dryRun)
+	case "down":
+		if *steps < 1 {
+			fmt.Fprintln(stderr, "-steps must be at least 1")
+			return 2
+		}
+		results, err = migrator.Down(*steps, *dryRun)
+	default:
+		fs.Usage()
+		return 2
+	}
+
+	verb := map[string]string{"up": "Applied", "down": "Reverted"}[action]
+	if *dryRun {
+		verb = "Would have " + strings.ToLower(verb)
+	}
+	for _, result := range results {
+		fmt.Fprintf(stdout, "%s %04d %s\n", verb, result.Version, result.Name)
+		if *dryRun {
+			for _, statement := range result.SQL {
+				fmt.Fprintf(stdout, "    %s;\n", statement)
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "Migration failed:", err)
+		return 1
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(stdout, "Nothing to do")
+	}
+	return 0
+}
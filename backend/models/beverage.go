@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Beverage is a drink that can be logged as water intake. The built-in
+// catalog has no UserID; users add their own beverages to it.
+type Beverage struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	UserID *uint  `gorm:"index" json:"user_id"` // nil for built-in beverages
+	Name   string `gorm:"size:50;not null" json:"name"`
+	// HydrationFactor is how much of the volume counts towards the water
+	// goal: 1 for water, less for coffee or beer, more for milk
+	HydrationFactor    float64   `gorm:"not null" json:"hydration_factor"`
+	CaffeineMGPer100ML float64   `gorm:"column:caffeine_mg_per_100ml;not null;default:0" json:"caffeine_mg_per_100ml"`
+	KcalPer100ML       float64   `gorm:"column:kcal_per_100ml;not null;default:0" json:"kcal_per_100ml"`
+	CreatedAt          time.Time `json:"created_at"`
+}
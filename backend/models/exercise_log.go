@@ -5,7 +5,7 @@ import "time"
 type ExerciseLog struct {
 	ID             uint      `gorm:"primaryKey"`
 	UserID         uint      `gorm:"index;not null"`
-	User           User      `gorm:"foreignKey:UserID;"`
+	User           User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
 	Type           string    `gorm:"not null"` // e.g., "Running", "Cycling"
 	Duration       int       `gorm:"not null"` // in minutes
 	CaloriesBurned int       `gorm:"not null"`
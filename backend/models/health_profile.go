@@ -3,18 +3,20 @@ package models
 import "time"
 
 type HealthProfile struct {
-    ID             uint       `gorm:"primaryKey" json:"id"`
-    UserID         uint       `gorm:"uniqueIndex;not null" json:"user_id"`
-    DateOfBirth    *time.Time `json:"date_of_birth"`
-    Sex            string     `gorm:"size:10" json:"sex"`
-    HeightCM       float64    `json:"height_cm"`
-    WeightKG       float64    `json:"weight_kg"`
-    NeckCM         *float64   `json:"neck_cm"`
-    WaistCM        *float64   `json:"waist_cm"`
-    HipsCM         *float64   `json:"hips_cm"`
-    ActivityLevel  string     `gorm:"size:20" json:"activity_level"`
-    PreferredUnits string     `gorm:"size:10;default:metric" json:"preferred_units"`
-    UpdatedAt      time.Time  `json:"updated_at"`
+    ID              uint       `gorm:"primaryKey" json:"id"`
+    UserID          uint       `gorm:"uniqueIndex;not null" json:"user_id"`
+    DateOfBirth     *time.Time `json:"date_of_birth"`
+    Sex             string     `gorm:"size:10" json:"sex"`
+    HeightCM        float64    `json:"height_cm"`
+    WeightKG        float64    `json:"weight_kg"`
+    NeckCM          *float64   `json:"neck_cm"`
+    WaistCM         *float64   `json:"waist_cm"`
+    HipsCM          *float64   `json:"hips_cm"`
+    ActivityLevel   string     `gorm:"size:20" json:"activity_level"`
+    PreferredUnits  string     `gorm:"size:10;default:metric" json:"preferred_units"`
+    TimeZone        string     `gorm:"size:64;not null;default:''" json:"time_zone"` // IANA name; empty is UTC
+    CaffeineLimitMG *int       `json:"caffeine_limit_mg"` // daily; nil for the default of 400mg
+    UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 type ProfileStats struct {
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// HydrationGoal sets a user's daily water goal from EffectiveFrom until
+// their next goal takes over. Earlier goals are kept so past days are
+// always measured against the goal that applied then.
+type HydrationGoal struct {
+	ID     uint `gorm:"primaryKey" json:"-"`
+	UserID uint `gorm:"not null;uniqueIndex:idx_hydration_goals_user_from" json:"-"`
+	// GoalML is nil for the automatic goal, worked out each day from the
+	// profile and that day's exercise
+	GoalML *int `json:"goal_ml"`
+	// EffectiveFrom is a YYYY-MM-DD day in the user's time zone
+	EffectiveFrom string    `gorm:"size:10;not null;uniqueIndex:idx_hydration_goals_user_from" json:"effective_from"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Where a day's water goal came from
+const (
+	WaterGoalCustom    = "custom"
+	WaterGoalAutomatic = "automatic"
+)
+
+// DailyWaterGoal is the water goal for one day
+type DailyWaterGoal struct {
+	Date   string `json:"date"` // YYYY-MM-DD
+	GoalML int    `json:"goal_ml"`
+	Source string `json:"source"` // custom or automatic
+	// EffectiveFrom is when the goal setting in use started; empty when the
+	// user never set one
+	EffectiveFrom string `json:"effective_from,omitempty"`
+	// Breakdown is only given for automatic goals
+	Breakdown *WaterGoalBreakdown `json:"breakdown,omitempty"`
+}
+
+// WaterGoalBreakdown shows how an automatic goal was worked out. GoalML is
+// the sum, kept within sensible limits.
+type WaterGoalBreakdown struct {
+	BaseML          int `json:"base_ml"` // from body weight, or a default without a profile
+	ActivityML      int `json:"activity_ml"`
+	ExerciseMinutes int `json:"exercise_minutes"`
+	ExerciseML      int `json:"exercise_ml"`
+}
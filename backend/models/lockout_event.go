@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// LockoutEvent records a login lockout for administrators to review
+type LockoutEvent struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Scope       string    `gorm:"size:10;not null" json:"scope"` // "username" or "ip"
+	Username    string    `gorm:"size:50;index" json:"username"`
+	IP          string    `gorm:"size:45;index" json:"ip"`
+	Failures    int       `gorm:"not null" json:"failures"`
+	LockedUntil time.Time `gorm:"not null" json:"locked_until"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
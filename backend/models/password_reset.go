@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// PasswordResetToken is a single-use token mailed to a user who forgot
+// their password. Only the SHA-256 of the token is stored.
+type PasswordResetToken struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"index;not null"`
+	TokenHash string     `gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time // set once redeemed or superseded
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
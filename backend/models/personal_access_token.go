@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// PersonalAccessToken lets scripts call the API as a user without their
+// password. Only the SHA-256 of the token is stored; Hint keeps its last
+// characters so users can tell their tokens apart.
+type PersonalAccessToken struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"index;not null" json:"-"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	TokenHash  string     `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	Hint       string     `gorm:"size:8;not null" json:"hint"`
+	Scopes     string     `gorm:"size:255;not null" json:"-"` // space separated
+	ExpiresAt  *time.Time `json:"expires_at"`                 // nil never expires
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
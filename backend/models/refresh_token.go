@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// RefreshToken is one link in a rotation chain. Every login starts a new
+// family; each refresh marks the presented token used and issues the next
+// one in the same family. Only the SHA-256 of the token is stored.
+type RefreshToken struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"index;not null"`
+	FamilyID  string     `gorm:"size:32;index;not null"`
+	TokenHash string     `gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time // set when exchanged for a new token
+	RevokedAt *time.Time // set when the family is revoked
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
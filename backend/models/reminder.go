@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Kinds of reminder rule
+const (
+	// ReminderInterval reminds the user when they have not logged a drink
+	// for IntervalMinutes
+	ReminderInterval = "interval"
+	// ReminderBehindPace reminds the user when today's percentage of their
+	// water goal is more than MarginPercent behind an even pace through
+	// their waking hours
+	ReminderBehindPace = "behind_pace"
+)
+
+// ReminderRule is one of a user's hydration reminders. Reminders are only
+// sent between StartTime and EndTime in the user's time zone, and at most
+// once every IntervalMinutes. An interval rule's hours may cross midnight.
+type ReminderRule struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	UserID          uint       `gorm:"not null;index" json:"-"`
+	Kind            string     `gorm:"size:20;not null" json:"kind"`
+	Enabled         bool       `gorm:"not null" json:"enabled"`
+	StartTime       string     `gorm:"size:5;not null" json:"start_time"` // HH:MM
+	EndTime         string     `gorm:"size:5;not null" json:"end_time"`   // HH:MM, after StartTime
+	IntervalMinutes int        `gorm:"not null" json:"interval_minutes"`
+	MarginPercent   int        `gorm:"not null;default:0" json:"margin_percent"` // behind_pace only
+	LastSentAt      *time.Time `json:"last_sent_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// NotificationHydrationReminder is the kind of notification reminder
+// rules send
+const NotificationHydrationReminder = "hydration_reminder"
+
+// Notification is a message in a user's in-app inbox
+type Notification struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"-"`
+	Kind      string     `gorm:"size:30;not null" json:"kind"`
+	Title     string     `gorm:"size:100;not null" json:"title"`
+	Body      string     `gorm:"size:500;not null" json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at"` // nil while unread
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// RevokedToken blocks a single access token (by jti) until it expires
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey;size:32"`
+	UserID    uint      `gorm:"index;not null"`
+	ExpiresAt time.Time `gorm:"index;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// UserRevocation blocks every access token of a user issued before
+// RevokedBefore ("log out everywhere")
+type UserRevocation struct {
+	UserID        uint      `gorm:"primaryKey;autoIncrement:false"`
+	RevokedBefore time.Time `gorm:"not null"`
+	ExpiresAt     time.Time `gorm:"index;not null"` // when the entry can be pruned
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// TwoFactor holds a user's TOTP secret. Enabled stays false until the user
+// proves their authenticator app works by entering a code from it.
+type TwoFactor struct {
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false"`
+	Secret    string    `gorm:"size:64;not null"`
+	Enabled   bool      `gorm:"not null;default:false"`
+	LastStep  int64     `gorm:"not null;default:0"` // last accepted time step, so codes cannot be replayed
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// RecoveryCode is a one-time code that stands in for a TOTP code when the
+// authenticator is lost. Only the SHA-256 of the code is stored.
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"index;not null"`
+	CodeHash  string     `gorm:"size:64;not null"`
+	UsedAt    *time.Time // set once redeemed
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
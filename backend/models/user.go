@@ -2,9 +2,34 @@ package models
 
 import "time"
 
+// Roles a user can have. Coaches and admins can do everything a user can;
+// admins can also manage other users through /api/admin.
+const (
+	RoleUser  = "user"
+	RoleCoach = "coach"
+	RoleAdmin = "admin"
+)
+
+// ValidRole reports whether role is one of the roles above
+func ValidRole(role string) bool {
+	return role == RoleUser || role == RoleCoach || role == RoleAdmin
+}
+
 type User struct {
 	ID           uint      `gorm:"primaryKey;autoIncrement"`
 	Username     string    `gorm:"uniqueIndex;size:50;not null"`
+	Email        *string   `gorm:"uniqueIndex;size:254"` // optional, needed for password reset
 	PasswordHash string    `gorm:"not null"`
+	Role         string    `gorm:"size:20;not null;default:user;index"`
 	CreatedAt    time.Time `gorm:"autoCreateTime"`
+
+	// set when the user asks to delete their account; the account is
+	// purged once the grace period has passed unless they restore it
+	DeletionRequestedAt *time.Time `gorm:"index"`
+
+	// set by an admin; disabled users cannot log in or use their tokens
+	DisabledAt *time.Time `gorm:"index"`
+	// set by an admin to make the user choose a new password through the
+	// reset flow before logging in again
+	PasswordResetRequired bool `gorm:"not null;default:false"`
 }
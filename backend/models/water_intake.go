@@ -3,19 +3,88 @@ package models
 import "time"
 
 type WaterIntake struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	UserID    uint      `gorm:"not null;index" json:"user_id"`
-	AmountML  int       `gorm:"not null" json:"amount_ml"`  // Amount in milliliters
-	LoggedAt  time.Time `gorm:"not null" json:"logged_at"`  // When they drank it
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"not null;index" json:"user_id"`
+	AmountML   int       `gorm:"not null" json:"amount_ml"` // Amount in milliliters
+	LoggedAt   time.Time `gorm:"not null" json:"logged_at"` // When they drank it
+	BeverageID *uint     `json:"beverage_id"`               // nil for plain water
+	// worked out from the beverage when logged, so later changes to the
+	// catalog leave the log as it was
+	HydrationML int       `gorm:"not null;default:0" json:"hydration_ml"` // AmountML that counts towards the goal
+	CaffeineMG  float64   `gorm:"not null;default:0" json:"caffeine_mg"`
+	Kcal        float64   `gorm:"not null;default:0" json:"kcal"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-// WaterIntakeSummary for daily/weekly summaries
+// WaterIntakeSummary totals one day
 type WaterIntakeSummary struct {
-	Date        string  `json:"date"`         // YYYY-MM-DD
+	Date        string  `json:"date"` // YYYY-MM-DD
 	TotalML     int     `json:"total_ml"`
 	EntryCount  int     `json:"entry_count"`
-	GoalML      int     `json:"goal_ml"`       // Optional daily goal
-	Percentage  float64 `json:"percentage"`    // % of goal achieved
-}
\ No newline at end of file
+	GoalML      int     `json:"goal_ml"`      // Goal in effect that day
+	GoalSource  string  `json:"goal_source"`  // custom or automatic
+	Percentage  float64 `json:"percentage"`   // % of goal achieved, by HydrationML
+	HydrationML int     `json:"hydration_ml"` // TotalML weighted by each beverage's hydration factor
+	CaffeineMG  float64 `json:"caffeine_mg"`
+	// CaffeineOverLimit is set when CaffeineMG is above CaffeineLimitMG
+	CaffeineLimitMG   int  `json:"caffeine_limit_mg"`
+	CaffeineOverLimit bool `json:"caffeine_over_limit"`
+}
+
+// WaterIntakeRange summarises the water logged over a range of days
+type WaterIntakeRange struct {
+	From        string              `json:"from"`        // YYYY-MM-DD
+	To          string              `json:"to"`          // YYYY-MM-DD, inclusive
+	Granularity string              `json:"granularity"` // day, week or month
+	Series      []WaterIntakePeriod `json:"series"`
+	Days        int                 `json:"days"`
+	TotalML     int                 `json:"total_ml"`
+	HydrationML int                 `json:"hydration_ml"`
+	CaffeineMG  float64             `json:"caffeine_mg"`
+	AverageML   float64             `json:"average_ml"`    // per day, including days with nothing logged
+	GoalHitDays int                 `json:"goal_hit_days"` // days on which the goal was reached
+	// CaffeineOverLimitDays counts days above the caffeine limit
+	CaffeineOverLimitDays int `json:"caffeine_over_limit_days"`
+	// BestDay and WorstDay have the highest and lowest percentage of their
+	// goal; the earlier day wins a tie
+	BestDay  WaterIntakeSummary `json:"best_day"`
+	WorstDay WaterIntakeSummary `json:"worst_day"`
+}
+
+// WaterIntakePeriod is one day, week or month of a WaterIntakeRange. Weeks
+// start on Monday. The first and last periods are cut to the range, so
+// they can be shorter.
+type WaterIntakePeriod struct {
+	Start       string  `json:"start"`
+	End         string  `json:"end"` // inclusive
+	Days        int     `json:"days"`
+	TotalML     int     `json:"total_ml"`
+	HydrationML int     `json:"hydration_ml"`
+	CaffeineMG  float64 `json:"caffeine_mg"`
+	EntryCount  int     `json:"entry_count"`
+	GoalML      int     `json:"goal_ml"`    // sum of the daily goals
+	Percentage  float64 `json:"percentage"` // % of GoalML achieved
+	AverageML   float64 `json:"average_ml"` // per day
+	GoalHitDays int     `json:"goal_hit_days"`
+}
+
+// WaterIntakeEdit is a water log as it was before one of its edits
+type WaterIntakeEdit struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	WaterIntakeID uint      `gorm:"not null;index" json:"water_intake_id"`
+	UserID        uint      `gorm:"not null;index" json:"-"`
+	AmountML      int       `gorm:"not null" json:"amount_ml"`
+	LoggedAt      time.Time `gorm:"not null" json:"logged_at"`
+	BeverageID    *uint     `json:"beverage_id"`
+	HydrationML   int       `gorm:"not null;default:0" json:"hydration_ml"`
+	CaffeineMG    float64   `gorm:"not null;default:0" json:"caffeine_mg"`
+	Kcal          float64   `gorm:"not null;default:0" json:"kcal"`
+	EditedAt      time.Time `gorm:"not null" json:"edited_at"` // when this version was replaced
+}
+
+// WaterIntakeHistory is a water log with its earlier versions, newest first
+type WaterIntakeHistory struct {
+	Current WaterIntake       `json:"current"`
+	Edits   []WaterIntakeEdit `json:"edits"`
+}
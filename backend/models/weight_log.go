@@ -5,7 +5,7 @@ import "time"
 type WeightLog struct {
 	ID       uint      `gorm:"primaryKey"`
 	UserID   uint      `gorm:"index;not null"`
-	User     User      `gorm:"foreignKey:UserID;"`
+	User     User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
 	WeightKG float64   `gorm:"not null"`
 	LoggedAt time.Time `gorm:"autoCreateTime"`
 }
@@ -0,0 +1,30 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+// InboxNotifier saves messages to the user's in-app inbox, which clients
+// read from /api/notifications
+type InboxNotifier struct {
+	inbox store.NotificationStore
+}
+
+func NewInboxNotifier(inbox store.NotificationStore) *InboxNotifier {
+	return &InboxNotifier{inbox: inbox}
+}
+
+func (n *InboxNotifier) Notify(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	notification := models.Notification{UserID: msg.UserID, Kind: msg.Kind, Title: msg.Title, Body: msg.Body}
+	if err := n.inbox.Create(&notification); err != nil {
+		return fmt.Errorf("save notification for user %d: %w", msg.UserID, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Package notify delivers notifications such as hydration reminders to
+// users.
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+// Message is a notification for one user
+type Message struct {
+	UserID uint
+	Kind   string // e.g. models.NotificationHydrationReminder
+	Title  string
+	Body   string
+}
+
+// Notifier delivers messages. Implementations must be safe for concurrent
+// use.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// New builds the notifier for reminders: the in-app inbox, plus the
+// webhook when one is configured
+func New(cfg config.ReminderConfig, inbox store.NotificationStore) Notifier {
+	notifiers := []Notifier{NewInboxNotifier(inbox)}
+	if cfg.WebhookURL != "" {
+		client := &http.Client{Timeout: cfg.WebhookTimeout.Std()}
+		notifiers = append(notifiers, NewWebhookNotifier(cfg.WebhookURL, cfg.WebhookSecret, client))
+	}
+	return All(notifiers...)
+}
+
+type all []Notifier
+
+// All sends each message through every notifier, even when one of them
+// fails, and returns their errors joined
+func All(notifiers ...Notifier) Notifier {
+	return all(notifiers)
+}
+
+func (notifiers all) Notify(ctx context.Context, msg Message) error {
+	var errs []error
+	for _, notifier := range notifiers {
+		if err := notifier.Notify(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
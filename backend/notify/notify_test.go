@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+var reminder = Message{UserID: 7, Kind: "hydration_reminder", Title: "Time for some water", Body: "Have a glass"}
+
+func TestInboxNotifier_SavesNotification(t *testing.T) {
+	inbox := store.NewMemoryNotificationStore()
+
+	if err := NewInboxNotifier(inbox).Notify(context.Background(), reminder); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+
+	saved, _ := inbox.List(7, 10)
+	if len(saved) != 1 || saved[0].Title != reminder.Title || saved[0].Kind != reminder.Kind || saved[0].ReadAt != nil {
+		t.Errorf("Expected an unread notification in the inbox, got %+v", saved)
+	}
+}
+
+func TestWebhookNotifier_PostsSignedJSON(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, "shh", server.Client())
+	if err := n.Notify(context.Background(), reminder); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Expected a JSON body, got %q", body)
+	}
+	if payload.UserID != 7 || payload.Title != reminder.Title || payload.SentAt.IsZero() {
+		t.Errorf("Unexpected payload %+v", payload)
+	}
+	if signature != "sha256="+Sign([]byte("shh"), body) {
+		t.Errorf("Expected the body to be signed, got %q", signature)
+	}
+}
+
+func TestWebhookNotifier_Failures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(SignatureHeader) != "" {
+			t.Error("Expected no signature without a secret")
+		}
+		if r.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	if err := NewWebhookNotifier(server.URL, "", server.Client()).Notify(context.Background(), reminder); err == nil {
+		t.Error("Expected an error for a 502 response")
+	}
+	client := &http.Client{Timeout: 10 * time.Millisecond}
+	if err := NewWebhookNotifier(server.URL+"/slow", "", client).Notify(context.Background(), reminder); err == nil {
+		t.Error("Expected an error when the webhook times out")
+	}
+}
+
+func TestAll_KeepsGoingAfterFailure(t *testing.T) {
+	failing, working := NewRecorder(), NewRecorder()
+	failing.Err = errors.New("boom")
+
+	err := All(failing, working).Notify(context.Background(), reminder)
+	if !errors.Is(err, failing.Err) {
+		t.Errorf("Expected the failure to be returned, got %v", err)
+	}
+	if len(working.Sent()) != 1 {
+		t.Error("Expected the second notifier to be used after the first failed")
+	}
+}
+
+func TestNew_InboxWithOptionalWebhook(t *testing.T) {
+	if notifiers := New(config.ReminderConfig{}, store.NewMemoryNotificationStore()).(all); len(notifiers) != 1 {
+		t.Errorf("Expected only the inbox without a webhook URL, got %d notifiers", len(notifiers))
+	}
+	cfg := config.ReminderConfig{WebhookURL: "https://hooks.example.com/water", WebhookTimeout: config.Duration(time.Second)}
+	if notifiers := New(cfg, store.NewMemoryNotificationStore()).(all); len(notifiers) != 2 {
+		t.Errorf("Expected the inbox and the webhook, got %d notifiers", len(notifiers))
+	}
+}
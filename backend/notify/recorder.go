@@ -0,0 +1,33 @@
+package notify
+
+import (
+	"context"
+	"sync"
+)
+
+// Recorder keeps messages in memory instead of sending them, so tests can
+// inspect what was sent. Err, when set, is returned from every Notify
+// after the message is recorded.
+type Recorder struct {
+	mu   sync.Mutex
+	sent []Message
+	Err  error
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Notify(ctx context.Context, msg Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, msg)
+	return r.Err
+}
+
+// Sent returns a copy of every message so far
+func (r *Recorder) Sent() []Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Message(nil), r.sent...)
+}
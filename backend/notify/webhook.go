@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook body, as
+// "sha256=<hex>", when the webhook has a secret
+const SignatureHeader = "X-Signature"
+
+// WebhookNotifier POSTs each message as JSON to a URL, e.g. a push
+// notification gateway
+type WebhookNotifier struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+// webhookPayload is the JSON body of a webhook request
+type webhookPayload struct {
+	UserID uint      `json:"user_id"`
+	Kind   string    `json:"kind"`
+	Title  string    `json:"title"`
+	Body   string    `json:"body"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+// NewWebhookNotifier posts to url. An empty secret leaves requests
+// unsigned.
+func NewWebhookNotifier(url, secret string, client *http.Client) *WebhookNotifier {
+	return &WebhookNotifier{url: url, secret: []byte(secret), client: client}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(webhookPayload{
+		UserID: msg.UserID,
+		Kind:   msg.Kind,
+		Title:  msg.Title,
+		Body:   msg.Body,
+		SentAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("post webhook: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign is the hex HMAC-SHA256 of body, for receivers checking
+// SignatureHeader
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,224 @@
+// Package openapi builds the OpenAPI 3 description of the API. Routes are
+// listed next to their handlers, and request and response schemas are
+// generated from the Go types the handlers bind and return, so the
+// document cannot drift from the structs.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+)
+
+const Version = "3.0.3"
+
+// Document is an OpenAPI document, ready to be encoded as JSON
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Route describes one endpoint as it is registered with Gin
+type Route struct {
+	Method string
+	// Path uses Gin's syntax, e.g. /api/water/:id
+	Path        string
+	ID          string // operationId, used for generated client method names
+	Tag         string
+	Summary     string
+	Description string
+	// Auth marks routes behind AuthMiddleware; they get the bearer
+	// security requirement and a 401 response
+	Auth  bool
+	Query []Parameter
+	// Body is the JSON request body, if any
+	Body      *Schema
+	Responses []Reply
+}
+
+// Reply is one possible response of a Route. Error statuses without a
+// body use the shared Error schema.
+type Reply struct {
+	Status      int
+	Description string
+	Body        *Schema
+}
+
+// Query describes an optional query string parameter
+func Query(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+const (
+	bearerAuth = "bearerAuth"
+	errorRef   = "#/components/schemas/Error"
+)
+
+// Build assembles the document for routes, generating the schemas for
+// every Go type they mention
+func Build(info Info, routes []Route) (*Document, error) {
+	g := newGenerator()
+	// registers the Error component that errorRef points at
+	g.resolve(TypeOf(apierror.Error{}))
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]map[string]*Operation),
+		Components: Components{
+			Schemas: g.components,
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerAuth: {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "An access token from /api/auth/login, or a personal access token",
+				},
+			},
+		},
+	}
+
+	for _, route := range routes {
+		path, parameters := convertPath(route.Path)
+		method := strings.ToLower(route.Method)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*Operation)
+		}
+		if _, exists := doc.Paths[path][method]; exists {
+			return nil, fmt.Errorf("openapi: %s %s is listed twice", route.Method, route.Path)
+		}
+		if route.ID == "" {
+			return nil, fmt.Errorf("openapi: %s %s has no ID", route.Method, route.Path)
+		}
+
+		op := &Operation{
+			OperationID: route.ID,
+			Summary:     route.Summary,
+			Description: route.Description,
+			Responses:   make(map[string]*Response),
+		}
+		if route.Tag != "" {
+			op.Tags = []string{route.Tag}
+		}
+		for _, query := range route.Query {
+			query.Schema = g.resolve(query.Schema)
+			parameters = append(parameters, query)
+		}
+		op.Parameters = parameters
+		if route.Body != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: g.resolve(route.Body)}},
+			}
+		}
+
+		replies := route.Responses
+		if route.Auth {
+			op.Security = []map[string][]string{{bearerAuth: {}}}
+			replies = append(replies, Reply{Status: http.StatusUnauthorized, Description: "Missing, invalid or revoked token"})
+		}
+		for _, reply := range replies {
+			status := strconv.Itoa(reply.Status)
+			if _, exists := op.Responses[status]; exists {
+				continue
+			}
+			body := g.resolve(reply.Body)
+			if body == nil && reply.Status >= 400 {
+				body = &Schema{Ref: errorRef}
+			}
+			description := reply.Description
+			if description == "" {
+				description = http.StatusText(reply.Status)
+			}
+			response := &Response{Description: description}
+			if body != nil {
+				response.Content = map[string]MediaType{"application/json": {Schema: body}}
+			}
+			op.Responses[status] = response
+		}
+
+		doc.Paths[path][method] = op
+	}
+	return doc, nil
+}
+
+// convertPath turns /users/:id into /users/{id} and describes the path
+// parameters. IDs are integers; anything else is a string.
+func convertPath(ginPath string) (string, []Parameter) {
+	var parameters []Parameter
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		name := segment[1:]
+		schema := String()
+		if name == "id" {
+			schema = Integer()
+		}
+		parameters = append(parameters, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+		segments[i] = "{" + name + "}"
+	}
+	return strings.Join(segments, "/"), parameters
+}
+
+// PathFor converts a Gin route path to its key in Document.Paths
+func PathFor(ginPath string) string {
+	path, _ := convertPath(ginPath)
+	return path
+}
@@ -0,0 +1,119 @@
+package openapi
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City string `json:"city" binding:"required"`
+}
+
+type person struct {
+	Name     string     `json:"name" binding:"required,min=2,max=50"`
+	Age      int        `json:"age" binding:"gte=0,lt=150"`
+	Nickname string     `json:"nickname,omitempty"`
+	Units    string     `json:"units" binding:"omitempty,oneof=metric imperial"`
+	Born     *time.Time `json:"born"`
+	Secret   string     `json:"-"`
+	Home     address    `json:"home"`
+	Tags     []string   `json:"tags"`
+	internal int
+}
+
+func TestSchemaFor_Struct(t *testing.T) {
+	g := newGenerator()
+	ref := g.resolve(TypeOf(person{}))
+	if ref.Ref != "#/components/schemas/Person" {
+		t.Fatalf("Expected a reference to Person, got %+v", ref)
+	}
+
+	s := g.components["Person"]
+	if !slices.Equal(s.Required, []string{"name"}) {
+		t.Errorf("Expected only name to be required, got %v", s.Required)
+	}
+	if _, ok := s.Properties["Secret"]; ok {
+		t.Error("Expected json:\"-\" fields to be skipped")
+	}
+	if _, ok := s.Properties["internal"]; ok {
+		t.Error("Expected unexported fields to be skipped")
+	}
+
+	name := s.Properties["name"]
+	if name.MinLength == nil || *name.MinLength != 2 || name.MaxLength == nil || *name.MaxLength != 50 {
+		t.Errorf("Expected name length 2..50, got %+v", name)
+	}
+	age := s.Properties["age"]
+	if age.Minimum == nil || *age.Minimum != 0 || age.Maximum == nil || *age.Maximum != 150 || !age.ExclusiveMaximum {
+		t.Errorf("Expected age in [0, 150), got %+v", age)
+	}
+	if units := s.Properties["units"]; !slices.Equal(units.Enum, []string{"metric", "imperial"}) {
+		t.Errorf("Expected units enum, got %v", units.Enum)
+	}
+	if born := s.Properties["born"]; born.Format != "date-time" || !born.Nullable {
+		t.Errorf("Expected a nullable date-time, got %+v", born)
+	}
+	if home := s.Properties["home"]; home.Ref != "#/components/schemas/Address" {
+		t.Errorf("Expected home to reference Address, got %+v", home)
+	}
+	if tags := s.Properties["tags"]; tags.Type != "array" || tags.Items.Type != "string" {
+		t.Errorf("Expected an array of strings, got %+v", tags)
+	}
+}
+
+func TestConvertPath(t *testing.T) {
+	path, parameters := convertPath("/api/users/:id/files/:name")
+	if path != "/api/users/{id}/files/{name}" {
+		t.Errorf("Expected converted path, got %s", path)
+	}
+	if len(parameters) != 2 {
+		t.Fatalf("Expected 2 path parameters, got %d", len(parameters))
+	}
+	if parameters[0].Schema.Type != "integer" || parameters[1].Schema.Type != "string" {
+		t.Errorf("Expected an integer id and a string name, got %+v", parameters)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	shared := Named("Greeting", Object(map[string]*Schema{"message": String()}, "message"))
+	doc, err := Build(Info{Title: "test", Version: "1"}, []Route{
+		{Method: "GET", Path: "/public", ID: "public", Responses: []Reply{{Status: 200, Body: shared}}},
+		{Method: "DELETE", Path: "/things/:id", ID: "deleteThing", Auth: true, Responses: []Reply{{Status: 200, Body: shared}, {Status: 404}}},
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	op := doc.Paths["/things/{id}"]["delete"]
+	if op == nil {
+		t.Fatal("Expected DELETE /things/{id}")
+	}
+	if len(op.Security) != 1 || op.Responses["401"] == nil {
+		t.Error("Expected authenticated routes to require a bearer token and document 401")
+	}
+	if op.Responses["404"].Content["application/json"].Schema.Ref != errorRef {
+		t.Error("Expected error responses to use the Error schema")
+	}
+	if doc.Paths["/public"]["get"].Security != nil {
+		t.Error("Expected public routes to have no security requirement")
+	}
+	if doc.Components.Schemas["Greeting"] == nil {
+		t.Error("Expected named schemas to become components")
+	}
+
+	if _, err := json.Marshal(doc); err != nil {
+		t.Errorf("Expected the document to encode, got %v", err)
+	}
+}
+
+func TestBuild_RejectsDuplicates(t *testing.T) {
+	_, err := Build(Info{}, []Route{
+		{Method: "GET", Path: "/a/:id", ID: "one"},
+		{Method: "GET", Path: "/a/:id", ID: "two"},
+	})
+	if err == nil {
+		t.Error("Expected duplicate routes to be rejected")
+	}
+}
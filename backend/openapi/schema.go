@@ -0,0 +1,323 @@
+package openapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Schema is the subset of the OpenAPI 3.0 schema object the API needs
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMaximum     bool               `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+
+	// goType is set by TypeOf and replaced by the generated schema when
+	// the document is built
+	goType reflect.Type
+	// name is set by Named; the schema becomes a component of that name
+	name string
+}
+
+func String() *Schema   { return &Schema{Type: "string"} }
+func Integer() *Schema  { return &Schema{Type: "integer"} }
+func Number() *Schema   { return &Schema{Type: "number"} }
+func Boolean() *Schema  { return &Schema{Type: "boolean"} }
+func DateTime() *Schema { return &Schema{Type: "string", Format: "date-time"} }
+func Date() *Schema     { return &Schema{Type: "string", Format: "date"} }
+
+// ArrayOf describes a JSON array of items
+func ArrayOf(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// MapOf describes a JSON object with arbitrary keys
+func MapOf(values *Schema) *Schema {
+	return &Schema{Type: "object", AdditionalProperties: values}
+}
+
+// Object describes a JSON object built by hand, such as a gin.H response
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+// OneOf describes a value matching exactly one of the schemas
+func OneOf(schemas ...*Schema) *Schema {
+	return &Schema{OneOf: schemas}
+}
+
+// Named moves s into components/schemas under name, so generated clients
+// get a type for it. Use it for hand-written schemas shared by routes.
+func Named(name string, s *Schema) *Schema {
+	copied := *s
+	copied.name = name
+	return &copied
+}
+
+// TypeOf describes the JSON encoding of v's type. Named structs end up in
+// components/schemas and are referenced from here.
+func TypeOf(v any) *Schema {
+	return &Schema{goType: reflect.TypeOf(v)}
+}
+
+// Describe returns a copy of s with a description
+func (s *Schema) Describe(description string) *Schema {
+	copied := *s
+	copied.Description = description
+	return &copied
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// generator turns Go types into schemas, collecting named structs as
+// components
+type generator struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	return &generator{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// resolve returns s with every TypeOf and Named placeholder replaced
+func (g *generator) resolve(s *Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+	if s.goType != nil {
+		resolved := g.schemaFor(s.goType)
+		if s.Description != "" {
+			resolved.Description = s.Description
+		}
+		return resolved
+	}
+	if s.name != "" {
+		ref := &Schema{Ref: "#/components/schemas/" + s.name}
+		if _, done := g.components[s.name]; !done {
+			component := *s
+			component.name = ""
+			g.components[s.name] = &component
+			g.components[s.name] = g.resolve(&component)
+		}
+		return ref
+	}
+
+	// resolved into a copy; route schemas are shared between documents
+	copied := *s
+	copied.Items = g.resolve(s.Items)
+	copied.AdditionalProperties = g.resolve(s.AdditionalProperties)
+	if s.Properties != nil {
+		copied.Properties = make(map[string]*Schema, len(s.Properties))
+		for name, property := range s.Properties {
+			copied.Properties[name] = g.resolve(property)
+		}
+	}
+	if s.OneOf != nil {
+		copied.OneOf = make([]*Schema, len(s.OneOf))
+		for i, option := range s.OneOf {
+			copied.OneOf[i] = g.resolve(option)
+		}
+	}
+	return &copied
+}
+
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+
+	var s *Schema
+	switch {
+	case t == timeType:
+		s = DateTime()
+	case t == rawJSONType || (t.Kind() == reflect.Interface):
+		s = &Schema{}
+	case t.Implements(marshalerType):
+		// custom JSON; a string is the usual encoding in this codebase
+		s = String()
+	case t.Kind() == reflect.Struct:
+		s = g.structRef(t)
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		s = &Schema{Type: "string", Format: "byte"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		s = ArrayOf(g.schemaFor(t.Elem()))
+	case t.Kind() == reflect.Map:
+		s = MapOf(g.schemaFor(t.Elem()))
+	case t.Kind() == reflect.Bool:
+		s = Boolean()
+	case t.Kind() == reflect.String:
+		s = String()
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uintptr:
+		s = Integer()
+		if t.Kind() == reflect.Int64 || t.Kind() == reflect.Uint64 {
+			s.Format = "int64"
+		}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		s = Number()
+	default:
+		s = &Schema{}
+	}
+
+	// OpenAPI 3.0 ignores siblings of $ref, so references stay as they are
+	if nullable && s.Ref == "" {
+		s.Nullable = true
+	}
+	return s
+}
+
+// structRef returns a reference to the component for a named struct, or
+// the schema itself for an anonymous one
+func (g *generator) structRef(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return g.structSchema(t)
+	}
+	if name, ok := g.names[t]; ok {
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	name := g.componentName(t)
+	g.names[t] = name
+	// registered before the fields are walked so recursive types terminate
+	g.components[name] = &Schema{}
+	*g.components[name] = *g.structSchema(t)
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName is the exported form of the type name, qualified by its
+// package when another type already took it
+func (g *generator) componentName(t reflect.Type) string {
+	name := exported(t.Name())
+	if _, taken := g.components[name]; taken {
+		name = exported(path.Base(t.PkgPath())) + name
+	}
+	return name
+}
+
+func exported(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(s, t)
+	return s
+}
+
+// addFields follows encoding/json: embedded structs are flattened, "-"
+// and unexported fields are skipped, and omitempty fields are optional
+func (g *generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(s, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schemaFor(field.Type)
+		required := applyBinding(property, field.Tag.Get("binding"))
+		s.Properties[name] = property
+		if required && !strings.Contains(options, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// applyBinding copies the validator rules gin enforces into the schema and
+// reports whether the field is required
+func applyBinding(s *Schema, binding string) bool {
+	required := false
+	if binding == "" {
+		return false
+	}
+	for _, rule := range strings.Split(binding, ",") {
+		name, value, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "oneof":
+			s.Enum = strings.Fields(value)
+		case "gt", "gte", "min", "lt", "lte", "max":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			setBound(s, name, n)
+		}
+	}
+	return required
+}
+
+func setBound(s *Schema, rule string, n float64) {
+	if s.Type == "array" {
+		return
+	}
+	if s.Type == "string" {
+		// lengths, not values
+		length := int(n)
+		switch rule {
+		case "min", "gte":
+			s.MinLength = &length
+		case "max", "lte":
+			s.MaxLength = &length
+		}
+		return
+	}
+	switch rule {
+	case "gt":
+		s.Minimum, s.ExclusiveMinimum = &n, true
+	case "gte", "min":
+		s.Minimum = &n
+	case "lt":
+		s.Maximum, s.ExclusiveMaximum = &n, true
+	case "lte", "max":
+		s.Maximum = &n
+	}
+}
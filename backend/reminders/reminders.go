@@ -0,0 +1,218 @@
+// Package reminders sends users' hydration reminders. A jobs.Scheduler
+// calls Dispatcher.Run every minute or so; each run sends the reminders
+// that have come due since the last one.
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/notify"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// Progress reports today's water intake against the user's goal, as
+// GET /api/water/summary does. handlers.WaterHandler implements it.
+type Progress interface {
+	TodaySummary(userID uint, loc *time.Location, now time.Time) (models.WaterIntakeSummary, error)
+}
+
+// Dispatcher checks reminder rules and sends the reminders that are due
+type Dispatcher struct {
+	rules    store.ReminderStore
+	users    store.UserStore
+	profiles store.ProfileStore
+	water    store.WaterStore
+	progress Progress
+	notifier notify.Notifier
+	now      func() time.Time
+}
+
+func NewDispatcher(rules store.ReminderStore, users store.UserStore, profiles store.ProfileStore, water store.WaterStore, progress Progress, notifier notify.Notifier) *Dispatcher {
+	return &Dispatcher{
+		rules:    rules,
+		users:    users,
+		profiles: profiles,
+		water:    water,
+		progress: progress,
+		notifier: notifier,
+		now:      time.Now,
+	}
+}
+
+// Run checks every enabled rule once. A rule that fails does not stop the
+// others; the errors are returned joined. A reminder is claimed before it
+// is delivered, so it counts as sent even when delivery fails and a broken
+// webhook does not repeat it every run.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	rules, err := d.rules.Enabled()
+	if err != nil {
+		return fmt.Errorf("list reminder rules: %w", err)
+	}
+
+	now := d.now()
+	var errs []error
+	for _, rule := range rules {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := d.check(ctx, rule, now); err != nil {
+			errs = append(errs, fmt.Errorf("reminder rule %d: %w", rule.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// check sends rule's reminder if it is due at now
+func (d *Dispatcher) check(ctx context.Context, rule models.ReminderRule, now time.Time) error {
+	interval := time.Duration(rule.IntervalMinutes) * time.Minute
+	if rule.LastSentAt != nil && now.Sub(*rule.LastSentAt) < interval {
+		return nil
+	}
+
+	user, err := d.users.Get(rule.UserID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+	if user.DisabledAt != nil || user.DeletionRequestedAt != nil {
+		return nil
+	}
+
+	loc, err := d.location(rule.UserID)
+	if err != nil {
+		return err
+	}
+	start, end, err := wakingHours(rule, now, loc)
+	if err != nil {
+		return err
+	}
+	if now.Before(start) || !now.Before(end) {
+		return nil
+	}
+
+	var msg notify.Message
+	switch rule.Kind {
+	case models.ReminderInterval:
+		recent, err := d.water.List(rule.UserID, now.Add(-interval), time.Time{})
+		if err != nil {
+			return fmt.Errorf("list water logs: %w", err)
+		}
+		if len(recent) > 0 {
+			return nil
+		}
+		msg = intervalMessage(rule)
+	case models.ReminderBehindPace:
+		summary, err := d.progress.TodaySummary(rule.UserID, loc, now)
+		if err != nil {
+			return fmt.Errorf("summarize today: %w", err)
+		}
+		target := paceTarget(start, end, now)
+		if summary.Percentage >= target-float64(rule.MarginPercent) {
+			return nil
+		}
+		msg = behindPaceMessage(summary, target)
+	default:
+		return fmt.Errorf("unknown kind %q", rule.Kind)
+	}
+	msg.UserID = rule.UserID
+	msg.Kind = models.NotificationHydrationReminder
+
+	// another instance may have sent it since the rule was read
+	claimed, err := d.rules.Claim(rule.ID, rule.LastSentAt, now)
+	if err != nil {
+		return fmt.Errorf("claim: %w", err)
+	}
+	if !claimed {
+		return nil
+	}
+	if err := d.notifier.Notify(ctx, msg); err != nil {
+		return fmt.Errorf("notify: %w", err)
+	}
+	return nil
+}
+
+// location is the user's time zone, UTC when they have no profile
+func (d *Dispatcher) location(userID uint) (*time.Location, error) {
+	profile, err := d.profiles.Get(userID)
+	if errors.Is(err, store.ErrNotFound) {
+		return time.UTC, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get health profile: %w", err)
+	}
+	loc, err := utils.LoadTimeZone(profile.TimeZone)
+	if err != nil {
+		// saved zones are validated, but the zone database can drop names
+		slog.Warn("unknown profile time zone", "user_id", userID, "time_zone", profile.TimeZone, "error", err)
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// wakingHours are the start and end of rule's window around now in loc.
+// A window whose end is before its start crosses midnight; the one now is
+// in, or the one starting later that day, is returned.
+func wakingHours(rule models.ReminderRule, now time.Time, loc *time.Location) (start, end time.Time, err error) {
+	if start, err = utils.AtTimeOfDay(now, rule.StartTime, loc); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("start time: %w", err)
+	}
+	if end, err = utils.AtTimeOfDay(now, rule.EndTime, loc); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("end time: %w", err)
+	}
+	if end.After(start) {
+		return start, end, nil
+	}
+
+	// noon on the neighbouring days is clear of DST changes
+	local := now.In(loc)
+	if now.Before(end) {
+		yesterday := time.Date(local.Year(), local.Month(), local.Day()-1, 12, 0, 0, 0, loc)
+		start, err = utils.AtTimeOfDay(yesterday, rule.StartTime, loc)
+	} else {
+		tomorrow := time.Date(local.Year(), local.Month(), local.Day()+1, 12, 0, 0, 0, loc)
+		end, err = utils.AtTimeOfDay(tomorrow, rule.EndTime, loc)
+	}
+	return start, end, err
+}
+
+// paceTarget is the percentage of the day's goal drunk by now when
+// drinking evenly from start to end
+func paceTarget(start, end, now time.Time) float64 {
+	if !end.After(start) {
+		return 0
+	}
+	elapsed := float64(now.Sub(start)) / float64(end.Sub(start))
+	return utils.RoundToTwo(min(max(elapsed, 0), 1) * 100)
+}
+
+func intervalMessage(rule models.ReminderRule) notify.Message {
+	return notify.Message{
+		Title: "Time for some water",
+		Body:  fmt.Sprintf("You haven't logged a drink in %s.", describeMinutes(rule.IntervalMinutes)),
+	}
+}
+
+func behindPaceMessage(summary models.WaterIntakeSummary, target float64) notify.Message {
+	return notify.Message{
+		Title: "You're behind on water today",
+		Body: fmt.Sprintf("You've had %d of your %d ml (%.0f%%). Drinking evenly through the day, you'd be at %.0f%% by now.",
+			summary.HydrationML, summary.GoalML, summary.Percentage, target),
+	}
+}
+
+// describeMinutes writes a duration the way people say it, e.g. 2 hours
+func describeMinutes(minutes int) string {
+	switch {
+	case minutes == 60:
+		return "an hour"
+	case minutes%60 == 0:
+		return fmt.Sprintf("%d hours", minutes/60)
+	default:
+		return fmt.Sprintf("%d minutes", minutes)
+	}
+}
@@ -0,0 +1,221 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/notify"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+// fakeProgress reports the same percentage of a 2000ml goal for everyone
+type fakeProgress struct {
+	percentage float64
+}
+
+func (p *fakeProgress) TodaySummary(userID uint, loc *time.Location, now time.Time) (models.WaterIntakeSummary, error) {
+	hydration := int(p.percentage * 20)
+	return models.WaterIntakeSummary{GoalML: 2000, HydrationML: hydration, Percentage: p.percentage}, nil
+}
+
+type fixture struct {
+	stores     store.Stores
+	progress   *fakeProgress
+	recorder   *notify.Recorder
+	dispatcher *Dispatcher
+	now        time.Time
+}
+
+// newFixture has user 1 in Los Angeles and a dispatcher whose clock reads
+// f.now
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{stores: store.NewMemoryStores(), progress: &fakeProgress{}, recorder: notify.NewRecorder()}
+	f.stores.Users.Create(&models.User{ID: 1, Username: "alice", PasswordHash: "hash"})
+	f.stores.Profiles.Save(&models.HealthProfile{UserID: 1, TimeZone: "America/Los_Angeles"})
+	f.dispatcher = NewDispatcher(f.stores.Reminders, f.stores.Users, f.stores.Profiles, f.stores.Water, f.progress, f.recorder)
+	f.dispatcher.now = func() time.Time { return f.now }
+	return f
+}
+
+func (f *fixture) addRule(t *testing.T, rule models.ReminderRule) models.ReminderRule {
+	t.Helper()
+	rule.UserID = 1
+	rule.Enabled = true
+	if err := f.stores.Reminders.Create(&rule); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return rule
+}
+
+// runAt runs the dispatcher at a time in Los Angeles and returns the
+// number of messages sent so far
+func (f *fixture) runAt(t *testing.T, local string) int {
+	t.Helper()
+	la, _ := time.LoadLocation("America/Los_Angeles")
+	now, err := time.ParseInLocation("2006-01-02 15:04", local, la)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.now = now
+	if err := f.dispatcher.Run(context.Background()); err != nil {
+		t.Fatalf("Run at %s: %v", local, err)
+	}
+	return len(f.recorder.Sent())
+}
+
+func TestRun_Interval(t *testing.T) {
+	f := newFixture(t)
+	rule := f.addRule(t, models.ReminderRule{Kind: models.ReminderInterval, StartTime: "08:00", EndTime: "22:00", IntervalMinutes: 90})
+
+	if sent := f.runAt(t, "2026-03-10 07:59"); sent != 0 {
+		t.Fatalf("Expected no reminder before waking hours, got %d", sent)
+	}
+	if sent := f.runAt(t, "2026-03-10 08:00"); sent != 1 {
+		t.Fatalf("Expected a reminder at the start of waking hours, got %d", sent)
+	}
+	msg := f.recorder.Sent()[0]
+	if msg.UserID != 1 || msg.Kind != models.NotificationHydrationReminder || !strings.Contains(msg.Body, "90 minutes") {
+		t.Errorf("Unexpected message %+v", msg)
+	}
+	if saved, _ := f.stores.Reminders.Get(1, rule.ID); saved.LastSentAt == nil || !saved.LastSentAt.Equal(f.now) {
+		t.Errorf("Expected the rule to be marked sent, got %+v", saved.LastSentAt)
+	}
+
+	if sent := f.runAt(t, "2026-03-10 09:00"); sent != 1 {
+		t.Errorf("Expected no reminder within the interval, got %d", sent)
+	}
+
+	// a drink resets the interval
+	drink := models.WaterIntake{UserID: 1, AmountML: 250, HydrationML: 250, LoggedAt: f.now}
+	f.stores.Water.Create(&drink)
+	if sent := f.runAt(t, "2026-03-10 10:00"); sent != 1 {
+		t.Errorf("Expected no reminder within the interval of a drink, got %d", sent)
+	}
+	if sent := f.runAt(t, "2026-03-10 10:31"); sent != 2 {
+		t.Errorf("Expected a reminder over 90 minutes after the drink, got %d", sent)
+	}
+
+	if sent := f.runAt(t, "2026-03-10 22:00"); sent != 2 {
+		t.Errorf("Expected no reminder after waking hours, got %d", sent)
+	}
+}
+
+func TestRun_IntervalPastMidnight(t *testing.T) {
+	f := newFixture(t)
+	f.addRule(t, models.ReminderRule{Kind: models.ReminderInterval, StartTime: "22:00", EndTime: "06:00", IntervalMinutes: 60})
+
+	if sent := f.runAt(t, "2026-03-10 21:59"); sent != 0 {
+		t.Fatalf("Expected no reminder before the hours start, got %d", sent)
+	}
+	if sent := f.runAt(t, "2026-03-10 23:00"); sent != 1 {
+		t.Fatalf("Expected a reminder before midnight, got %d", sent)
+	}
+	if sent := f.runAt(t, "2026-03-11 03:00"); sent != 2 {
+		t.Fatalf("Expected a reminder after midnight, got %d", sent)
+	}
+	if sent := f.runAt(t, "2026-03-11 06:00"); sent != 2 {
+		t.Errorf("Expected no reminder once the hours end, got %d", sent)
+	}
+	if sent := f.runAt(t, "2026-03-11 12:00"); sent != 2 {
+		t.Errorf("Expected no reminder during the day, got %d", sent)
+	}
+}
+
+func TestRun_ClaimedElsewhereIsNotSent(t *testing.T) {
+	f := newFixture(t)
+	rule := f.addRule(t, models.ReminderRule{Kind: models.ReminderInterval, StartTime: "08:00", EndTime: "22:00", IntervalMinutes: 60})
+
+	// another instance sends it after this one read the rules
+	la, _ := time.LoadLocation("America/Los_Angeles")
+	f.now = time.Date(2026, 3, 10, 12, 0, 0, 0, la)
+	if claimed, _ := f.stores.Reminders.Claim(rule.ID, nil, f.now); !claimed {
+		t.Fatal("Expected the other instance's claim to win")
+	}
+	if err := f.dispatcher.check(context.Background(), rule, f.now); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	if sent := len(f.recorder.Sent()); sent != 0 {
+		t.Errorf("Expected a reminder claimed elsewhere not to be sent again, got %d", sent)
+	}
+}
+
+func TestRun_BehindPace(t *testing.T) {
+	f := newFixture(t)
+	f.addRule(t, models.ReminderRule{Kind: models.ReminderBehindPace, StartTime: "08:00", EndTime: "20:00", IntervalMinutes: 60, MarginPercent: 10})
+
+	// halfway through the day the pace is 50%, so 40% is within the margin
+	f.progress.percentage = 40
+	if sent := f.runAt(t, "2026-03-10 14:00"); sent != 0 {
+		t.Fatalf("Expected no reminder within the margin, got %d", sent)
+	}
+
+	f.progress.percentage = 30
+	if sent := f.runAt(t, "2026-03-10 14:00"); sent != 1 {
+		t.Fatalf("Expected a reminder when behind pace, got %d", sent)
+	}
+	if body := f.recorder.Sent()[0].Body; !strings.Contains(body, "600 of your 2000 ml (30%)") || !strings.Contains(body, "50%") {
+		t.Errorf("Unexpected message %q", body)
+	}
+
+	if sent := f.runAt(t, "2026-03-10 14:30"); sent != 1 {
+		t.Errorf("Expected at most one reminder an hour, got %d", sent)
+	}
+	if sent := f.runAt(t, "2026-03-10 15:00"); sent != 2 {
+		t.Errorf("Expected another reminder after an hour, got %d", sent)
+	}
+}
+
+func TestRun_SkipsDisabledUsersAndRules(t *testing.T) {
+	f := newFixture(t)
+	f.addRule(t, models.ReminderRule{Kind: models.ReminderInterval, StartTime: "08:00", EndTime: "22:00", IntervalMinutes: 60})
+	off := models.ReminderRule{UserID: 1, Kind: models.ReminderInterval, StartTime: "08:00", EndTime: "22:00", IntervalMinutes: 30}
+	f.stores.Reminders.Create(&off)
+
+	disabledAt := time.Now()
+	disabled := models.User{ID: 2, Username: "bob", PasswordHash: "hash", DisabledAt: &disabledAt}
+	f.stores.Users.Create(&disabled)
+	f.stores.Reminders.Create(&models.ReminderRule{UserID: 2, Kind: models.ReminderInterval, Enabled: true, StartTime: "00:00", EndTime: "23:59", IntervalMinutes: 30})
+
+	if sent := f.runAt(t, "2026-03-10 12:00"); sent != 1 || f.recorder.Sent()[0].UserID != 1 {
+		t.Errorf("Expected only user 1's enabled rule to send, got %+v", f.recorder.Sent())
+	}
+}
+
+func TestRun_DeliveryFailureIsNotRetried(t *testing.T) {
+	f := newFixture(t)
+	rule := f.addRule(t, models.ReminderRule{Kind: models.ReminderInterval, StartTime: "08:00", EndTime: "22:00", IntervalMinutes: 60})
+	f.recorder.Err = errors.New("webhook down")
+
+	la, _ := time.LoadLocation("America/Los_Angeles")
+	f.now = time.Date(2026, 3, 10, 12, 0, 0, 0, la)
+	if err := f.dispatcher.Run(context.Background()); !errors.Is(err, f.recorder.Err) {
+		t.Errorf("Expected the delivery error, got %v", err)
+	}
+	if saved, _ := f.stores.Reminders.Get(1, rule.ID); saved.LastSentAt == nil {
+		t.Error("Expected the rule to be marked sent despite the failure")
+	}
+}
+
+func TestPaceTarget(t *testing.T) {
+	start := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	end := start.Add(12 * time.Hour)
+	tests := []struct {
+		now      time.Time
+		expected float64
+	}{
+		{start, 0},
+		{start.Add(3 * time.Hour), 25},
+		{start.Add(8 * time.Hour), 66.67},
+		{end.Add(time.Hour), 100},
+	}
+	for _, tt := range tests {
+		if got := paceTarget(start, end, tt.now); got != tt.expected {
+			t.Errorf("paceTarget at %v = %v; want %v", tt.now, got, tt.expected)
+		}
+	}
+}
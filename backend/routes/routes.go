@@ -1,52 +1,182 @@
 package routes
 
 import (
-	"gorm.io/gorm"
-
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/handlers"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/mailer"
 	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(router *gin.Engine, db *gorm.DB) {
+// SetupRoutes registers every endpoint on handlers backed by stores.
+// AuthMiddleware checks the store given to middleware.SetRevocationStore,
+// which should be stores.Revocations.
+func SetupRoutes(router *gin.Engine, stores store.Stores, cfg *config.Config, mail mailer.Mailer) {
+	auth := handlers.NewAuthHandler(stores.Users, stores.Profiles, stores.RefreshTokens, stores.TwoFactors, stores.Lockouts, stores.Revocations)
+	passwords := handlers.NewPasswordHandler(auth, stores.PasswordResets, mail, cfg.Auth)
+	accounts := handlers.NewAccountHandler(auth, cfg.Account)
+	twoFactor := handlers.NewTwoFactorHandler(auth, cfg.Auth.TOTPIssuer)
+	tokens := handlers.NewPersonalTokenHandler(stores.PersonalTokens)
+	admins := handlers.NewAdminHandler(auth, passwords, stores.Stats)
+	profiles := handlers.NewProfileHandler(stores.Profiles, stores.Users)
+	water := handlers.NewWaterHandler(stores.Water, stores.Profiles, stores.HydrationGoals, stores.Exercises, stores.Beverages)
+	weights := handlers.NewWeightHandler(stores.Weights, stores.Profiles)
+	exercises := handlers.NewExerciseHandler(stores.Exercises)
+	beverages := handlers.NewBeverageHandler(stores.Beverages)
+	reminders := handlers.NewReminderHandler(stores.Reminders)
+	notifications := handlers.NewNotificationHandler(stores.Notifications)
+
+	// unknown paths and methods get the error envelope too
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(handlers.NoRoute)
+	router.NoMethod(handlers.NoMethod)
+
+	// public verification keys for other services
+	router.GET("/.well-known/jwks.json", handlers.JWKS)
+
+	// API description and a browser for it
+	router.GET(handlers.OpenAPIPath, handlers.OpenAPI)
+	router.GET(handlers.DocsPath+"/*file", handlers.SwaggerUI)
+
 	api := router.Group("/api")
 	{
-		//public endpoints
-		api.POST("/auth/register", func(c *gin.Context) {
-			handlers.Register(c, db)
-		})
-		api.POST("/auth/login", func(c *gin.Context) {
-			handlers.Login(c, db)
-		})
+		// public endpoints, limited per client IP
+		public := api.Group("")
+		public.Use(rateLimit(cfg.RateLimit, "auth", cfg.RateLimit.Auth, middleware.ByClientIP))
+		{
+			public.POST("/auth/register", auth.Register)
+			public.POST("/auth/login", auth.Login)
+			public.POST("/auth/login/2fa", auth.LoginTwoFactor)
+			public.POST("/auth/refresh", auth.Refresh)
+			public.POST("/auth/password/forgot", passwords.ForgotPassword)
+			public.POST("/auth/password/reset", passwords.ResetPassword)
+			public.POST("/account/restore", accounts.RestoreAccount)
+		}
 
 		// protected endpoints
 		protected := api.Group("")
-		protected.Use(middleware.AuthMiddleware())
+		protected.Use(middleware.AuthMiddleware(), rateLimit(cfg.RateLimit, "api", cfg.RateLimit.API, middleware.ByUser))
+
+		// account and security settings need a login session; personal
+		// access tokens cannot manage themselves or the account
+		account := protected.Group("")
+		account.Use(middleware.SessionOnly())
+		{
+			// sessions
+			account.POST("/auth/logout", auth.Logout)
+			account.POST("/auth/logout-all", auth.LogoutAll)
+			account.PUT("/auth/password", passwords.ChangePassword)
+
+			// two-factor authentication
+			account.POST("/auth/2fa/setup", twoFactor.SetupTwoFactor)
+			account.POST("/auth/2fa/enable", twoFactor.EnableTwoFactor)
+			account.POST("/auth/2fa/disable", twoFactor.DisableTwoFactor)
+			account.POST("/auth/2fa/recovery-codes", twoFactor.RegenerateRecoveryCodes)
+
+			// personal access tokens
+			account.POST("/tokens", tokens.CreatePersonalToken)
+			account.GET("/tokens", tokens.ListPersonalTokens)
+			account.DELETE("/tokens/:id", tokens.RevokePersonalToken)
+
+			// account
+			account.DELETE("/account", accounts.DeleteAccount)
+		}
+
+		// user management for admins
+		admin := protected.Group("/admin")
+		admin.Use(middleware.SessionOnly(), middleware.RequireRole(models.RoleAdmin))
+		{
+			admin.GET("/users", admins.ListUsers)
+			admin.GET("/users/:id", admins.GetUser)
+			admin.POST("/users/:id/disable", admins.DisableUser)
+			admin.POST("/users/:id/enable", admins.EnableUser)
+			admin.POST("/users/:id/force-password-reset", admins.ForcePasswordReset)
+			admin.PUT("/users/:id/role", admins.SetUserRole)
+			admin.GET("/lockouts", admins.ListLockouts)
+			admin.GET("/stats", admins.GetUsageStats)
+		}
+
+		// health data; personal access tokens need the matching
+		// <resource>:read or <resource>:write scope
+		profile := protected.Group("")
+		profile.Use(middleware.RequireScope("profile"))
 		{
 			// health Profile CRUD
-			protected.GET("/profile", handlers.GetProfile)
-			protected.PUT("/profile", handlers.UpdateProfile)
+			profile.GET("/profile", profiles.GetProfile)
+			profile.PUT("/profile", profiles.UpdateProfile)
 
 			// stats calculation
-			protected.GET("/profile/stats", handlers.GetStats)
+			profile.GET("/profile/stats", profiles.GetStats)
+		}
+
+		// calorie goal calculation only reads the profile
+		protected.POST("/caloriegoal", middleware.RequireExactScope("profile:read"), profiles.CalculateCalorieGoal)
 
+		waterGroup := protected.Group("")
+		waterGroup.Use(middleware.RequireScope("water"))
+		{
 			// water intake
-			protected.POST("/water", handlers.LogWaterIntake)
-			protected.GET("/water", handlers.GetWaterIntakeLogs)
-			protected.GET("/water/summary", handlers.GetDailySummary)
-			protected.DELETE("/water/:id", handlers.DeleteWaterLog)
+			waterGroup.POST("/water", water.LogWaterIntake)
+			waterGroup.GET("/water", water.GetWaterIntakeLogs)
+			waterGroup.GET("/water/summary", water.GetDailySummary)
+			waterGroup.GET("/water/goal", water.GetWaterGoal)
+			waterGroup.PUT("/water/goal", water.SetWaterGoal)
+			waterGroup.PUT("/water/:id", water.UpdateWaterLog)
+			waterGroup.PATCH("/water/:id", water.PatchWaterLog)
+			waterGroup.GET("/water/:id/history", water.GetWaterLogHistory)
+			waterGroup.DELETE("/water/:id", water.DeleteWaterLog)
 
-			// weight log CRUD
-			protected.PUT("/weight/add", handlers.AddWeightLog)
-			protected.GET("/weight/logs", handlers.GetWeightLogs)
-			protected.POST("/weight/modify", handlers.ModifyLastWeight)
+			// beverage catalog
+			waterGroup.GET("/water/beverages", beverages.ListBeverages)
+			waterGroup.POST("/water/beverages", beverages.CreateBeverage)
+			waterGroup.DELETE("/water/beverages/:id", beverages.DeleteBeverage)
+
+			// hydration reminder rules
+			waterGroup.GET("/water/reminders", reminders.ListReminders)
+			waterGroup.POST("/water/reminders", reminders.CreateReminder)
+			waterGroup.PUT("/water/reminders/:id", reminders.UpdateReminder)
+			waterGroup.DELETE("/water/reminders/:id", reminders.DeleteReminder)
+		}
+
+		inbox := protected.Group("")
+		inbox.Use(middleware.RequireScope("notifications"))
+		{
+			// in-app notifications
+			inbox.GET("/notifications", notifications.ListNotifications)
+			inbox.POST("/notifications/:id/read", notifications.MarkNotificationRead)
+		}
 
-			// calorie goal calculation
-			protected.POST("/caloriegoal", handlers.CalculateCalorieGoal)
+		weight := protected.Group("")
+		weight.Use(middleware.RequireScope("weight"))
+		{
+			// weight log CRUD
+			weight.PUT("/weight/add", weights.AddWeightLog)
+			weight.GET("/weight/logs", weights.GetWeightLogs)
+			weight.POST("/weight/modify", weights.ModifyLastWeight)
+		}
 
+		exercise := protected.Group("")
+		exercise.Use(middleware.RequireScope("exercise"))
+		{
 			// exercise log CRUD
-			protected.POST("/exercise/add", handlers.LogExercise)
-			protected.GET("/exercise/logs", handlers.GetExerciseLogs)
+			exercise.POST("/exercise/add", exercises.LogExercise)
+			exercise.GET("/exercise/logs", exercises.GetExerciseLogs)
 		}
 	}
 }
+
+// rateLimit builds the limiter for one route group, or a no-op when rate
+// limiting is turned off
+func rateLimit(cfg config.RateLimitConfig, name string, policy config.RateLimitPolicy, key middleware.RateLimitKey) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return middleware.RateLimit(utils.RateLimitPolicy{
+		Name:     name,
+		Requests: policy.Requests,
+		Period:   policy.Period.Std(),
+	}, key)
+}
@@ -0,0 +1,98 @@
+package routes
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/apierror"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/handlers"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/mailer"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/middleware"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/openapi"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+)
+
+// TestOpenAPI_CoversEveryRoute fails when a route is added without
+// describing it in handlers.APIRoutes, or the other way round
+func TestOpenAPI_CoversEveryRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	SetupRoutes(router, store.NewMemoryStores(), config.Default(), mailer.NewLogMailer(io.Discard))
+
+	doc, err := handlers.OpenAPIDocument()
+	if err != nil {
+		t.Fatalf("Failed to build the OpenAPI document: %v", err)
+	}
+
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		// the Swagger UI files are not part of the API
+		if strings.HasPrefix(route.Path, handlers.DocsPath+"/") {
+			continue
+		}
+		path := openapi.PathFor(route.Path)
+		method := strings.ToLower(route.Method)
+		registered[method+" "+path] = true
+		if doc.Paths[path][method] == nil {
+			t.Errorf("%s %s is registered but missing from the OpenAPI document", route.Method, route.Path)
+		}
+	}
+
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			if !registered[method+" "+path] {
+				t.Errorf("%s %s is in the OpenAPI document but not registered", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func errorResponse(t *testing.T, method, path string) (*httptest.ResponseRecorder, apierror.Error) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	SetupRoutes(router, store.NewMemoryStores(), config.Default(), mailer.NewLogMailer(io.Discard))
+
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response apierror.Error
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Expected a JSON error body, got %q", w.Body.String())
+	}
+	return w, response
+}
+
+func TestNoRoute_ReturnsErrorEnvelope(t *testing.T) {
+	w, response := errorResponse(t, "GET", "/api/no-such-endpoint")
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+	if response.Code != apierror.NotFound || response.Message == "" || response.RequestID != "req-1" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+}
+
+func TestNoMethod_ReturnsErrorEnvelope(t *testing.T) {
+	w, response := errorResponse(t, "DELETE", "/api/auth/login")
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+	if response.Code != apierror.MethodNotAllowed || response.Message == "" || response.RequestID != "req-1" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+	if allow := w.Header().Get("Allow"); !strings.Contains(allow, "POST") {
+		t.Errorf("Expected POST in the Allow header, got %q", allow)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+)
+
+// newHTTPServer wraps the router with the configured connection timeouts
+func newHTTPServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout.Std(),
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout.Std(),
+		WriteTimeout:      cfg.WriteTimeout.Std(),
+		IdleTimeout:       cfg.IdleTimeout.Std(),
+	}
+}
+
+// serve runs srv on listener until ctx is cancelled. It then calls drain,
+// keeps serving for drainDelay so load balancers see readiness fail, stops
+// accepting connections and gives in-flight requests up to shutdownTimeout
+// to finish before closing them.
+func serve(ctx context.Context, srv *http.Server, listener net.Listener, drainDelay, shutdownTimeout time.Duration, drain func()) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("draining, still accepting connections", "delay", drainDelay.String())
+	drain()
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server stopped: %w", err)
+	case <-time.After(drainDelay):
+	}
+
+	slog.Info("shutting down, waiting for requests to finish", "timeout", shutdownTimeout.String())
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+		return fmt.Errorf("graceful shutdown: %w", err)
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	slog.Info("server stopped")
+	return nil
+}
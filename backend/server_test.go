@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/handlers"
+)
+
+func TestServe_DrainsInFlightRequests(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		io.WriteString(w, "done")
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var drained atomic.Bool
+	result := make(chan error, 1)
+	go func() {
+		result <- serve(ctx, srv, listener, 0, 5*time.Second, func() { drained.Store(true) })
+	}()
+
+	response := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			response <- "error: " + err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		response <- string(body)
+	}()
+
+	<-started
+	cancel()
+
+	if got := <-response; got != "done" {
+		t.Errorf("Expected the in-flight request to finish, got %q", got)
+	}
+	if err := <-result; err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+	if !drained.Load() {
+		t.Error("Expected drain to be called before shutting down")
+	}
+
+	if _, err := http.Get("http://" + listener.Addr().String()); err == nil {
+		t.Error("Expected new connections to be refused after shutdown")
+	}
+}
+
+func TestServe_GivesUpAfterTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- serve(ctx, srv, listener, 0, 50*time.Millisecond, func() {})
+	}()
+	go http.Get("http://" + listener.Addr().String())
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-result:
+		if err == nil {
+			t.Error("Expected an error when requests outlive the shutdown timeout")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("serve did not return after the shutdown timeout")
+	}
+}
+
+func TestServe_FailsReadinessBeforeClosingListener(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	health := handlers.NewHealthHandler(func(ctx context.Context) error { return nil })
+	router := gin.New()
+	router.GET("/health/ready", health.Ready)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	drained := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		result <- serve(ctx, &http.Server{Handler: router}, listener, 500*time.Millisecond, 5*time.Second, func() {
+			health.Drain()
+			close(drained)
+		})
+	}()
+
+	// every request opens a new connection, so a response proves the
+	// listener is still accepting
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	ready := "http://" + listener.Addr().String() + "/health/ready"
+	readiness := func() (int, error) {
+		resp, err := client.Get(ready)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	if code, err := readiness(); err != nil || code != http.StatusOK {
+		t.Fatalf("Expected ready before shutdown, got %d %v", code, err)
+	}
+
+	cancel()
+	<-drained
+
+	if code, err := readiness(); err != nil || code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 on a new connection during the drain delay, got %d %v", code, err)
+	}
+
+	if err := <-result; err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+	if _, err := readiness(); err == nil {
+		t.Error("Expected new connections to be refused after shutdown")
+	}
+}
@@ -0,0 +1,23 @@
+package store
+
+import "github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+
+// BuiltinBeverages is the catalog every user starts with. The database gets
+// it from a migration, which keeps its own copy; change both together.
+// Values are approximate, after the beverage hydration index.
+func BuiltinBeverages() []models.Beverage {
+	return []models.Beverage{
+		{Name: "Water", HydrationFactor: 1},
+		{Name: "Sparkling water", HydrationFactor: 1},
+		{Name: "Coffee", HydrationFactor: 0.9, CaffeineMGPer100ML: 40, KcalPer100ML: 2},
+		{Name: "Espresso", HydrationFactor: 0.8, CaffeineMGPer100ML: 212, KcalPer100ML: 9},
+		{Name: "Tea", HydrationFactor: 0.95, CaffeineMGPer100ML: 20, KcalPer100ML: 1},
+		{Name: "Milk", HydrationFactor: 1.5, KcalPer100ML: 61},
+		{Name: "Orange juice", HydrationFactor: 1.1, KcalPer100ML: 45},
+		{Name: "Sports drink", HydrationFactor: 1.1, KcalPer100ML: 26},
+		{Name: "Cola", HydrationFactor: 0.9, CaffeineMGPer100ML: 10, KcalPer100ML: 42},
+		{Name: "Energy drink", HydrationFactor: 0.9, CaffeineMGPer100ML: 32, KcalPer100ML: 45},
+		{Name: "Beer", HydrationFactor: 0.8, KcalPer100ML: 43},
+		{Name: "Wine", HydrationFactor: 0.3, KcalPer100ML: 83},
+	}
+}
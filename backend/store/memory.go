@@ -0,0 +1,1336 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// NewMemoryStores returns empty in-memory stores. They keep records in
+// process memory, copy them in and out like a database would and are safe
+// for concurrent use.
+func NewMemoryStores() Stores {
+	users := NewMemoryUserStore()
+	refreshTokens := NewMemoryRefreshTokenStore()
+	twoFactors := NewMemoryTwoFactorStore()
+	passwordResets := NewMemoryPasswordResetStore()
+	personalTokens := NewMemoryPersonalTokenStore(users)
+	profiles := NewMemoryProfileStore()
+	water := NewMemoryWaterStore()
+	weights := NewMemoryWeightStore()
+	exercises := NewMemoryExerciseStore()
+	hydrationGoals := NewMemoryHydrationGoalStore()
+	beverages := NewMemoryBeverageStore()
+	reminders := NewMemoryReminderStore()
+	notifications := NewMemoryNotificationStore()
+
+	// deleting a user clears their records, like the database does
+	users.records = []userRecords{
+		refreshTokens, twoFactors, passwordResets, personalTokens, profiles, water,
+		weights, exercises, hydrationGoals, beverages, reminders, notifications,
+	}
+
+	return Stores{
+		Users:          users,
+		RefreshTokens:  refreshTokens,
+		TwoFactors:     twoFactors,
+		Lockouts:       NewMemoryLockoutStore(),
+		PasswordResets: passwordResets,
+		PersonalTokens: personalTokens,
+		Revocations:    utils.NewMemoryRevocationStore(),
+		Profiles:       profiles,
+		Water:          water,
+		Weights:        weights,
+		Exercises:      exercises,
+
+		HydrationGoals: hydrationGoals,
+		Beverages:      beverages,
+		Reminders:      reminders,
+		Notifications:  notifications,
+		Stats: &MemoryStatsStore{
+			users:         users,
+			refreshTokens: refreshTokens,
+			twoFactors:    twoFactors,
+			water:         water,
+			weights:       weights,
+			exercises:     exercises,
+		},
+	}
+}
+
+// userRecords is a store of records that belong to a user
+type userRecords interface {
+	// deleteUser removes every record of the user
+	deleteUser(userID uint)
+}
+
+type MemoryUserStore struct {
+	mu     sync.Mutex
+	users  map[uint]models.User
+	nextID uint
+	// cleared by Delete; set by NewMemoryStores
+	records []userRecords
+}
+
+func NewMemoryUserStore() *MemoryUserStore {
+	return &MemoryUserStore{users: make(map[uint]models.User), nextID: 1}
+}
+
+func (s *MemoryUserStore) Get(id uint) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[id]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	return user, nil
+}
+
+func (s *MemoryUserStore) GetByUsername(username string) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return models.User{}, ErrNotFound
+}
+
+func (s *MemoryUserStore) GetByEmail(email string) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		if user.Email != nil && *user.Email == email {
+			return user, nil
+		}
+	}
+	return models.User{}, ErrNotFound
+}
+
+func (s *MemoryUserStore) Create(user *models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.users {
+		if existing.Username == user.Username {
+			return fmt.Errorf("username %q is taken", user.Username)
+		}
+		if existing.Email != nil && user.Email != nil && *existing.Email == *user.Email {
+			return fmt.Errorf("email %q is taken", *user.Email)
+		}
+	}
+	if user.ID == 0 {
+		user.ID = s.nextID
+	} else if _, ok := s.users[user.ID]; ok {
+		return fmt.Errorf("user %d already exists", user.ID)
+	}
+	if user.ID >= s.nextID {
+		s.nextID = user.ID + 1
+	}
+	if user.Role == "" {
+		user.Role = models.RoleUser
+	}
+	user.CreatedAt = time.Now()
+	s.users[user.ID] = *user
+	return nil
+}
+
+func (s *MemoryUserStore) Search(filter UserFilter, offset, limit int) ([]models.User, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	query := strings.ToLower(filter.Query)
+	users := []models.User{}
+	for _, user := range s.users {
+		if query != "" && !strings.Contains(strings.ToLower(user.Username), query) &&
+			(user.Email == nil || !strings.Contains(strings.ToLower(*user.Email), query)) {
+			continue
+		}
+		if filter.Role != "" && user.Role != filter.Role {
+			continue
+		}
+		if filter.Disabled != nil && (user.DisabledAt != nil) != *filter.Disabled {
+			continue
+		}
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	total := int64(len(users))
+	if offset > len(users) {
+		offset = len(users)
+	}
+	users = users[offset:]
+	if len(users) > limit {
+		users = users[:limit]
+	}
+	return users, total, nil
+}
+
+func (s *MemoryUserStore) SetPassword(id uint, hash string) error {
+	return s.update(id, func(user *models.User) {
+		user.PasswordHash = hash
+		user.PasswordResetRequired = false
+	})
+}
+
+func (s *MemoryUserStore) RequirePasswordReset(id uint) error {
+	return s.update(id, func(user *models.User) { user.PasswordResetRequired = true })
+}
+
+func (s *MemoryUserStore) SetRole(id uint, role string) error {
+	return s.update(id, func(user *models.User) { user.Role = role })
+}
+
+func (s *MemoryUserStore) SetDisabled(id uint, at *time.Time) error {
+	return s.update(id, func(user *models.User) { user.DisabledAt = copyTime(at) })
+}
+
+func (s *MemoryUserStore) SetDeletionRequested(id uint, at *time.Time) error {
+	return s.update(id, func(user *models.User) { user.DeletionRequestedAt = copyTime(at) })
+}
+
+// update changes one user
+func (s *MemoryUserStore) update(id uint, change func(user *models.User)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	change(&user)
+	s.users[id] = user
+	return nil
+}
+
+func (s *MemoryUserStore) Delete(id uint) error {
+	s.mu.Lock()
+	if _, ok := s.users[id]; !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	delete(s.users, id)
+	s.mu.Unlock()
+
+	for _, records := range s.records {
+		records.deleteUser(id)
+	}
+	return nil
+}
+
+type MemoryRefreshTokenStore struct {
+	mu     sync.Mutex
+	tokens map[uint]models.RefreshToken
+	nextID uint
+}
+
+func NewMemoryRefreshTokenStore() *MemoryRefreshTokenStore {
+	return &MemoryRefreshTokenStore{tokens: make(map[uint]models.RefreshToken), nextID: 1}
+}
+
+func (s *MemoryRefreshTokenStore) Create(token *models.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.create(token)
+}
+
+// create stores a token; the caller holds the lock
+func (s *MemoryRefreshTokenStore) create(token *models.RefreshToken) error {
+	for _, existing := range s.tokens {
+		if existing.TokenHash == token.TokenHash {
+			return fmt.Errorf("refresh token hash %q already exists", token.TokenHash)
+		}
+	}
+	token.ID = s.nextID
+	s.nextID++
+	token.CreatedAt = time.Now()
+	s.tokens[token.ID] = *token
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) GetByHash(hash string) (models.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, token := range s.tokens {
+		if token.TokenHash == hash {
+			return token, nil
+		}
+	}
+	return models.RefreshToken{}, ErrNotFound
+}
+
+func (s *MemoryRefreshTokenStore) Rotate(usedID uint, next *models.RefreshToken) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	used, ok := s.tokens[usedID]
+	if !ok || used.UsedAt != nil {
+		return false, nil
+	}
+	if err := s.create(next); err != nil {
+		return false, err
+	}
+	now := time.Now()
+	used.UsedAt = &now
+	s.tokens[usedID] = used
+	return true, nil
+}
+
+func (s *MemoryRefreshTokenStore) RevokeFamily(familyID string) error {
+	return s.revoke(func(token models.RefreshToken) bool { return token.FamilyID == familyID })
+}
+
+func (s *MemoryRefreshTokenStore) RevokeUser(userID uint) error {
+	return s.revoke(func(token models.RefreshToken) bool { return token.UserID == userID })
+}
+
+// revoke revokes the matching tokens that are not revoked yet
+func (s *MemoryRefreshTokenStore) revoke(match func(token models.RefreshToken) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id, token := range s.tokens {
+		if match(token) && token.RevokedAt == nil {
+			token.RevokedAt = &now
+			s.tokens[id] = token
+		}
+	}
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) deleteUser(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, token := range s.tokens {
+		if token.UserID == userID {
+			delete(s.tokens, id)
+		}
+	}
+}
+
+type MemoryTwoFactorStore struct {
+	mu            sync.Mutex
+	twoFactors    map[uint]models.TwoFactor // by user ID
+	recoveryCodes []models.RecoveryCode
+	nextCodeID    uint
+}
+
+func NewMemoryTwoFactorStore() *MemoryTwoFactorStore {
+	return &MemoryTwoFactorStore{twoFactors: make(map[uint]models.TwoFactor), nextCodeID: 1}
+}
+
+func (s *MemoryTwoFactorStore) Get(userID uint) (models.TwoFactor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	twoFactor, ok := s.twoFactors[userID]
+	if !ok {
+		return models.TwoFactor{}, ErrNotFound
+	}
+	return twoFactor, nil
+}
+
+func (s *MemoryTwoFactorStore) Save(twoFactor *models.TwoFactor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if existing, ok := s.twoFactors[twoFactor.UserID]; ok {
+		twoFactor.CreatedAt = existing.CreatedAt
+	} else {
+		twoFactor.CreatedAt = now
+	}
+	twoFactor.UpdatedAt = now
+	s.twoFactors[twoFactor.UserID] = *twoFactor
+	return nil
+}
+
+func (s *MemoryTwoFactorStore) Enable(userID uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	twoFactor, ok := s.twoFactors[userID]
+	if !ok {
+		return ErrNotFound
+	}
+	twoFactor.Enabled = true
+	twoFactor.UpdatedAt = time.Now()
+	s.twoFactors[userID] = twoFactor
+	return nil
+}
+
+func (s *MemoryTwoFactorStore) UseStep(userID uint, step int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	twoFactor, ok := s.twoFactors[userID]
+	if !ok || twoFactor.LastStep >= step {
+		return false, nil
+	}
+	twoFactor.LastStep = step
+	twoFactor.UpdatedAt = time.Now()
+	s.twoFactors[userID] = twoFactor
+	return true, nil
+}
+
+func (s *MemoryTwoFactorStore) Delete(userID uint) error {
+	s.deleteUser(userID)
+	return nil
+}
+
+func (s *MemoryTwoFactorStore) ReplaceRecoveryCodes(userID uint, hashes []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteRecoveryCodes(userID)
+	now := time.Now()
+	for _, hash := range hashes {
+		s.recoveryCodes = append(s.recoveryCodes, models.RecoveryCode{ID: s.nextCodeID, UserID: userID, CodeHash: hash, CreatedAt: now})
+		s.nextCodeID++
+	}
+	return nil
+}
+
+func (s *MemoryTwoFactorStore) UseRecoveryCode(userID uint, hash string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, code := range s.recoveryCodes {
+		if code.UserID == userID && code.CodeHash == hash && code.UsedAt == nil {
+			now := time.Now()
+			s.recoveryCodes[i].UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// deleteRecoveryCodes removes the user's recovery codes; the caller holds
+// the lock
+func (s *MemoryTwoFactorStore) deleteRecoveryCodes(userID uint) {
+	kept := s.recoveryCodes[:0]
+	for _, code := range s.recoveryCodes {
+		if code.UserID != userID {
+			kept = append(kept, code)
+		}
+	}
+	s.recoveryCodes = kept
+}
+
+func (s *MemoryTwoFactorStore) deleteUser(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.twoFactors, userID)
+	s.deleteRecoveryCodes(userID)
+}
+
+type MemoryLockoutStore struct {
+	mu     sync.Mutex
+	events []models.LockoutEvent
+}
+
+func NewMemoryLockoutStore() *MemoryLockoutStore {
+	return &MemoryLockoutStore{}
+}
+
+func (s *MemoryLockoutStore) Create(event *models.LockoutEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event.ID = uint(len(s.events)) + 1
+	event.CreatedAt = time.Now()
+	s.events = append(s.events, *event)
+	return nil
+}
+
+func (s *MemoryLockoutStore) List(offset, limit int) ([]models.LockoutEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := []models.LockoutEvent{}
+	// events are appended in order, so newest first is back to front
+	for i := len(s.events) - 1 - offset; i >= 0 && len(events) < limit; i-- {
+		events = append(events, s.events[i])
+	}
+	return events, nil
+}
+
+type MemoryPasswordResetStore struct {
+	mu     sync.Mutex
+	tokens map[uint]models.PasswordResetToken
+	nextID uint
+}
+
+func NewMemoryPasswordResetStore() *MemoryPasswordResetStore {
+	return &MemoryPasswordResetStore{tokens: make(map[uint]models.PasswordResetToken), nextID: 1}
+}
+
+func (s *MemoryPasswordResetStore) Replace(token *models.PasswordResetToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id, existing := range s.tokens {
+		if existing.TokenHash == token.TokenHash {
+			return fmt.Errorf("password reset token hash %q already exists", token.TokenHash)
+		}
+		if existing.UserID == token.UserID && existing.UsedAt == nil {
+			existing.UsedAt = &now
+			s.tokens[id] = existing
+		}
+	}
+	token.ID = s.nextID
+	s.nextID++
+	token.CreatedAt = now
+	s.tokens[token.ID] = *token
+	return nil
+}
+
+func (s *MemoryPasswordResetStore) GetByHash(hash string) (models.PasswordResetToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, token := range s.tokens {
+		if token.TokenHash == hash {
+			return token, nil
+		}
+	}
+	return models.PasswordResetToken{}, ErrNotFound
+}
+
+func (s *MemoryPasswordResetStore) Redeem(id uint) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.tokens[id]
+	if !ok || token.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	s.tokens[id] = token
+	return true, nil
+}
+
+func (s *MemoryPasswordResetStore) deleteUser(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, token := range s.tokens {
+		if token.UserID == userID {
+			delete(s.tokens, id)
+		}
+	}
+}
+
+type MemoryPersonalTokenStore struct {
+	mu     sync.Mutex
+	tokens map[uint]models.PersonalAccessToken
+	nextID uint
+	users  UserStore // to refuse tokens of blocked accounts
+}
+
+func NewMemoryPersonalTokenStore(users UserStore) *MemoryPersonalTokenStore {
+	return &MemoryPersonalTokenStore{tokens: make(map[uint]models.PersonalAccessToken), nextID: 1, users: users}
+}
+
+func (s *MemoryPersonalTokenStore) Authenticate(token string, now time.Time) (*utils.PersonalToken, error) {
+	s.mu.Lock()
+	var record models.PersonalAccessToken
+	found := false
+	for _, stored := range s.tokens {
+		if stored.TokenHash == utils.HashToken(token) {
+			record, found = stored, true
+		}
+	}
+	s.mu.Unlock()
+	if !found || (record.ExpiresAt != nil && !now.Before(*record.ExpiresAt)) {
+		return nil, nil
+	}
+
+	user, err := s.users.Get(record.UserID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletionRequestedAt != nil || user.DisabledAt != nil || user.PasswordResetRequired {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	if stored, ok := s.tokens[record.ID]; ok {
+		stored.LastUsedAt = &now
+		s.tokens[record.ID] = stored
+	}
+	s.mu.Unlock()
+
+	return &utils.PersonalToken{
+		ID:     record.ID,
+		UserID: record.UserID,
+		Scopes: strings.Fields(record.Scopes),
+	}, nil
+}
+
+func (s *MemoryPersonalTokenStore) Create(token *models.PersonalAccessToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.tokens {
+		if existing.TokenHash == token.TokenHash {
+			return fmt.Errorf("personal token hash %q already exists", token.TokenHash)
+		}
+	}
+	token.ID = s.nextID
+	s.nextID++
+	token.CreatedAt = time.Now()
+	s.tokens[token.ID] = *token
+	return nil
+}
+
+func (s *MemoryPersonalTokenStore) List(userID uint) ([]models.PersonalAccessToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens := []models.PersonalAccessToken{}
+	for _, token := range s.tokens {
+		if token.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return newerFirst(tokens[i].CreatedAt, tokens[j].CreatedAt, tokens[i].ID, tokens[j].ID)
+	})
+	return tokens, nil
+}
+
+func (s *MemoryPersonalTokenStore) Count(userID uint) (int64, error) {
+	tokens, err := s.List(userID)
+	return int64(len(tokens)), err
+}
+
+func (s *MemoryPersonalTokenStore) Delete(userID, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token, ok := s.tokens[id]; !ok || token.UserID != userID {
+		return ErrNotFound
+	}
+	delete(s.tokens, id)
+	return nil
+}
+
+func (s *MemoryPersonalTokenStore) deleteUser(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, token := range s.tokens {
+		if token.UserID == userID {
+			delete(s.tokens, id)
+		}
+	}
+}
+
+type MemoryProfileStore struct {
+	mu       sync.Mutex
+	profiles map[uint]models.HealthProfile // by user ID
+	nextID   uint
+}
+
+func NewMemoryProfileStore() *MemoryProfileStore {
+	return &MemoryProfileStore{profiles: make(map[uint]models.HealthProfile), nextID: 1}
+}
+
+func (s *MemoryProfileStore) Get(userID uint) (models.HealthProfile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profile, ok := s.profiles[userID]
+	if !ok {
+		return models.HealthProfile{}, ErrNotFound
+	}
+	return profile, nil
+}
+
+func (s *MemoryProfileStore) Save(profile *models.HealthProfile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, exists := s.profiles[profile.UserID]
+	if profile.ID == 0 {
+		if exists {
+			return fmt.Errorf("user %d already has a profile", profile.UserID)
+		}
+		profile.ID = s.nextID
+		s.nextID++
+	} else if !exists || existing.ID != profile.ID {
+		return ErrNotFound
+	}
+	if profile.PreferredUnits == "" {
+		profile.PreferredUnits = "metric"
+	}
+	profile.UpdatedAt = time.Now()
+	s.profiles[profile.UserID] = *profile
+	return nil
+}
+
+func (s *MemoryProfileStore) deleteUser(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.profiles, userID)
+}
+
+type MemoryWaterStore struct {
+	mu     sync.Mutex
+	logs   map[uint]models.WaterIntake
+	edits  []models.WaterIntakeEdit
+	nextID uint
+}
+
+func NewMemoryWaterStore() *MemoryWaterStore {
+	return &MemoryWaterStore{logs: make(map[uint]models.WaterIntake), nextID: 1}
+}
+
+func (s *MemoryWaterStore) Create(log *models.WaterIntake) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log.ID = s.nextID
+	s.nextID++
+	now := time.Now()
+	log.CreatedAt, log.UpdatedAt = now, now
+	s.logs[log.ID] = *log
+	return nil
+}
+
+func (s *MemoryWaterStore) Get(userID, id uint) (models.WaterIntake, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log, ok := s.logs[id]
+	if !ok || log.UserID != userID {
+		return models.WaterIntake{}, ErrNotFound
+	}
+	return log, nil
+}
+
+func (s *MemoryWaterStore) List(userID uint, from, to time.Time) ([]models.WaterIntake, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	logs := []models.WaterIntake{}
+	for _, log := range s.logs {
+		if log.UserID != userID ||
+			(!from.IsZero() && log.LoggedAt.Before(from)) ||
+			(!to.IsZero() && !log.LoggedAt.Before(to)) {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return newerFirst(logs[i].LoggedAt, logs[j].LoggedAt, logs[i].ID, logs[j].ID)
+	})
+	return logs, nil
+}
+
+func (s *MemoryWaterStore) DailyTotals(userID uint, days []time.Time) ([]DayTotal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	byDay := make(map[int]DayTotal)
+	for _, log := range s.logs {
+		if log.UserID != userID {
+			continue
+		}
+		// the last start not after the log
+		day := sort.Search(len(days), func(i int) bool { return days[i].After(log.LoggedAt) }) - 1
+		if day < 0 || day >= len(days)-1 {
+			continue
+		}
+		total := byDay[day]
+		total.Day = day
+		total.TotalML += log.AmountML
+		total.HydrationML += log.HydrationML
+		total.CaffeineMG += log.CaffeineMG
+		total.EntryCount++
+		byDay[day] = total
+	}
+
+	totals := []DayTotal{}
+	for _, total := range byDay {
+		totals = append(totals, total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Day < totals[j].Day })
+	return totals, nil
+}
+
+func (s *MemoryWaterStore) Update(log *models.WaterIntake) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, ok := s.logs[log.ID]
+	if !ok || previous.UserID != log.UserID {
+		return ErrNotFound
+	}
+	now := time.Now()
+	s.edits = append(s.edits, models.WaterIntakeEdit{
+		ID:            uint(len(s.edits) + 1),
+		WaterIntakeID: previous.ID,
+		UserID:        previous.UserID,
+		AmountML:      previous.AmountML,
+		LoggedAt:      previous.LoggedAt,
+		BeverageID:    previous.BeverageID,
+		HydrationML:   previous.HydrationML,
+		CaffeineMG:    previous.CaffeineMG,
+		Kcal:          previous.Kcal,
+		EditedAt:      now,
+	})
+	log.CreatedAt, log.UpdatedAt = previous.CreatedAt, now
+	s.logs[log.ID] = *log
+	return nil
+}
+
+func (s *MemoryWaterStore) Edits(userID, id uint) ([]models.WaterIntakeEdit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	edits := []models.WaterIntakeEdit{}
+	for _, edit := range s.edits {
+		if edit.WaterIntakeID == id && edit.UserID == userID {
+			edits = append(edits, edit)
+		}
+	}
+	sort.Slice(edits, func(i, j int) bool {
+		return newerFirst(edits[i].EditedAt, edits[j].EditedAt, edits[i].ID, edits[j].ID)
+	})
+	return edits, nil
+}
+
+func (s *MemoryWaterStore) Delete(userID, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if log, ok := s.logs[id]; !ok || log.UserID != userID {
+		return ErrNotFound
+	}
+	delete(s.logs, id)
+	kept := s.edits[:0]
+	for _, edit := range s.edits {
+		if edit.WaterIntakeID != id {
+			kept = append(kept, edit)
+		}
+	}
+	s.edits = kept
+	return nil
+}
+
+func (s *MemoryWaterStore) deleteUser(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, log := range s.logs {
+		if log.UserID == userID {
+			delete(s.logs, id)
+		}
+	}
+	kept := s.edits[:0]
+	for _, edit := range s.edits {
+		if edit.UserID != userID {
+			kept = append(kept, edit)
+		}
+	}
+	s.edits = kept
+}
+
+type MemoryWeightStore struct {
+	mu     sync.Mutex
+	logs   map[uint]models.WeightLog
+	nextID uint
+}
+
+func NewMemoryWeightStore() *MemoryWeightStore {
+	return &MemoryWeightStore{logs: make(map[uint]models.WeightLog), nextID: 1}
+}
+
+func (s *MemoryWeightStore) Create(log *models.WeightLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log.ID = s.nextID
+	s.nextID++
+	if log.LoggedAt.IsZero() {
+		log.LoggedAt = time.Now()
+	}
+	s.logs[log.ID] = *log
+	return nil
+}
+
+func (s *MemoryWeightStore) Get(userID, id uint) (models.WeightLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log, ok := s.logs[id]
+	if !ok || log.UserID != userID {
+		return models.WeightLog{}, ErrNotFound
+	}
+	return log, nil
+}
+
+func (s *MemoryWeightStore) Recent(userID uint, limit int) ([]models.WeightLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	logs := []models.WeightLog{}
+	for _, log := range s.logs {
+		if log.UserID == userID {
+			logs = append(logs, log)
+		}
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return newerFirst(logs[i].LoggedAt, logs[j].LoggedAt, logs[i].ID, logs[j].ID)
+	})
+	if len(logs) > limit {
+		logs = logs[:limit]
+	}
+	return logs, nil
+}
+
+func (s *MemoryWeightStore) Update(log *models.WeightLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.logs[log.ID]; !ok || existing.UserID != log.UserID {
+		return ErrNotFound
+	}
+	s.logs[log.ID] = *log
+	return nil
+}
+
+func (s *MemoryWeightStore) deleteUser(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, log := range s.logs {
+		if log.UserID == userID {
+			delete(s.logs, id)
+		}
+	}
+}
+
+type MemoryExerciseStore struct {
+	mu     sync.Mutex
+	logs   map[uint]models.ExerciseLog
+	nextID uint
+}
+
+func NewMemoryExerciseStore() *MemoryExerciseStore {
+	return &MemoryExerciseStore{logs: make(map[uint]models.ExerciseLog), nextID: 1}
+}
+
+func (s *MemoryExerciseStore) Create(log *models.ExerciseLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log.ID = s.nextID
+	s.nextID++
+	if log.LoggedAt.IsZero() {
+		log.LoggedAt = time.Now()
+	}
+	s.logs[log.ID] = *log
+	return nil
+}
+
+func (s *MemoryExerciseStore) Recent(userID uint, limit int) ([]models.ExerciseLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	logs := []models.ExerciseLog{}
+	for _, log := range s.logs {
+		if log.UserID == userID {
+			logs = append(logs, log)
+		}
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return newerFirst(logs[i].LoggedAt, logs[j].LoggedAt, logs[i].ID, logs[j].ID)
+	})
+	if len(logs) > limit {
+		logs = logs[:limit]
+	}
+	return logs, nil
+}
+
+func (s *MemoryExerciseStore) List(userID uint, from, to time.Time) ([]models.ExerciseLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	logs := []models.ExerciseLog{}
+	for _, log := range s.logs {
+		if log.UserID != userID || log.LoggedAt.Before(from) || !log.LoggedAt.Before(to) {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return newerFirst(logs[i].LoggedAt, logs[j].LoggedAt, logs[i].ID, logs[j].ID)
+	})
+	return logs, nil
+}
+
+func (s *MemoryExerciseStore) deleteUser(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, log := range s.logs {
+		if log.UserID == userID {
+			delete(s.logs, id)
+		}
+	}
+}
+
+type MemoryHydrationGoalStore struct {
+	mu     sync.Mutex
+	goals  map[uint]models.HydrationGoal
+	nextID uint
+}
+
+func NewMemoryHydrationGoalStore() *MemoryHydrationGoalStore {
+	return &MemoryHydrationGoalStore{goals: make(map[uint]models.HydrationGoal), nextID: 1}
+}
+
+func (s *MemoryHydrationGoalStore) Set(goal *models.HydrationGoal) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	goal.ID, goal.CreatedAt = 0, now
+	for _, existing := range s.goals {
+		if existing.UserID == goal.UserID && existing.EffectiveFrom == goal.EffectiveFrom {
+			goal.ID, goal.CreatedAt = existing.ID, existing.CreatedAt
+		}
+	}
+	if goal.ID == 0 {
+		goal.ID = s.nextID
+		s.nextID++
+	}
+	goal.UpdatedAt = now
+	if goal.GoalML != nil {
+		// copied so the caller cannot change the stored goal
+		ml := *goal.GoalML
+		goal.GoalML = &ml
+	}
+	s.goals[goal.ID] = *goal
+	return nil
+}
+
+func (s *MemoryHydrationGoalStore) List(userID uint) ([]models.HydrationGoal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	goals := []models.HydrationGoal{}
+	for _, goal := range s.goals {
+		if goal.UserID == userID {
+			goals = append(goals, goal)
+		}
+	}
+	sort.Slice(goals, func(i, j int) bool { return goals[i].EffectiveFrom < goals[j].EffectiveFrom })
+	return goals, nil
+}
+
+func (s *MemoryHydrationGoalStore) deleteUser(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, goal := range s.goals {
+		if goal.UserID == userID {
+			delete(s.goals, id)
+		}
+	}
+}
+
+type MemoryBeverageStore struct {
+	mu        sync.Mutex
+	beverages map[uint]models.Beverage
+	nextID    uint
+}
+
+// NewMemoryBeverageStore returns a store holding the built-in catalog
+func NewMemoryBeverageStore() *MemoryBeverageStore {
+	s := &MemoryBeverageStore{beverages: make(map[uint]models.Beverage), nextID: 1}
+	for _, beverage := range BuiltinBeverages() {
+		s.Create(&beverage)
+	}
+	return s
+}
+
+func (s *MemoryBeverageStore) List(userID uint) ([]models.Beverage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	beverages := []models.Beverage{}
+	for _, beverage := range s.beverages {
+		if beverage.UserID == nil || *beverage.UserID == userID {
+			beverages = append(beverages, beverage)
+		}
+	}
+	sort.Slice(beverages, func(i, j int) bool {
+		if beverages[i].Name != beverages[j].Name {
+			return beverages[i].Name < beverages[j].Name
+		}
+		return beverages[i].ID < beverages[j].ID
+	})
+	return beverages, nil
+}
+
+func (s *MemoryBeverageStore) Get(userID, id uint) (models.Beverage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	beverage, ok := s.beverages[id]
+	if !ok || (beverage.UserID != nil && *beverage.UserID != userID) {
+		return models.Beverage{}, ErrNotFound
+	}
+	return beverage, nil
+}
+
+func (s *MemoryBeverageStore) Create(beverage *models.Beverage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	beverage.ID = s.nextID
+	s.nextID++
+	beverage.CreatedAt = time.Now()
+	s.beverages[beverage.ID] = *beverage
+	return nil
+}
+
+func (s *MemoryBeverageStore) Delete(userID, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// built-in beverages cannot be deleted
+	if beverage, ok := s.beverages[id]; !ok || beverage.UserID == nil || *beverage.UserID != userID {
+		return ErrNotFound
+	}
+	delete(s.beverages, id)
+	return nil
+}
+
+func (s *MemoryBeverageStore) deleteUser(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, beverage := range s.beverages {
+		if beverage.UserID != nil && *beverage.UserID == userID {
+			delete(s.beverages, id)
+		}
+	}
+}
+
+type MemoryReminderStore struct {
+	mu     sync.Mutex
+	rules  map[uint]models.ReminderRule
+	nextID uint
+}
+
+func NewMemoryReminderStore() *MemoryReminderStore {
+	return &MemoryReminderStore{rules: make(map[uint]models.ReminderRule), nextID: 1}
+}
+
+func (s *MemoryReminderStore) List(userID uint) ([]models.ReminderRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rules := []models.ReminderRule{}
+	for _, rule := range s.rules {
+		if rule.UserID == userID {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules, nil
+}
+
+func (s *MemoryReminderStore) Get(userID, id uint) (models.ReminderRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule, ok := s.rules[id]
+	if !ok || rule.UserID != userID {
+		return models.ReminderRule{}, ErrNotFound
+	}
+	return rule, nil
+}
+
+func (s *MemoryReminderStore) Create(rule *models.ReminderRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule.ID = s.nextID
+	s.nextID++
+	now := time.Now()
+	rule.CreatedAt, rule.UpdatedAt = now, now
+	s.rules[rule.ID] = *rule
+	return nil
+}
+
+func (s *MemoryReminderStore) Update(rule *models.ReminderRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.rules[rule.ID]
+	if !ok || existing.UserID != rule.UserID {
+		return ErrNotFound
+	}
+	rule.LastSentAt, rule.CreatedAt, rule.UpdatedAt = existing.LastSentAt, existing.CreatedAt, time.Now()
+	s.rules[rule.ID] = *rule
+	return nil
+}
+
+func (s *MemoryReminderStore) Delete(userID, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rule, ok := s.rules[id]; !ok || rule.UserID != userID {
+		return ErrNotFound
+	}
+	delete(s.rules, id)
+	return nil
+}
+
+func (s *MemoryReminderStore) Enabled() ([]models.ReminderRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rules := []models.ReminderRule{}
+	for _, rule := range s.rules {
+		if rule.Enabled {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules, nil
+}
+
+func (s *MemoryReminderStore) Claim(id uint, previous *time.Time, at time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule, ok := s.rules[id]
+	if !ok {
+		return false, nil
+	}
+	if (rule.LastSentAt == nil) != (previous == nil) || (previous != nil && !rule.LastSentAt.Equal(*previous)) {
+		return false, nil
+	}
+	rule.LastSentAt = &at
+	s.rules[id] = rule
+	return true, nil
+}
+
+func (s *MemoryReminderStore) deleteUser(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, rule := range s.rules {
+		if rule.UserID == userID {
+			delete(s.rules, id)
+		}
+	}
+}
+
+type MemoryNotificationStore struct {
+	mu            sync.Mutex
+	notifications map[uint]models.Notification
+	nextID        uint
+}
+
+func NewMemoryNotificationStore() *MemoryNotificationStore {
+	return &MemoryNotificationStore{notifications: make(map[uint]models.Notification), nextID: 1}
+}
+
+func (s *MemoryNotificationStore) Create(notification *models.Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	notification.ID = s.nextID
+	s.nextID++
+	notification.CreatedAt = time.Now()
+	s.notifications[notification.ID] = *notification
+	return nil
+}
+
+func (s *MemoryNotificationStore) List(userID uint, limit int) ([]models.Notification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	notifications := []models.Notification{}
+	for _, notification := range s.notifications {
+		if notification.UserID == userID {
+			notifications = append(notifications, notification)
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		return newerFirst(notifications[i].CreatedAt, notifications[j].CreatedAt, notifications[i].ID, notifications[j].ID)
+	})
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+	}
+	return notifications, nil
+}
+
+func (s *MemoryNotificationStore) MarkRead(userID, id uint, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	notification, ok := s.notifications[id]
+	if !ok || notification.UserID != userID {
+		return ErrNotFound
+	}
+	if notification.ReadAt == nil {
+		notification.ReadAt = &at
+		s.notifications[id] = notification
+	}
+	return nil
+}
+
+func (s *MemoryNotificationStore) deleteUser(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, notification := range s.notifications {
+		if notification.UserID == userID {
+			delete(s.notifications, id)
+		}
+	}
+}
+
+// MemoryStatsStore counts the records of the other memory stores
+type MemoryStatsStore struct {
+	users         *MemoryUserStore
+	refreshTokens *MemoryRefreshTokenStore
+	twoFactors    *MemoryTwoFactorStore
+	water         *MemoryWaterStore
+	weights       *MemoryWeightStore
+	exercises     *MemoryExerciseStore
+}
+
+func (s *MemoryStatsStore) Usage(weekAgo, monthAgo time.Time) (UsageStats, error) {
+	stats := UsageStats{UsersByRole: map[string]int64{}}
+
+	s.users.mu.Lock()
+	for _, user := range s.users.users {
+		stats.Users++
+		stats.UsersByRole[user.Role]++
+		if user.DisabledAt != nil {
+			stats.Disabled++
+		}
+		if user.DeletionRequestedAt != nil {
+			stats.PendingDeletion++
+		}
+		if !user.CreatedAt.Before(weekAgo) {
+			stats.NewLastWeek++
+		}
+		if !user.CreatedAt.Before(monthAgo) {
+			stats.NewLastMonth++
+		}
+	}
+	s.users.mu.Unlock()
+
+	s.twoFactors.mu.Lock()
+	for _, twoFactor := range s.twoFactors.twoFactors {
+		if twoFactor.Enabled {
+			stats.TwoFactorEnabled++
+		}
+	}
+	s.twoFactors.mu.Unlock()
+
+	s.refreshTokens.mu.Lock()
+	active := map[uint]bool{}
+	for _, token := range s.refreshTokens.tokens {
+		if !token.CreatedAt.Before(weekAgo) {
+			active[token.UserID] = true
+		}
+	}
+	stats.ActiveLastWeek = int64(len(active))
+	s.refreshTokens.mu.Unlock()
+
+	s.water.mu.Lock()
+	stats.WaterLogs = int64(len(s.water.logs))
+	s.water.mu.Unlock()
+	s.weights.mu.Lock()
+	stats.WeightLogs = int64(len(s.weights.logs))
+	s.weights.mu.Unlock()
+	s.exercises.mu.Lock()
+	stats.ExerciseLogs = int64(len(s.exercises.logs))
+	s.exercises.mu.Unlock()
+
+	return stats, nil
+}
+
+// copyTime copies a time so the caller cannot change the stored one
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
+
+// newerFirst orders logs by time, newest first, breaking ties by ID so the
+// order is stable
+func newerFirst(a, b time.Time, idA, idB uint) bool {
+	if !a.Equal(b) {
+		return a.After(b)
+	}
+	return idA > idB
+}
@@ -0,0 +1,14 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store/storetest"
+)
+
+func TestMemoryStores(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.Stores {
+		return store.NewMemoryStores()
+	})
+}
@@ -0,0 +1,263 @@
+// Package store defines the storage the handlers depend on. The GORM
+// implementations live in package database; the in-memory ones in this
+// package are fakes for tests. Handler tests run against both, and
+// storetest holds the behaviour every implementation must share.
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// ErrNotFound is returned when a record does not exist or belongs to
+// another user
+var ErrNotFound = errors.New("record not found")
+
+type UserStore interface {
+	Get(id uint) (models.User, error)
+	GetByUsername(username string) (models.User, error)
+	// GetByEmail looks up a normalized (lowercased) address
+	GetByEmail(email string) (models.User, error)
+	Create(user *models.User) error
+	// Search returns one page of the users matching a filter, by ID, and
+	// how many match in all
+	Search(filter UserFilter, offset, limit int) ([]models.User, int64, error)
+	// SetPassword stores a new password hash and clears
+	// PasswordResetRequired
+	SetPassword(id uint, hash string) error
+	RequirePasswordReset(id uint) error
+	SetRole(id uint, role string) error
+	// SetDisabled disables the user at the given time; nil enables them
+	SetDisabled(id uint, at *time.Time) error
+	// SetDeletionRequested schedules the user's deletion; nil cancels it
+	SetDeletionRequested(id uint, at *time.Time) error
+	// Delete removes the user along with every record of theirs in the
+	// other stores
+	Delete(id uint) error
+}
+
+// UserFilter narrows a user search. Zero fields match every user.
+type UserFilter struct {
+	// Query matches usernames and emails containing it, ignoring case
+	Query    string
+	Role     string
+	Disabled *bool
+}
+
+// RefreshTokenStore holds the refresh tokens of login sessions. The tokens
+// of one session share a FamilyID, and each is exchanged once for the next.
+type RefreshTokenStore interface {
+	Create(token *models.RefreshToken) error
+	GetByHash(hash string) (models.RefreshToken, error)
+	// Rotate marks a token used and stores the next one of its family in
+	// one step. It reports false and stores nothing if the token was
+	// already used, so of two concurrent exchanges only one wins.
+	Rotate(usedID uint, next *models.RefreshToken) (bool, error)
+	// RevokeFamily revokes every token of a session that is not revoked yet
+	RevokeFamily(familyID string) error
+	// RevokeUser revokes every token of the user that is not revoked yet
+	RevokeUser(userID uint) error
+}
+
+// TwoFactorStore holds each user's TOTP secret and recovery codes
+type TwoFactorStore interface {
+	// Get returns the user's second factor, whether or not it is enabled
+	Get(userID uint) (models.TwoFactor, error)
+	// Save inserts or replaces the user's second factor
+	Save(twoFactor *models.TwoFactor) error
+	// Enable turns on a second factor that was saved disabled
+	Enable(userID uint) error
+	// UseStep records that the TOTP code of a time step was accepted. It
+	// reports false if that step or a later one already was, so a code
+	// cannot be used twice.
+	UseStep(userID uint, step int64) (bool, error)
+	// Delete removes the user's second factor and recovery codes
+	Delete(userID uint) error
+	// ReplaceRecoveryCodes swaps the user's recovery codes for new ones,
+	// given as hashes
+	ReplaceRecoveryCodes(userID uint, hashes []string) error
+	// UseRecoveryCode marks an unused recovery code used. It reports false
+	// if the user has no such unused code.
+	UseRecoveryCode(userID uint, hash string) (bool, error)
+}
+
+// LockoutStore records login lockouts for administrators to review
+type LockoutStore interface {
+	Create(event *models.LockoutEvent) error
+	// List returns one page of lockouts, newest first
+	List(offset, limit int) ([]models.LockoutEvent, error)
+}
+
+// PasswordResetStore holds the tokens mailed to users who forgot their
+// password
+type PasswordResetStore interface {
+	// Replace stores a new token for a user and marks their outstanding
+	// ones used
+	Replace(token *models.PasswordResetToken) error
+	GetByHash(hash string) (models.PasswordResetToken, error)
+	// Redeem marks a token used. It reports false if it already was, so of
+	// two concurrent redemptions only one wins.
+	Redeem(id uint) (bool, error)
+}
+
+// PersonalTokenStore holds the personal access tokens scripts use instead
+// of a login session
+type PersonalTokenStore interface {
+	utils.PersonalTokenStore
+	Create(token *models.PersonalAccessToken) error
+	// List returns the user's tokens, newest first
+	List(userID uint) ([]models.PersonalAccessToken, error)
+	Count(userID uint) (int64, error)
+	Delete(userID, id uint) error
+}
+
+// ProfileStore holds one health profile per user
+type ProfileStore interface {
+	Get(userID uint) (models.HealthProfile, error)
+	// Save inserts a profile with no ID and updates one that has one
+	Save(profile *models.HealthProfile) error
+}
+
+type WaterStore interface {
+	Create(log *models.WaterIntake) error
+	Get(userID, id uint) (models.WaterIntake, error)
+	// List returns the user's logs in [from, to), newest first. A zero
+	// bound leaves that side open.
+	List(userID uint, from, to time.Time) ([]models.WaterIntake, error)
+	// DailyTotals sums the user's logs per day without loading them. days
+	// holds the start of each day followed by the end of the last, so day
+	// i is [days[i], days[i+1]). Days without logs are left out.
+	DailyTotals(userID uint, days []time.Time) ([]DayTotal, error)
+	// Update saves an edited log and keeps the version it replaces as a
+	// WaterIntakeEdit. The log's owner is checked like Get.
+	Update(log *models.WaterIntake) error
+	// Edits returns the earlier versions of a log, newest first
+	Edits(userID, id uint) ([]models.WaterIntakeEdit, error)
+	// Delete removes a log along with its edits
+	Delete(userID, id uint) error
+}
+
+// DayTotal is the water logged on one day of a DailyTotals range
+type DayTotal struct {
+	Day         int // index into the range
+	TotalML     int
+	HydrationML int
+	CaffeineMG  float64
+	EntryCount  int
+}
+
+// BeverageStore holds the built-in beverage catalog and the beverages
+// users add to it
+type BeverageStore interface {
+	// List returns the built-in beverages and the user's own, by name
+	List(userID uint) ([]models.Beverage, error)
+	// Get returns a built-in beverage or one of the user's own
+	Get(userID, id uint) (models.Beverage, error)
+	Create(beverage *models.Beverage) error
+	// Delete removes one of the user's own beverages. Logs of it keep the
+	// amounts worked out when they were logged.
+	Delete(userID, id uint) error
+}
+
+type WeightStore interface {
+	Create(log *models.WeightLog) error
+	Get(userID, id uint) (models.WeightLog, error)
+	// Recent returns the user's latest logs, newest first
+	Recent(userID uint, limit int) ([]models.WeightLog, error)
+	Update(log *models.WeightLog) error
+}
+
+type ExerciseStore interface {
+	Create(log *models.ExerciseLog) error
+	// Recent returns the user's latest logs, newest first
+	Recent(userID uint, limit int) ([]models.ExerciseLog, error)
+	// List returns the user's logs in [from, to), newest first
+	List(userID uint, from, to time.Time) ([]models.ExerciseLog, error)
+}
+
+// HydrationGoalStore keeps each user's history of water goals
+type HydrationGoalStore interface {
+	// Set saves a goal, replacing the user's goal for the same day
+	Set(goal *models.HydrationGoal) error
+	// List returns the user's whole goal history, oldest first. The goal
+	// in effect on a day is the last one whose EffectiveFrom is not after
+	// it.
+	List(userID uint) ([]models.HydrationGoal, error)
+}
+
+// ReminderStore holds users' hydration reminder rules
+type ReminderStore interface {
+	// List returns the user's rules, oldest first
+	List(userID uint) ([]models.ReminderRule, error)
+	Get(userID, id uint) (models.ReminderRule, error)
+	Create(rule *models.ReminderRule) error
+	// Update saves the user's changes to a rule. The owner is checked like
+	// Get, and LastSentAt is left as it is.
+	Update(rule *models.ReminderRule) error
+	Delete(userID, id uint) error
+	// Enabled returns every user's enabled rules, for the scheduler
+	Enabled() ([]models.ReminderRule, error)
+	// Claim records that a rule sends a reminder at at, provided its
+	// LastSentAt is still previous. Every server instance runs the
+	// scheduler; only the one that gets true sends.
+	Claim(id uint, previous *time.Time, at time.Time) (bool, error)
+}
+
+// NotificationStore is the in-app inbox
+type NotificationStore interface {
+	Create(notification *models.Notification) error
+	// List returns the user's latest notifications, newest first
+	List(userID uint, limit int) ([]models.Notification, error)
+	// MarkRead marks one of the user's notifications read at the given
+	// time. A notification that is already read keeps its time.
+	MarkRead(userID, id uint, at time.Time) error
+}
+
+// StatsStore computes the aggregate counts administrators see
+type StatsStore interface {
+	// Usage counts users and logs. Users created at or after weekAgo and
+	// monthAgo count as new; users with a session started or refreshed
+	// since weekAgo count as active.
+	Usage(weekAgo, monthAgo time.Time) (UsageStats, error)
+}
+
+// UsageStats are counts only; nothing in them identifies a user
+type UsageStats struct {
+	Users            int64
+	UsersByRole      map[string]int64
+	Disabled         int64
+	PendingDeletion  int64
+	TwoFactorEnabled int64
+	NewLastWeek      int64
+	NewLastMonth     int64
+	ActiveLastWeek   int64
+	WaterLogs        int64
+	WeightLogs       int64
+	ExerciseLogs     int64
+}
+
+// Stores bundles one implementation of each store for wiring up routes
+type Stores struct {
+	Users         UserStore
+	RefreshTokens RefreshTokenStore
+	TwoFactors    TwoFactorStore
+	Lockouts      LockoutStore
+	// PasswordResets and PersonalTokens hold hashes of the tokens only
+	PasswordResets PasswordResetStore
+	PersonalTokens PersonalTokenStore
+	// Revocations refuses access tokens before they expire
+	Revocations utils.RevocationStore
+	Profiles    ProfileStore
+	Water       WaterStore
+	Weights     WeightStore
+	Exercises   ExerciseStore
+	// HydrationGoals is the per-user water goal history
+	HydrationGoals HydrationGoalStore
+	Beverages      BeverageStore
+	Reminders      ReminderStore
+	Notifications  NotificationStore
+	Stats          StatsStore
+}
@@ -0,0 +1,1106 @@
+// Package storetest checks that a store implementation behaves the way the
+// handlers expect. The in-memory and database implementations both run it,
+// which keeps the fakes used by handler tests honest.
+package storetest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/store"
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/utils"
+)
+
+// Run tests a set of stores; open must return empty stores for each test
+func Run(t *testing.T, open func(t *testing.T) store.Stores) {
+	t.Run("Users", func(t *testing.T) { testUsers(t, open(t)) })
+	t.Run("UserSearch", func(t *testing.T) { testUserSearch(t, open(t)) })
+	t.Run("UserUpdates", func(t *testing.T) { testUserUpdates(t, open(t)) })
+	t.Run("UserDelete", func(t *testing.T) { testUserDelete(t, open(t)) })
+	t.Run("RefreshTokens", func(t *testing.T) { testRefreshTokens(t, open(t)) })
+	t.Run("TwoFactors", func(t *testing.T) { testTwoFactors(t, open(t)) })
+	t.Run("RecoveryCodes", func(t *testing.T) { testRecoveryCodes(t, open(t)) })
+	t.Run("Lockouts", func(t *testing.T) { testLockouts(t, open(t)) })
+	t.Run("PasswordResets", func(t *testing.T) { testPasswordResets(t, open(t)) })
+	t.Run("PersonalTokens", func(t *testing.T) { testPersonalTokens(t, open(t)) })
+	t.Run("Stats", func(t *testing.T) { testStats(t, open(t)) })
+	t.Run("Profiles", func(t *testing.T) { testProfiles(t, open(t)) })
+	t.Run("Water", func(t *testing.T) { testWater(t, open(t)) })
+	t.Run("Weights", func(t *testing.T) { testWeights(t, open(t)) })
+	t.Run("Exercises", func(t *testing.T) { testExercises(t, open(t)) })
+	t.Run("HydrationGoals", func(t *testing.T) { testHydrationGoals(t, open(t)) })
+	t.Run("Beverages", func(t *testing.T) { testBeverages(t, open(t)) })
+	t.Run("Reminders", func(t *testing.T) { testReminders(t, open(t)) })
+	t.Run("ReminderClaims", func(t *testing.T) { testReminderClaims(t, open(t)) })
+	t.Run("Notifications", func(t *testing.T) { testNotifications(t, open(t)) })
+}
+
+// createUsers adds users 1 and 2, which the log tables refer to
+func createUsers(t *testing.T, s store.Stores) {
+	t.Helper()
+	for _, user := range []models.User{
+		{ID: 1, Username: "alice", PasswordHash: "hash"},
+		{ID: 2, Username: "bob", PasswordHash: "hash"},
+	} {
+		if err := s.Users.Create(&user); err != nil {
+			t.Fatalf("Failed to create user %s: %v", user.Username, err)
+		}
+	}
+}
+
+func testUsers(t *testing.T, s store.Stores) {
+	user := models.User{Username: "alice", PasswordHash: "hash"}
+	if err := s.Users.Create(&user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if user.ID == 0 {
+		t.Fatal("Expected Create to assign an ID")
+	}
+	if err := s.Users.Create(&models.User{Username: "alice", PasswordHash: "hash"}); err == nil {
+		t.Error("Expected a duplicate username to be rejected")
+	}
+
+	got, err := s.Users.Get(user.ID)
+	if err != nil || got.Username != "alice" || got.Role != models.RoleUser {
+		t.Errorf("Get: got %+v, %v", got, err)
+	}
+	got, err = s.Users.GetByUsername("alice")
+	if err != nil || got.ID != user.ID {
+		t.Errorf("GetByUsername: got %+v, %v", got, err)
+	}
+
+	if _, err := s.Users.Get(user.ID + 100); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing ID, got %v", err)
+	}
+	if _, err := s.Users.GetByUsername("nobody"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing username, got %v", err)
+	}
+
+	email := "bob@example.com"
+	bob := models.User{Username: "bob", Email: &email, PasswordHash: "hash"}
+	if err := s.Users.Create(&bob); err != nil {
+		t.Fatalf("Create with email: %v", err)
+	}
+	got, err = s.Users.GetByEmail(email)
+	if err != nil || got.ID != bob.ID {
+		t.Errorf("GetByEmail: got %+v, %v", got, err)
+	}
+	if _, err := s.Users.GetByEmail("nobody@example.com"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing email, got %v", err)
+	}
+	if err := s.Users.Create(&models.User{Username: "carol", Email: &email, PasswordHash: "hash"}); err == nil {
+		t.Error("Expected a duplicate email to be rejected")
+	}
+}
+
+func testUserSearch(t *testing.T, s store.Stores) {
+	aliceEmail, carolEmail := "alice@example.com", "Carol@Example.org"
+	for _, user := range []models.User{
+		{Username: "alice", Email: &aliceEmail, PasswordHash: "hash"},
+		{Username: "bob_smith", PasswordHash: "hash", Role: models.RoleAdmin},
+		{Username: "bobxsmith", PasswordHash: "hash"},
+		{Username: "carol", Email: &carolEmail, PasswordHash: "hash", Role: models.RoleCoach},
+	} {
+		if err := s.Users.Create(&user); err != nil {
+			t.Fatalf("Create %s: %v", user.Username, err)
+		}
+	}
+	bobx, _ := s.Users.GetByUsername("bobxsmith")
+	now := time.Now()
+	if err := s.Users.SetDisabled(bobx.ID, &now); err != nil {
+		t.Fatalf("SetDisabled: %v", err)
+	}
+
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		filter store.UserFilter
+		want   []string
+	}{
+		{"everyone", store.UserFilter{}, []string{"alice", "bob_smith", "bobxsmith", "carol"}},
+		{"username ignoring case", store.UserFilter{Query: "BOB"}, []string{"bob_smith", "bobxsmith"}},
+		{"email", store.UserFilter{Query: "example.org"}, []string{"carol"}},
+		{"wildcards match literally", store.UserFilter{Query: "b_s"}, []string{"bob_smith"}},
+		{"percent matches literally", store.UserFilter{Query: "%"}, nil},
+		{"role", store.UserFilter{Role: models.RoleCoach}, []string{"carol"}},
+		{"disabled", store.UserFilter{Disabled: &yes}, []string{"bobxsmith"}},
+		{"not disabled", store.UserFilter{Query: "bob", Disabled: &no}, []string{"bob_smith"}},
+	}
+	for _, tt := range tests {
+		users, total, err := s.Users.Search(tt.filter, 0, 10)
+		if err != nil {
+			t.Fatalf("%s: Search: %v", tt.name, err)
+		}
+		var got []string
+		for _, user := range users {
+			got = append(got, user.Username)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) || total != int64(len(tt.want)) {
+			t.Errorf("%s: got %v (total %d), want %v", tt.name, got, total, tt.want)
+		}
+	}
+
+	// a page counts every match but holds only its own users, by ID
+	users, total, err := s.Users.Search(store.UserFilter{}, 1, 2)
+	if err != nil || total != 4 || len(users) != 2 || users[0].Username != "bob_smith" || users[1].Username != "bobxsmith" {
+		t.Errorf("Search page: got %+v (total %d), %v", users, total, err)
+	}
+	if users, _, err := s.Users.Search(store.UserFilter{}, 10, 2); err != nil || len(users) != 0 {
+		t.Errorf("Search past the end: got %+v, %v", users, err)
+	}
+}
+
+func testUserUpdates(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	if err := s.Users.RequirePasswordReset(1); err != nil {
+		t.Fatalf("RequirePasswordReset: %v", err)
+	}
+	if got, _ := s.Users.Get(1); !got.PasswordResetRequired {
+		t.Error("Expected a password reset to be required")
+	}
+	if err := s.Users.SetPassword(1, "new-hash"); err != nil {
+		t.Fatalf("SetPassword: %v", err)
+	}
+	if got, _ := s.Users.Get(1); got.PasswordHash != "new-hash" || got.PasswordResetRequired {
+		t.Errorf("Expected SetPassword to store the hash and clear the reset, got %+v", got)
+	}
+
+	if err := s.Users.SetRole(1, models.RoleCoach); err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+	if got, _ := s.Users.Get(1); got.Role != models.RoleCoach {
+		t.Errorf("Expected role coach, got %q", got.Role)
+	}
+
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := s.Users.SetDisabled(1, &at); err != nil {
+		t.Fatalf("SetDisabled: %v", err)
+	}
+	if err := s.Users.SetDeletionRequested(1, &at); err != nil {
+		t.Fatalf("SetDeletionRequested: %v", err)
+	}
+	got, _ := s.Users.Get(1)
+	if got.DisabledAt == nil || !got.DisabledAt.Equal(at) || got.DeletionRequestedAt == nil || !got.DeletionRequestedAt.Equal(at) {
+		t.Errorf("Expected both times to be set, got %+v", got)
+	}
+	if err := s.Users.SetDisabled(1, nil); err != nil {
+		t.Fatalf("SetDisabled (nil): %v", err)
+	}
+	if err := s.Users.SetDeletionRequested(1, nil); err != nil {
+		t.Fatalf("SetDeletionRequested (nil): %v", err)
+	}
+	if got, _ := s.Users.Get(1); got.DisabledAt != nil || got.DeletionRequestedAt != nil {
+		t.Errorf("Expected both times to be cleared, got %+v", got)
+	}
+	if got, _ := s.Users.Get(2); got.Role != models.RoleUser || got.PasswordHash != "hash" {
+		t.Errorf("Expected other users to be left alone, got %+v", got)
+	}
+
+	for name, err := range map[string]error{
+		"SetPassword":          s.Users.SetPassword(99, "hash"),
+		"RequirePasswordReset": s.Users.RequirePasswordReset(99),
+		"SetRole":              s.Users.SetRole(99, models.RoleAdmin),
+		"SetDisabled":          s.Users.SetDisabled(99, &at),
+		"SetDeletionRequested": s.Users.SetDeletionRequested(99, &at),
+		"Delete":               s.Users.Delete(99),
+	} {
+		if !errors.Is(err, store.ErrNotFound) {
+			t.Errorf("%s: expected ErrNotFound for a missing user, got %v", name, err)
+		}
+	}
+}
+
+func testUserDelete(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+	for _, userID := range []uint{1, 2} {
+		suffix := fmt.Sprint(userID)
+		records := []error{
+			s.Profiles.Save(&models.HealthProfile{UserID: userID, Sex: "female", HeightCM: 165, WeightKG: 60}),
+			s.Water.Create(&models.WaterIntake{UserID: userID, AmountML: 250, LoggedAt: time.Now()}),
+			s.Weights.Create(&models.WeightLog{UserID: userID, WeightKG: 60}),
+			s.Exercises.Create(&models.ExerciseLog{UserID: userID, Type: "Running", Duration: 30, CaloriesBurned: 300}),
+			s.Reminders.Create(&models.ReminderRule{UserID: userID, Kind: models.ReminderInterval, StartTime: "08:00", EndTime: "20:00", IntervalMinutes: 60}),
+			s.Notifications.Create(&models.Notification{UserID: userID, Kind: models.NotificationHydrationReminder, Title: "Drink"}),
+			s.RefreshTokens.Create(&models.RefreshToken{UserID: userID, FamilyID: "family-" + suffix, TokenHash: "refresh-" + suffix, ExpiresAt: time.Now().Add(time.Hour)}),
+			s.TwoFactors.Save(&models.TwoFactor{UserID: userID, Secret: "SECRET"}),
+			s.PasswordResets.Replace(&models.PasswordResetToken{UserID: userID, TokenHash: "reset-" + suffix, ExpiresAt: time.Now().Add(time.Hour)}),
+			s.PersonalTokens.Create(&models.PersonalAccessToken{UserID: userID, Name: "script", TokenHash: "pat-" + suffix, Hint: "abcd", Scopes: "water:read"}),
+		}
+		for _, err := range records {
+			if err != nil {
+				t.Fatalf("Failed to seed user %d: %v", userID, err)
+			}
+		}
+	}
+
+	if err := s.Users.Delete(1); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, err := s.Users.Get(1); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected the user to be gone, got %v", err)
+	}
+	if _, err := s.Profiles.Get(1); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected the profile to be gone, got %v", err)
+	}
+	if logs, _ := s.Water.List(1, time.Time{}, time.Time{}); len(logs) != 0 {
+		t.Errorf("Expected the water logs to be gone, got %+v", logs)
+	}
+	if logs, _ := s.Weights.Recent(1, 10); len(logs) != 0 {
+		t.Errorf("Expected the weight logs to be gone, got %+v", logs)
+	}
+	if logs, _ := s.Exercises.Recent(1, 10); len(logs) != 0 {
+		t.Errorf("Expected the exercise logs to be gone, got %+v", logs)
+	}
+	if rules, _ := s.Reminders.List(1); len(rules) != 0 {
+		t.Errorf("Expected the reminder rules to be gone, got %+v", rules)
+	}
+	if notifications, _ := s.Notifications.List(1, 10); len(notifications) != 0 {
+		t.Errorf("Expected the notifications to be gone, got %+v", notifications)
+	}
+	if _, err := s.RefreshTokens.GetByHash("refresh-1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected the refresh token to be gone, got %v", err)
+	}
+	if _, err := s.TwoFactors.Get(1); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected the second factor to be gone, got %v", err)
+	}
+	if _, err := s.PasswordResets.GetByHash("reset-1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected the reset token to be gone, got %v", err)
+	}
+	if tokens, _ := s.PersonalTokens.List(1); len(tokens) != 0 {
+		t.Errorf("Expected the personal tokens to be gone, got %+v", tokens)
+	}
+
+	// the other user keeps everything
+	if _, err := s.Profiles.Get(2); err != nil {
+		t.Errorf("Expected the other profile to be kept, got %v", err)
+	}
+	if logs, _ := s.Water.List(2, time.Time{}, time.Time{}); len(logs) != 1 {
+		t.Errorf("Expected the other water log to be kept, got %+v", logs)
+	}
+	if _, err := s.RefreshTokens.GetByHash("refresh-2"); err != nil {
+		t.Errorf("Expected the other refresh token to be kept, got %v", err)
+	}
+	if tokens, _ := s.PersonalTokens.List(2); len(tokens) != 1 {
+		t.Errorf("Expected the other personal token to be kept, got %+v", tokens)
+	}
+}
+
+func testRefreshTokens(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	first := models.RefreshToken{UserID: 1, FamilyID: "family-a", TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.RefreshTokens.Create(&first); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	other := models.RefreshToken{UserID: 2, FamilyID: "family-b", TokenHash: "hash-other", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.RefreshTokens.Create(&other); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := s.RefreshTokens.GetByHash("hash-1")
+	if err != nil || got.ID != first.ID || got.FamilyID != "family-a" || got.UsedAt != nil {
+		t.Errorf("GetByHash: got %+v, %v", got, err)
+	}
+	if _, err := s.RefreshTokens.GetByHash("missing"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing hash, got %v", err)
+	}
+
+	// the first exchange wins; a second one of the same token stores nothing
+	second := models.RefreshToken{UserID: 1, FamilyID: "family-a", TokenHash: "hash-2", ExpiresAt: time.Now().Add(time.Hour)}
+	if rotated, err := s.RefreshTokens.Rotate(first.ID, &second); err != nil || !rotated {
+		t.Fatalf("Rotate: got %v, %v", rotated, err)
+	}
+	if got, _ := s.RefreshTokens.GetByHash("hash-1"); got.UsedAt == nil {
+		t.Error("Expected Rotate to mark the token used")
+	}
+	if _, err := s.RefreshTokens.GetByHash("hash-2"); err != nil {
+		t.Errorf("Expected Rotate to store the next token, got %v", err)
+	}
+	replay := models.RefreshToken{UserID: 1, FamilyID: "family-a", TokenHash: "hash-3", ExpiresAt: time.Now().Add(time.Hour)}
+	if rotated, err := s.RefreshTokens.Rotate(first.ID, &replay); err != nil || rotated {
+		t.Errorf("Expected a used token not to rotate, got %v, %v", rotated, err)
+	}
+	if _, err := s.RefreshTokens.GetByHash("hash-3"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected a failed rotation to store nothing, got %v", err)
+	}
+
+	if err := s.RefreshTokens.RevokeFamily("family-a"); err != nil {
+		t.Fatalf("RevokeFamily: %v", err)
+	}
+	for _, hash := range []string{"hash-1", "hash-2"} {
+		if got, _ := s.RefreshTokens.GetByHash(hash); got.RevokedAt == nil {
+			t.Errorf("Expected %s to be revoked", hash)
+		}
+	}
+	if got, _ := s.RefreshTokens.GetByHash("hash-other"); got.RevokedAt != nil {
+		t.Error("Expected other families to be left alone")
+	}
+
+	revoked, _ := s.RefreshTokens.GetByHash("hash-1")
+	later := models.RefreshToken{UserID: 1, FamilyID: "family-c", TokenHash: "hash-later", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.RefreshTokens.Create(&later); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := s.RefreshTokens.RevokeUser(1); err != nil {
+		t.Fatalf("RevokeUser: %v", err)
+	}
+	if got, _ := s.RefreshTokens.GetByHash("hash-later"); got.RevokedAt == nil {
+		t.Error("Expected every session of the user to be revoked")
+	}
+	if got, _ := s.RefreshTokens.GetByHash("hash-1"); !got.RevokedAt.Equal(*revoked.RevokedAt) {
+		t.Errorf("Expected revoked tokens to keep their time, got %v then %v", revoked.RevokedAt, got.RevokedAt)
+	}
+	if got, _ := s.RefreshTokens.GetByHash("hash-other"); got.RevokedAt != nil {
+		t.Error("Expected other users to be left alone")
+	}
+}
+
+func testTwoFactors(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	if _, err := s.TwoFactors.Get(1); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound before setup, got %v", err)
+	}
+
+	twoFactor := models.TwoFactor{UserID: 1, Secret: "SECRET1"}
+	if err := s.TwoFactors.Save(&twoFactor); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	// saving again replaces the secret
+	twoFactor = models.TwoFactor{UserID: 1, Secret: "SECRET2", Enabled: true}
+	if err := s.TwoFactors.Save(&twoFactor); err != nil {
+		t.Fatalf("Save (replace): %v", err)
+	}
+
+	got, err := s.TwoFactors.Get(1)
+	if err != nil || got.Secret != "SECRET2" || !got.Enabled {
+		t.Errorf("Get: got %+v, %v", got, err)
+	}
+	if _, err := s.TwoFactors.Get(2); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected other users to have no second factor, got %v", err)
+	}
+
+	pending := models.TwoFactor{UserID: 2, Secret: "SECRET3"}
+	if err := s.TwoFactors.Save(&pending); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := s.TwoFactors.Enable(2); err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	if got, _ := s.TwoFactors.Get(2); !got.Enabled || got.Secret != "SECRET3" {
+		t.Errorf("Expected Enable to keep the secret, got %+v", got)
+	}
+	if err := s.TwoFactors.Enable(99); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound enabling a missing second factor, got %v", err)
+	}
+
+	// a step is only accepted once, and never after a later one
+	if used, err := s.TwoFactors.UseStep(1, 100); err != nil || !used {
+		t.Fatalf("UseStep: got %v, %v", used, err)
+	}
+	if used, _ := s.TwoFactors.UseStep(1, 100); used {
+		t.Error("Expected the same step to be refused")
+	}
+	if used, _ := s.TwoFactors.UseStep(1, 99); used {
+		t.Error("Expected an earlier step to be refused")
+	}
+	if got, _ := s.TwoFactors.Get(1); got.LastStep != 100 {
+		t.Errorf("Expected LastStep 100, got %d", got.LastStep)
+	}
+	if used, _ := s.TwoFactors.UseStep(99, 100); used {
+		t.Error("Expected a missing second factor to accept no step")
+	}
+
+	if err := s.TwoFactors.Delete(1); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.TwoFactors.Get(1); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after Delete, got %v", err)
+	}
+	if _, err := s.TwoFactors.Get(2); err != nil {
+		t.Errorf("Expected other users to keep their second factor, got %v", err)
+	}
+}
+
+func testRecoveryCodes(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+	if err := s.TwoFactors.Save(&models.TwoFactor{UserID: 1, Secret: "SECRET", Enabled: true}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := s.TwoFactors.ReplaceRecoveryCodes(1, []string{"code-a", "code-b"}); err != nil {
+		t.Fatalf("ReplaceRecoveryCodes: %v", err)
+	}
+	if err := s.TwoFactors.ReplaceRecoveryCodes(2, []string{"code-c"}); err != nil {
+		t.Fatalf("ReplaceRecoveryCodes: %v", err)
+	}
+
+	if used, err := s.TwoFactors.UseRecoveryCode(1, "code-a"); err != nil || !used {
+		t.Fatalf("UseRecoveryCode: got %v, %v", used, err)
+	}
+	if used, _ := s.TwoFactors.UseRecoveryCode(1, "code-a"); used {
+		t.Error("Expected a used code to be refused")
+	}
+	if used, _ := s.TwoFactors.UseRecoveryCode(1, "code-c"); used {
+		t.Error("Expected another user's code to be refused")
+	}
+
+	// new codes replace the old ones, used or not
+	if err := s.TwoFactors.ReplaceRecoveryCodes(1, []string{"code-d"}); err != nil {
+		t.Fatalf("ReplaceRecoveryCodes: %v", err)
+	}
+	if used, _ := s.TwoFactors.UseRecoveryCode(1, "code-b"); used {
+		t.Error("Expected a replaced code to be refused")
+	}
+
+	if err := s.TwoFactors.Delete(1); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if used, _ := s.TwoFactors.UseRecoveryCode(1, "code-d"); used {
+		t.Error("Expected Delete to remove the recovery codes")
+	}
+	if used, _ := s.TwoFactors.UseRecoveryCode(2, "code-c"); !used {
+		t.Error("Expected other users to keep their recovery codes")
+	}
+}
+
+func testLockouts(t *testing.T, s store.Stores) {
+	event := models.LockoutEvent{Scope: "username", Username: "alice", IP: "192.0.2.1", Failures: 5, LockedUntil: time.Now().Add(time.Minute)}
+	if err := s.Lockouts.Create(&event); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if event.ID == 0 {
+		t.Error("Expected Create to assign an ID")
+	}
+	for _, username := range []string{"bob", "carol"} {
+		if err := s.Lockouts.Create(&models.LockoutEvent{Scope: "username", Username: username, Failures: 5, LockedUntil: time.Now().Add(time.Minute)}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	events, err := s.Lockouts.List(0, 2)
+	if err != nil || len(events) != 2 || events[0].Username != "carol" || events[1].Username != "bob" {
+		t.Errorf("List: got %+v, %v", events, err)
+	}
+	events, err = s.Lockouts.List(2, 2)
+	if err != nil || len(events) != 1 || events[0].ID != event.ID || events[0].IP != "192.0.2.1" {
+		t.Errorf("List (second page): got %+v, %v", events, err)
+	}
+	if events, _ := s.Lockouts.List(5, 2); len(events) != 0 {
+		t.Errorf("Expected no events past the end, got %+v", events)
+	}
+}
+
+func testPasswordResets(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	first := models.PasswordResetToken{UserID: 1, TokenHash: "reset-1", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.PasswordResets.Replace(&first); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	other := models.PasswordResetToken{UserID: 2, TokenHash: "reset-other", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.PasswordResets.Replace(&other); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	got, err := s.PasswordResets.GetByHash("reset-1")
+	if err != nil || got.ID != first.ID || got.UserID != 1 || got.UsedAt != nil {
+		t.Errorf("GetByHash: got %+v, %v", got, err)
+	}
+	if _, err := s.PasswordResets.GetByHash("missing"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing hash, got %v", err)
+	}
+
+	// a new token supersedes the user's outstanding one
+	second := models.PasswordResetToken{UserID: 1, TokenHash: "reset-2", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.PasswordResets.Replace(&second); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	if got, _ := s.PasswordResets.GetByHash("reset-1"); got.UsedAt == nil {
+		t.Error("Expected the earlier token to be marked used")
+	}
+	if got, _ := s.PasswordResets.GetByHash("reset-other"); got.UsedAt != nil {
+		t.Error("Expected other users' tokens to be left alone")
+	}
+
+	if redeemed, err := s.PasswordResets.Redeem(second.ID); err != nil || !redeemed {
+		t.Fatalf("Redeem: got %v, %v", redeemed, err)
+	}
+	if redeemed, _ := s.PasswordResets.Redeem(second.ID); redeemed {
+		t.Error("Expected a token to be redeemed only once")
+	}
+	if redeemed, _ := s.PasswordResets.Redeem(first.ID); redeemed {
+		t.Error("Expected a superseded token not to be redeemed")
+	}
+}
+
+func testPersonalTokens(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	hash := utils.HashToken("ftpat_first")
+	first := models.PersonalAccessToken{UserID: 1, Name: "first", TokenHash: hash, Hint: "irst", Scopes: "water:read weight:write"}
+	if err := s.PersonalTokens.Create(&first); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	second := models.PersonalAccessToken{UserID: 1, Name: "second", TokenHash: utils.HashToken("ftpat_second"), Hint: "cond", Scopes: "water:read"}
+	if err := s.PersonalTokens.Create(&second); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	other := models.PersonalAccessToken{UserID: 2, Name: "other", TokenHash: utils.HashToken("ftpat_other"), Hint: "ther", Scopes: "water:read"}
+	if err := s.PersonalTokens.Create(&other); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	tokens, err := s.PersonalTokens.List(1)
+	if err != nil || len(tokens) != 2 || tokens[0].Name != "second" || tokens[1].Name != "first" {
+		t.Errorf("List: got %+v, %v", tokens, err)
+	}
+	if count, err := s.PersonalTokens.Count(1); err != nil || count != 2 {
+		t.Errorf("Count: got %d, %v", count, err)
+	}
+
+	now := time.Now()
+	authenticated, err := s.PersonalTokens.Authenticate("ftpat_first", now)
+	if err != nil || authenticated == nil || authenticated.ID != first.ID || authenticated.UserID != 1 ||
+		fmt.Sprint(authenticated.Scopes) != "[water:read weight:write]" {
+		t.Fatalf("Authenticate: got %+v, %v", authenticated, err)
+	}
+	if tokens, _ := s.PersonalTokens.List(1); tokens[1].LastUsedAt == nil {
+		t.Error("Expected Authenticate to record when the token was used")
+	}
+	if authenticated, err := s.PersonalTokens.Authenticate("ftpat_unknown", now); err != nil || authenticated != nil {
+		t.Errorf("Expected an unknown token to be refused, got %+v, %v", authenticated, err)
+	}
+
+	expiresAt := now.Add(-time.Minute)
+	expired := models.PersonalAccessToken{UserID: 2, Name: "expired", TokenHash: utils.HashToken("ftpat_expired"), Hint: "ired", Scopes: "water:read", ExpiresAt: &expiresAt}
+	if err := s.PersonalTokens.Create(&expired); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if authenticated, _ := s.PersonalTokens.Authenticate("ftpat_expired", now); authenticated != nil {
+		t.Error("Expected an expired token to be refused")
+	}
+
+	// tokens stop working while their account is blocked
+	if err := s.Users.SetDisabled(2, &now); err != nil {
+		t.Fatalf("SetDisabled: %v", err)
+	}
+	if authenticated, _ := s.PersonalTokens.Authenticate("ftpat_other", now); authenticated != nil {
+		t.Error("Expected a disabled user's token to be refused")
+	}
+
+	if err := s.PersonalTokens.Delete(2, first.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting another user's token, got %v", err)
+	}
+	if err := s.PersonalTokens.Delete(1, first.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if authenticated, _ := s.PersonalTokens.Authenticate("ftpat_first", now); authenticated != nil {
+		t.Error("Expected a deleted token to be refused")
+	}
+	if err := s.PersonalTokens.Delete(1, first.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+	}
+}
+
+func testStats(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+	admin := models.User{Username: "carol", PasswordHash: "hash", Role: models.RoleAdmin}
+	if err := s.Users.Create(&admin); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	now := time.Now()
+	seeded := []error{
+		s.Users.SetDisabled(2, &now),
+		s.Users.SetDeletionRequested(1, &now),
+		s.TwoFactors.Save(&models.TwoFactor{UserID: 1, Secret: "SECRET", Enabled: true}),
+		s.TwoFactors.Save(&models.TwoFactor{UserID: 2, Secret: "SECRET"}),
+		// two sessions of one user count once
+		s.RefreshTokens.Create(&models.RefreshToken{UserID: 1, FamilyID: "a", TokenHash: "hash-a", ExpiresAt: now.Add(time.Hour)}),
+		s.RefreshTokens.Create(&models.RefreshToken{UserID: 1, FamilyID: "b", TokenHash: "hash-b", ExpiresAt: now.Add(time.Hour)}),
+		s.Water.Create(&models.WaterIntake{UserID: 1, AmountML: 250, LoggedAt: now}),
+		s.Water.Create(&models.WaterIntake{UserID: 2, AmountML: 250, LoggedAt: now}),
+		s.Weights.Create(&models.WeightLog{UserID: 1, WeightKG: 60}),
+	}
+	for _, err := range seeded {
+		if err != nil {
+			t.Fatalf("Failed to seed: %v", err)
+		}
+	}
+
+	stats, err := s.Stats.Usage(now.AddDate(0, 0, -7), now.AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	want := store.UsageStats{
+		Users:            3,
+		UsersByRole:      map[string]int64{models.RoleUser: 2, models.RoleAdmin: 1},
+		Disabled:         1,
+		PendingDeletion:  1,
+		TwoFactorEnabled: 1,
+		NewLastWeek:      3,
+		NewLastMonth:     3,
+		ActiveLastWeek:   1,
+		WaterLogs:        2,
+		WeightLogs:       1,
+	}
+	if fmt.Sprintf("%+v", stats) != fmt.Sprintf("%+v", want) {
+		t.Errorf("Usage: got %+v, want %+v", stats, want)
+	}
+
+	// nobody was created or active after now
+	later := now.Add(time.Minute)
+	if stats, _ := s.Stats.Usage(later, later); stats.NewLastWeek != 0 || stats.NewLastMonth != 0 || stats.ActiveLastWeek != 0 {
+		t.Errorf("Expected nothing new or active since %v, got %+v", later, stats)
+	}
+}
+
+func testProfiles(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	if _, err := s.Profiles.Get(1); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound before a profile exists, got %v", err)
+	}
+
+	profile := models.HealthProfile{UserID: 1, Sex: "female", HeightCM: 170, WeightKG: 60}
+	if err := s.Profiles.Save(&profile); err != nil {
+		t.Fatalf("Save (create): %v", err)
+	}
+	if profile.ID == 0 {
+		t.Fatal("Expected Save to assign an ID")
+	}
+
+	got, err := s.Profiles.Get(1)
+	if err != nil || got.HeightCM != 170 || got.PreferredUnits != "metric" {
+		t.Errorf("Get: got %+v, %v", got, err)
+	}
+
+	got.WeightKG = 62
+	if err := s.Profiles.Save(&got); err != nil {
+		t.Fatalf("Save (update): %v", err)
+	}
+	if updated, _ := s.Profiles.Get(1); updated.WeightKG != 62 || updated.ID != profile.ID {
+		t.Errorf("Expected the profile updated in place, got %+v", updated)
+	}
+	if _, err := s.Profiles.Get(2); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected no profile for user 2, got %v", err)
+	}
+}
+
+func testWater(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	east := time.FixedZone("UTC+2", 2*60*60)
+	logs := []models.WaterIntake{
+		{UserID: 1, AmountML: 100, LoggedAt: day.Add(-time.Minute)},
+		{UserID: 1, AmountML: 200, HydrationML: 180, CaffeineMG: 80, LoggedAt: day},
+		// 22:30 UTC on the 10th, written in another zone
+		{UserID: 1, AmountML: 300, HydrationML: 300, LoggedAt: time.Date(2026, 3, 11, 0, 30, 0, 0, east)},
+		{UserID: 1, AmountML: 400, LoggedAt: day.Add(24 * time.Hour)},
+		{UserID: 2, AmountML: 500, LoggedAt: day.Add(time.Hour)},
+	}
+	for i := range logs {
+		if err := s.Water.Create(&logs[i]); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	got, err := s.Water.List(1, day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(got) != 2 || got[0].AmountML != 300 || got[1].AmountML != 200 {
+		t.Errorf("Expected the 300ml and 200ml logs, newest first, got %+v", got)
+	}
+
+	totals, err := s.Water.DailyTotals(1, []time.Time{day.Add(-24 * time.Hour), day, day.Add(24 * time.Hour), day.Add(48 * time.Hour), day.Add(72 * time.Hour)})
+	if err != nil {
+		t.Fatalf("DailyTotals: %v", err)
+	}
+	expected := []store.DayTotal{
+		{Day: 0, TotalML: 100, EntryCount: 1},
+		{Day: 1, TotalML: 500, HydrationML: 480, CaffeineMG: 80, EntryCount: 2},
+		{Day: 2, TotalML: 400, EntryCount: 1},
+	}
+	if len(totals) != len(expected) {
+		t.Fatalf("Expected totals for 3 days, got %+v", totals)
+	}
+	for i := range expected {
+		if totals[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], totals[i])
+		}
+	}
+
+	all, _ := s.Water.List(1, time.Time{}, time.Time{})
+	if len(all) != 4 {
+		t.Errorf("Expected all 4 of user 1's logs without bounds, got %d", len(all))
+	}
+
+	if _, err := s.Water.Get(1, logs[4].ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected another user's log to be hidden, got %v", err)
+	}
+	if err := s.Water.Delete(1, logs[4].ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected deleting another user's log to fail, got %v", err)
+	}
+
+	stolen := logs[4]
+	stolen.UserID = 1
+	if err := s.Water.Update(&stolen); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected updating another user's log to fail, got %v", err)
+	}
+	edited := logs[1]
+	for _, amount := range []int{250, 260} {
+		edited.AmountML, edited.HydrationML = amount, amount
+		if err := s.Water.Update(&edited); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+	if current, _ := s.Water.Get(1, edited.ID); current.AmountML != 260 || current.HydrationML != 260 || !current.LoggedAt.Equal(day) {
+		t.Errorf("Expected the log to be updated in place, got %+v", current)
+	}
+	edits, err := s.Water.Edits(1, edited.ID)
+	if err != nil {
+		t.Fatalf("Edits: %v", err)
+	}
+	if len(edits) != 2 || edits[0].AmountML != 250 || edits[1].AmountML != 200 || edits[1].CaffeineMG != 80 {
+		t.Errorf("Expected the 250ml and 200ml versions, newest first, got %+v", edits)
+	}
+	if edits, _ := s.Water.Edits(2, edited.ID); len(edits) != 0 {
+		t.Errorf("Expected another user's edits to be hidden, got %+v", edits)
+	}
+	if err := s.Water.Delete(1, edited.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if edits, _ := s.Water.Edits(1, edited.ID); len(edits) != 0 {
+		t.Errorf("Expected a deleted log's edits to go with it, got %+v", edits)
+	}
+	if err := s.Water.Delete(1, logs[0].ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Water.Get(1, logs[0].ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected the deleted log to be gone, got %v", err)
+	}
+}
+
+func testWeights(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < 5; i++ {
+		log := models.WeightLog{UserID: 1, WeightKG: 70 + float64(i), LoggedAt: now.Add(time.Duration(i) * time.Hour)}
+		if err := s.Weights.Create(&log); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	other := models.WeightLog{UserID: 2, WeightKG: 90, LoggedAt: now.Add(time.Hour)}
+	s.Weights.Create(&other)
+
+	recent, err := s.Weights.Recent(1, 3)
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(recent) != 3 || recent[0].WeightKG != 74 || recent[2].WeightKG != 72 {
+		t.Errorf("Expected the 3 newest logs, newest first, got %+v", recent)
+	}
+
+	latest := recent[0]
+	latest.WeightKG = 80
+	if err := s.Weights.Update(&latest); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got, _ := s.Weights.Get(1, latest.ID); got.WeightKG != 80 {
+		t.Errorf("Expected the update to be saved, got %+v", got)
+	}
+
+	other.UserID = 1
+	if err := s.Weights.Update(&other); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected updating another user's log to fail, got %v", err)
+	}
+
+	defaulted := models.WeightLog{UserID: 2, WeightKG: 91}
+	s.Weights.Create(&defaulted)
+	if defaulted.LoggedAt.IsZero() {
+		t.Error("Expected Create to default logged_at")
+	}
+}
+
+func testExercises(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		log := models.ExerciseLog{UserID: 1, Type: "Running", Duration: 10 * (i + 1), CaloriesBurned: 100, LoggedAt: now.Add(time.Duration(i) * time.Minute)}
+		if err := s.Exercises.Create(&log); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	s.Exercises.Create(&models.ExerciseLog{UserID: 2, Type: "Cycling", Duration: 5, CaloriesBurned: 50, LoggedAt: now})
+
+	recent, err := s.Exercises.Recent(1, 2)
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(recent) != 2 || recent[0].Duration != 30 || recent[1].Duration != 20 {
+		t.Errorf("Expected the 2 newest logs, newest first, got %+v", recent)
+	}
+
+	listed, err := s.Exercises.List(1, now.Add(time.Minute), now.Add(3*time.Minute))
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(listed) != 2 || listed[0].Duration != 30 || listed[1].Duration != 20 {
+		t.Errorf("Expected the logs in range, newest first, got %+v", listed)
+	}
+}
+
+func testHydrationGoals(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	if history, err := s.HydrationGoals.List(1); err != nil || len(history) != 0 {
+		t.Errorf("Expected no goals before any is set, got %+v, %v", history, err)
+	}
+
+	ml := func(n int) *int { return &n }
+	for _, goal := range []models.HydrationGoal{
+		{UserID: 1, GoalML: ml(3000), EffectiveFrom: "2026-03-20"},
+		{UserID: 1, GoalML: ml(2500), EffectiveFrom: "2026-03-01"},
+		{UserID: 1, GoalML: nil, EffectiveFrom: "2026-03-10"},
+		{UserID: 2, GoalML: ml(1800), EffectiveFrom: "2026-03-05"},
+	} {
+		if err := s.HydrationGoals.Set(&goal); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+	}
+
+	history, err := s.HydrationGoals.List(1)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(history) != 3 || history[0].EffectiveFrom != "2026-03-01" || history[1].GoalML != nil ||
+		history[2].GoalML == nil || *history[2].GoalML != 3000 {
+		t.Errorf("Expected user 1's 3 goals, oldest first, got %+v", history)
+	}
+
+	// setting a goal for the same day replaces it
+	replaced := models.HydrationGoal{UserID: 1, GoalML: ml(2750), EffectiveFrom: "2026-03-01"}
+	if err := s.HydrationGoals.Set(&replaced); err != nil {
+		t.Fatalf("Set (replace): %v", err)
+	}
+	history, _ = s.HydrationGoals.List(1)
+	if len(history) != 3 || history[0].GoalML == nil || *history[0].GoalML != 2750 || history[0].ID != replaced.ID {
+		t.Errorf("Expected the replaced goal, got %+v", history)
+	}
+}
+
+func testBeverages(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	// the database gets the catalog from a migration, which must match
+	builtins := make(map[string]models.Beverage)
+	for _, beverage := range store.BuiltinBeverages() {
+		builtins[beverage.Name] = beverage
+	}
+	catalog, err := s.Beverages.List(1)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(catalog) != len(builtins) {
+		t.Fatalf("Expected the %d built-in beverages, got %+v", len(builtins), catalog)
+	}
+	for _, beverage := range catalog {
+		builtin, ok := builtins[beverage.Name]
+		if !ok || beverage.UserID != nil || beverage.HydrationFactor != builtin.HydrationFactor ||
+			beverage.CaffeineMGPer100ML != builtin.CaffeineMGPer100ML || beverage.KcalPer100ML != builtin.KcalPer100ML {
+			t.Errorf("Built-in %s differs from BuiltinBeverages: %+v", beverage.Name, beverage)
+		}
+	}
+
+	alice, bob := uint(1), uint(2)
+	own := models.Beverage{UserID: &alice, Name: "Aaa oat latte", HydrationFactor: 0.9, CaffeineMGPer100ML: 30, KcalPer100ML: 50}
+	if err := s.Beverages.Create(&own); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	other := models.Beverage{UserID: &bob, Name: "Kombucha", HydrationFactor: 1}
+	s.Beverages.Create(&other)
+
+	catalog, _ = s.Beverages.List(1)
+	if len(catalog) != len(builtins)+1 || catalog[0].ID != own.ID {
+		t.Errorf("Expected the user's beverage listed by name with the built-ins, got %+v", catalog)
+	}
+	if got, err := s.Beverages.Get(1, own.ID); err != nil || got.Name != own.Name {
+		t.Errorf("Get: got %+v, %v", got, err)
+	}
+	if got, err := s.Beverages.Get(1, catalog[1].ID); err != nil || got.UserID != nil {
+		t.Errorf("Expected a built-in beverage, got %+v, %v", got, err)
+	}
+	if _, err := s.Beverages.Get(1, other.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected another user's beverage to be hidden, got %v", err)
+	}
+
+	if err := s.Beverages.Delete(1, catalog[1].ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected a built-in beverage not to be deleted, got %v", err)
+	}
+	if err := s.Beverages.Delete(1, other.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected deleting another user's beverage to fail, got %v", err)
+	}
+	if err := s.Beverages.Delete(1, own.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Beverages.Get(1, own.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected the deleted beverage to be gone, got %v", err)
+	}
+}
+
+func testReminders(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	interval := models.ReminderRule{UserID: 1, Kind: models.ReminderInterval, Enabled: true, StartTime: "08:00", EndTime: "22:00", IntervalMinutes: 90}
+	pace := models.ReminderRule{UserID: 1, Kind: models.ReminderBehindPace, Enabled: false, StartTime: "07:00", EndTime: "21:00", IntervalMinutes: 60, MarginPercent: 10}
+	other := models.ReminderRule{UserID: 2, Kind: models.ReminderInterval, Enabled: true, StartTime: "09:00", EndTime: "17:00", IntervalMinutes: 30}
+	for _, rule := range []*models.ReminderRule{&interval, &pace, &other} {
+		if err := s.Reminders.Create(rule); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	rules, err := s.Reminders.List(1)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(rules) != 2 || rules[0].ID != interval.ID || rules[1].Enabled {
+		t.Errorf("Expected user 1's two rules, oldest first, got %+v", rules)
+	}
+	if _, err := s.Reminders.Get(1, other.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected another user's rule to be hidden, got %v", err)
+	}
+
+	enabled, err := s.Reminders.Enabled()
+	if err != nil {
+		t.Fatalf("Enabled: %v", err)
+	}
+	if len(enabled) != 2 || enabled[0].ID != interval.ID || enabled[1].ID != other.ID {
+		t.Errorf("Expected both users' enabled rules, got %+v", enabled)
+	}
+
+	sentAt := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	if claimed, err := s.Reminders.Claim(interval.ID, nil, sentAt); err != nil || !claimed {
+		t.Fatalf("Claim: %v %v", claimed, err)
+	}
+	// an update made from an older copy keeps the time the rule last sent
+	interval.IntervalMinutes = 120
+	interval.Enabled = false
+	if err := s.Reminders.Update(&interval); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, _ := s.Reminders.Get(1, interval.ID)
+	if got.IntervalMinutes != 120 || got.Enabled || got.LastSentAt == nil || !got.LastSentAt.Equal(sentAt) {
+		t.Errorf("Expected the update to keep LastSentAt, got %+v", got)
+	}
+
+	stolen := other
+	stolen.UserID = 1
+	if err := s.Reminders.Update(&stolen); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected updating another user's rule to fail, got %v", err)
+	}
+	if err := s.Reminders.Delete(1, other.ID); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected deleting another user's rule to fail, got %v", err)
+	}
+	if err := s.Reminders.Delete(1, pace.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if rules, _ := s.Reminders.List(1); len(rules) != 1 {
+		t.Errorf("Expected one rule left, got %+v", rules)
+	}
+}
+
+// testReminderClaims has two schedulers race for the same reminder, as
+// every server instance does
+func testReminderClaims(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	rule := models.ReminderRule{UserID: 1, Kind: models.ReminderInterval, Enabled: true, StartTime: "08:00", EndTime: "22:00", IntervalMinutes: 60}
+	if err := s.Reminders.Create(&rule); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// both read the rule before either claims it
+	first := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	var won atomic.Int32
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			claimed, err := s.Reminders.Claim(rule.ID, rule.LastSentAt, first)
+			if err != nil {
+				t.Errorf("Claim: %v", err)
+			}
+			if claimed {
+				won.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if won.Load() != 1 {
+		t.Fatalf("Expected exactly one claim to win, got %d", won.Load())
+	}
+
+	// the next run claims from the time that was stored, and only once
+	saved, err := s.Reminders.Get(1, rule.ID)
+	if err != nil || saved.LastSentAt == nil || !saved.LastSentAt.Equal(first) {
+		t.Fatalf("Expected the claim to be saved, got %+v %v", saved.LastSentAt, err)
+	}
+	next := first.Add(time.Hour)
+	if claimed, err := s.Reminders.Claim(rule.ID, saved.LastSentAt, next); err != nil || !claimed {
+		t.Errorf("Expected the next reminder to be claimed, got %v %v", claimed, err)
+	}
+	if claimed, err := s.Reminders.Claim(rule.ID, saved.LastSentAt, next); err != nil || claimed {
+		t.Errorf("Expected a stale claim to lose, got %v %v", claimed, err)
+	}
+	if claimed, err := s.Reminders.Claim(rule.ID+100, nil, next); err != nil || claimed {
+		t.Errorf("Expected claiming a missing rule to lose, got %v %v", claimed, err)
+	}
+}
+
+func testNotifications(t *testing.T, s store.Stores) {
+	createUsers(t, s)
+
+	for i, userID := range []uint{1, 1, 1, 2} {
+		notification := models.Notification{UserID: userID, Kind: models.NotificationHydrationReminder, Title: fmt.Sprintf("Reminder %d", i), Body: "Drink some water"}
+		if err := s.Notifications.Create(&notification); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	latest, err := s.Notifications.List(1, 2)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(latest) != 2 || latest[0].Title != "Reminder 2" || latest[1].Title != "Reminder 1" {
+		t.Errorf("Expected the 2 newest notifications, newest first, got %+v", latest)
+	}
+
+	readAt := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	if err := s.Notifications.MarkRead(1, latest[0].ID, readAt); err != nil {
+		t.Fatalf("MarkRead: %v", err)
+	}
+	if err := s.Notifications.MarkRead(1, latest[0].ID, readAt.Add(time.Hour)); err != nil {
+		t.Fatalf("MarkRead again: %v", err)
+	}
+	all, _ := s.Notifications.List(1, 10)
+	if len(all) != 3 || all[0].ReadAt == nil || !all[0].ReadAt.Equal(readAt) || all[1].ReadAt != nil {
+		t.Errorf("Expected only the newest notification read, at the first time, got %+v", all)
+	}
+
+	others, _ := s.Notifications.List(2, 10)
+	if err := s.Notifications.MarkRead(1, others[0].ID, readAt); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected another user's notification to be hidden, got %v", err)
+	}
+}
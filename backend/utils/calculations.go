@@ -8,10 +8,15 @@ import (
 
 // calculate age from date of birth
 func CalculateAge(dob *time.Time) int {
+	return CalculateAgeOn(dob, time.Now())
+}
+
+// CalculateAgeOn is the age on the calendar day of now, in now's location,
+// so a birthday starts at the user's midnight rather than UTC's
+func CalculateAgeOn(dob *time.Time, now time.Time) int {
 	if dob == nil {
 		return 0
 	}
-	now := time.Now()
 	age := now.Year() - dob.Year()
 
 	// adjust if birthday hasn't occurred this year yet
@@ -29,7 +34,7 @@ func CalculateBMI(weightKG, heightCM float64) float64 {
 		return 0
 	}
 	heightM := heightCM / 100
-	return roundToTwo(weightKG / (heightM * heightM))
+	return RoundToTwo(weightKG / (heightM * heightM))
 }
 
 // calculate Body Fat Percentage using Deurenberg formula
@@ -44,7 +49,7 @@ func CalculateBFP(bmi float64, age int, sex string) float64 {
 	}
 
 	bfp := (1.20 * bmi) + (0.23 * float64(age)) - (10.8 * sexFactor) - 5.4
-	return roundToTwo(bfp)
+	return RoundToTwo(bfp)
 }
 
 // calculate BMR (Basal Metabolic Rate) using Mifflin-St Jeor Equation
@@ -52,9 +57,9 @@ func CalculateBFP(bmi float64, age int, sex string) float64 {
 // female: (10 × weight) + (6.25 × height) - (5 × age) - 161
 func CalculateBMR(weightKG, heightCM float64, age int, sex string) float64 {
 	if sex == "male" {
-		return roundToTwo((10 * weightKG) + (6.25 * heightCM) - (5 * float64(age)) + 5)
+		return RoundToTwo((10 * weightKG) + (6.25 * heightCM) - (5 * float64(age)) + 5)
 	}
-	return roundToTwo((10 * weightKG) + (6.25 * heightCM) - (5 * float64(age)) - 161)
+	return RoundToTwo((10 * weightKG) + (6.25 * heightCM) - (5 * float64(age)) - 161)
 }
 
 // calculate TDEE (Total Daily Energy Expenditure)
@@ -73,12 +78,12 @@ func CalculateTDEE(bmr float64, activityLevel string) float64 {
 		multiplier = 1.2 // Default to sedentary
 	}
 
-	return roundToTwo(bmr * multiplier)
+	return RoundToTwo(bmr * multiplier)
 }
 
 // main function to calculate all stats from a health profile
 func CalculateStats(profile *models.HealthProfile) models.ProfileStats {
-	age := CalculateAge(profile.DateOfBirth)
+	age := ProfileAge(profile)
 	bmi := CalculateBMI(profile.WeightKG, profile.HeightCM)
 	bfp := CalculateBFP(bmi, age, profile.Sex)
 	bmr := CalculateBMR(profile.WeightKG, profile.HeightCM, age, profile.Sex)
@@ -93,8 +98,18 @@ func CalculateStats(profile *models.HealthProfile) models.ProfileStats {
 	}
 }
 
-// helper function to round to 2 decimal places
-func roundToTwo(val float64) float64 {
+// ProfileAge is the age today in the profile's time zone, or in UTC if it
+// has none or an unknown one
+func ProfileAge(profile *models.HealthProfile) int {
+	loc, err := LoadTimeZone(profile.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return CalculateAgeOn(profile.DateOfBirth, time.Now().In(loc))
+}
+
+// RoundToTwo rounds to 2 decimal places
+func RoundToTwo(val float64) float64 {
 	return float64(int(val*100+0.5)) / 100
 }
 
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"math"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+)
+
+const (
+	// DefaultWaterGoalML is the base goal when there is no profile weight
+	DefaultWaterGoalML = 2000
+	// MinWaterGoalML and MaxWaterGoalML bound the automatic goal
+	MinWaterGoalML = 1000
+	MaxWaterGoalML = 6000
+
+	// waterMLPerKG is the usual 35 ml per kg of body weight
+	waterMLPerKG = 35
+	// waterMLPerExerciseMinute replaces roughly 0.7 l lost per hour of exercise
+	waterMLPerExerciseMinute = 12
+
+	// DefaultCaffeineLimitMG is the daily amount considered safe for most
+	// adults
+	DefaultCaffeineLimitMG = 400
+)
+
+// extra water for the profile's activity level
+var activityWaterML = map[string]int{
+	"sedentary":   0,
+	"light":       250,
+	"moderate":    500,
+	"active":      750,
+	"very_active": 1000,
+}
+
+// CalculateWaterGoal works out the automatic daily water goal from the
+// profile, which may be nil, and the minutes exercised that day
+func CalculateWaterGoal(profile *models.HealthProfile, exerciseMinutes int) (int, models.WaterGoalBreakdown) {
+	breakdown := models.WaterGoalBreakdown{
+		BaseML:          DefaultWaterGoalML,
+		ExerciseMinutes: exerciseMinutes,
+		ExerciseML:      exerciseMinutes * waterMLPerExerciseMinute,
+	}
+	if profile != nil {
+		if profile.WeightKG > 0 {
+			breakdown.BaseML = int(math.Round(profile.WeightKG * waterMLPerKG))
+		}
+		breakdown.ActivityML = activityWaterML[profile.ActivityLevel]
+	}
+
+	goal := breakdown.BaseML + breakdown.ActivityML + breakdown.ExerciseML
+	return min(max(goal, MinWaterGoalML), MaxWaterGoalML), breakdown
+}
+
+// BeverageIntake is what amountML of a beverage adds to the day. A nil
+// beverage is plain water.
+func BeverageIntake(amountML int, beverage *models.Beverage) (hydrationML int, caffeineMG, kcal float64) {
+	if beverage == nil {
+		return amountML, 0, 0
+	}
+	hydrationML = int(math.Round(float64(amountML) * beverage.HydrationFactor))
+	caffeineMG = RoundToTwo(float64(amountML) * beverage.CaffeineMGPer100ML / 100)
+	kcal = RoundToTwo(float64(amountML) * beverage.KcalPer100ML / 100)
+	return hydrationML, caffeineMG, kcal
+}
+
+// ScaleIntake is what amountML of a logged drink adds to the day, at the
+// rates recorded in the log rather than the catalog's current ones
+func ScaleIntake(log models.WaterIntake, amountML int) (hydrationML int, caffeineMG, kcal float64) {
+	if log.AmountML <= 0 {
+		return amountML, 0, 0
+	}
+	scale := float64(amountML) / float64(log.AmountML)
+	hydrationML = int(math.Round(float64(log.HydrationML) * scale))
+	return hydrationML, RoundToTwo(log.CaffeineMG * scale), RoundToTwo(log.Kcal * scale)
+}
+
+// CaffeineLimit is the profile's daily caffeine limit, or the default for
+// a nil profile or one without a limit
+func CaffeineLimit(profile *models.HealthProfile) int {
+	if profile == nil || profile.CaffeineLimitMG == nil {
+		return DefaultCaffeineLimitMG
+	}
+	return *profile.CaffeineLimitMG
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/models"
+)
+
+func TestCalculateWaterGoal(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  *models.HealthProfile
+		minutes  int
+		expected int
+	}{
+		{"no profile", nil, 0, 2000},
+		{"no profile, exercise", nil, 30, 2360},
+		{"weight only", &models.HealthProfile{WeightKG: 70}, 0, 2450},
+		{"weight and activity", &models.HealthProfile{WeightKG: 70, ActivityLevel: "moderate"}, 0, 2950},
+		{"weight, activity and exercise", &models.HealthProfile{WeightKG: 70, ActivityLevel: "active"}, 45, 3740},
+		{"unknown activity level", &models.HealthProfile{WeightKG: 60, ActivityLevel: "other"}, 0, 2100},
+		{"clamped low", &models.HealthProfile{WeightKG: 20}, 0, MinWaterGoalML},
+		{"clamped high", &models.HealthProfile{WeightKG: 150, ActivityLevel: "very_active"}, 120, MaxWaterGoalML},
+	}
+
+	for _, tt := range tests {
+		goal, breakdown := CalculateWaterGoal(tt.profile, tt.minutes)
+		if goal != tt.expected {
+			t.Errorf("%s: CalculateWaterGoal = %d; want %d (%+v)", tt.name, goal, tt.expected, breakdown)
+		}
+		if breakdown.ExerciseMinutes != tt.minutes {
+			t.Errorf("%s: breakdown has %d exercise minutes; want %d", tt.name, breakdown.ExerciseMinutes, tt.minutes)
+		}
+	}
+}
+
+func TestBeverageIntake(t *testing.T) {
+	coffee := &models.Beverage{Name: "Coffee", HydrationFactor: 0.9, CaffeineMGPer100ML: 40, KcalPer100ML: 2}
+	tests := []struct {
+		name      string
+		amount    int
+		beverage  *models.Beverage
+		hydration int
+		caffeine  float64
+		kcal      float64
+	}{
+		{"plain water", 250, nil, 250, 0, 0},
+		{"coffee", 250, coffee, 225, 100, 5},
+		{"rounded", 333, coffee, 300, 133.2, 6.66},
+		{"milk", 200, &models.Beverage{HydrationFactor: 1.5, KcalPer100ML: 61}, 300, 0, 122},
+	}
+
+	for _, tt := range tests {
+		hydration, caffeine, kcal := BeverageIntake(tt.amount, tt.beverage)
+		if hydration != tt.hydration || caffeine != tt.caffeine || kcal != tt.kcal {
+			t.Errorf("%s: BeverageIntake = %d, %v, %v; want %d, %v, %v", tt.name, hydration, caffeine, kcal, tt.hydration, tt.caffeine, tt.kcal)
+		}
+	}
+}
+
+func TestScaleIntake(t *testing.T) {
+	coffee := models.WaterIntake{AmountML: 250, HydrationML: 225, CaffeineMG: 100, Kcal: 5}
+	if hydration, caffeine, kcal := ScaleIntake(coffee, 500); hydration != 450 || caffeine != 200 || kcal != 10 {
+		t.Errorf("ScaleIntake(coffee, 500) = %d, %v, %v; want 450, 200, 10", hydration, caffeine, kcal)
+	}
+	if hydration, caffeine, kcal := ScaleIntake(coffee, 333); hydration != 300 || caffeine != 133.2 || kcal != 6.66 {
+		t.Errorf("ScaleIntake(coffee, 333) = %d, %v, %v; want 300, 133.2, 6.66", hydration, caffeine, kcal)
+	}
+	water := models.WaterIntake{AmountML: 250, HydrationML: 250}
+	if hydration, _, _ := ScaleIntake(water, 300); hydration != 300 {
+		t.Errorf("Expected plain water to stay fully hydrating, got %d", hydration)
+	}
+}
@@ -2,39 +2,162 @@ package utils
 
 import (
 	"errors"
+	"fmt"
 	"time"
+
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+)
+
+// signing keys and lifetimes; replaced by ConfigureJWT at startup
+var (
+	jwtKeys         = mustKeySet(config.Default().JWT)
+	accessTokenTTL  = config.Default().JWT.AccessTokenTTL.Std()
+	refreshTokenTTL = config.Default().JWT.RefreshTokenTTL.Std()
 )
 
-var jwtSecret = []byte("temp-secret-for-testing")
+// ConfigureJWT loads the signing keys and token lifetimes; call it once at
+// startup
+func ConfigureJWT(cfg config.JWTConfig) error {
+	keys, err := NewKeySet(cfg)
+	if err != nil {
+		return err
+	}
+	jwtKeys = keys
+	accessTokenTTL = cfg.AccessTokenTTL.Std()
+	refreshTokenTTL = cfg.RefreshTokenTTL.Std()
+	return nil
+}
+
+// AccessTokenTTL is how long tokens from GenerateToken stay valid
+func AccessTokenTTL() time.Duration {
+	return accessTokenTTL
+}
+
+// RefreshTokenTTL is how long a refresh token can be redeemed
+func RefreshTokenTTL() time.Duration {
+	return refreshTokenTTL
+}
 
+// PublicJWKS returns the published verification keys
+func PublicJWKS() JWKS {
+	return jwtKeys.JWKS()
+}
+
+// iat/exp are written with microsecond precision so a logout-all cutoff
+// can tell tokens issued in the same second apart
+func init() {
+	jwt.TimePrecision = time.Microsecond
+}
+
+func mustKeySet(cfg config.JWTConfig) *KeySet {
+	keys, err := NewKeySet(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return keys
+}
+
+// Claims carries the token ID in RegisteredClaims.ID ("jti") so a single
+// token can be revoked, and the refresh-token family it was issued for in
+// SessionID so logging out can end the whole session.
+//
+// Type is empty for access tokens. Other kinds of token (such as the MFA
+// challenge handed out between the two login steps) set it, and the auth
+// middleware refuses them.
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    uint   `json:"user_id"`
+	Email     string `json:"email"`
+	SessionID string `json:"sid,omitempty"`
+	Role      string `json:"role,omitempty"`
+	Type      string `json:"typ,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// TokenTypeMFAChallenge marks a token that only proves the password was
+// right; it can be exchanged for a session with a second factor
+const TokenTypeMFAChallenge = "mfa_challenge"
+
+// MFAChallengeTTL is how long the user has to enter their second factor
+const MFAChallengeTTL = 5 * time.Minute
+
 // for testing
 func GenerateToken(userID uint, email string) (string, error) {
+	return GenerateSessionToken(userID, email, "", "")
+}
+
+// GenerateSessionToken issues an access token tied to a login session. An
+// empty role means an ordinary user.
+func GenerateSessionToken(userID uint, email string, role string, sessionID string) (string, error) {
+	jti, err := NewID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := &Claims{
+		UserID:    userID,
+		Email:     email,
+		SessionID: sessionID,
+		Role:      role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	return signClaims(claims)
+}
+
+// GenerateMFAChallengeToken issues the short-lived token Login returns in
+// place of a session when the user has two-factor authentication enabled
+func GenerateMFAChallengeToken(userID uint, email string) (string, error) {
+	jti, err := NewID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
 	claims := &Claims{
 		UserID: userID,
 		Email:  email,
+		Type:   TokenTypeMFAChallenge,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(MFAChallengeTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return signClaims(claims)
+}
+
+// ValidateMFAChallengeToken checks a token from GenerateMFAChallengeToken
+func ValidateMFAChallengeToken(tokenString string) (*Claims, error) {
+	claims, err := ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != TokenTypeMFAChallenge {
+		return nil, errors.New("not an mfa challenge token")
+	}
+	return claims, nil
+}
+
+// signClaims signs with the active key and names it in the kid header
+func signClaims(claims *Claims) (string, error) {
+	key := jwtKeys.Active()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Sign)
 }
 
 func ValidateToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, lookupVerifyKey)
 
 	if err != nil {
 		return nil, err
@@ -45,4 +168,26 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	}
 
 	return claims, nil
-}
\ No newline at end of file
+}
+
+// lookupVerifyKey picks the key named by the token's kid header. Tokens
+// issued before key IDs existed carry no kid and are checked against the
+// default key. The algorithm must match the key's to prevent an attacker
+// from, say, presenting an RSA public key as an HMAC secret.
+func lookupVerifyKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		kid = config.DefaultKeyID
+	}
+
+	key, ok := jwtKeys.Lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+
+	return key.Verify, nil
+}
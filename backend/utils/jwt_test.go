@@ -0,0 +1,205 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+)
+
+// useKeys swaps the package key set for the duration of a test
+func useKeys(t *testing.T, cfg config.JWTConfig) {
+	previous := jwtKeys
+	t.Cleanup(func() { jwtKeys = previous })
+
+	if err := ConfigureJWT(cfg); err != nil {
+		t.Fatalf("ConfigureJWT returned error: %v", err)
+	}
+}
+
+// keyConfig keeps the default token lifetimes while replacing the keys
+func keyConfig(active string, keys ...config.JWTKeyConfig) config.JWTConfig {
+	cfg := config.Default().JWT
+	cfg.ActiveKey = active
+	cfg.Keys = keys
+	return cfg
+}
+
+func writePrivateKeyPEM(t *testing.T, key interface{}) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "key.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	return path
+}
+
+func TestGenerateToken_SetsKeyID(t *testing.T) {
+	useKeys(t, config.Default().JWT)
+
+	tokenString, err := GenerateToken(1, "testuser")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+	if err != nil {
+		t.Fatalf("Failed to parse token: %v", err)
+	}
+	if token.Header["kid"] != config.DefaultKeyID {
+		t.Errorf("Expected kid %q, got %v", config.DefaultKeyID, token.Header["kid"])
+	}
+}
+
+func TestValidateToken_AcceptsRetiredKey(t *testing.T) {
+	oldKey := config.JWTKeyConfig{ID: "2026-01", Secret: "old-secret"}
+	newKey := config.JWTKeyConfig{ID: "2026-07", Secret: "new-secret"}
+
+	// sign with the old key
+	useKeys(t, keyConfig("2026-01", oldKey))
+	oldToken, err := GenerateToken(7, "testuser")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+
+	// rotate: new key signs, old key is retired but still verifies
+	useKeys(t, keyConfig("2026-07", newKey, oldKey))
+	claims, err := ValidateToken(oldToken)
+	if err != nil {
+		t.Fatalf("Expected token signed with retired key to validate, got %v", err)
+	}
+	if claims.UserID != 7 {
+		t.Errorf("Expected user ID 7, got %d", claims.UserID)
+	}
+
+	// once the old key is dropped the token is rejected
+	useKeys(t, keyConfig("2026-07", newKey))
+	if _, err := ValidateToken(oldToken); err == nil {
+		t.Error("Expected token signed with removed key to be rejected")
+	}
+}
+
+func TestValidateToken_LegacyTokenWithoutKeyID(t *testing.T) {
+	useKeys(t, config.Default().JWT)
+
+	claims := &Claims{UserID: 3}
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.DevJWTSecret))
+	if err != nil {
+		t.Fatalf("Failed to sign legacy token: %v", err)
+	}
+
+	if _, err := ValidateToken(legacy); err != nil {
+		t.Errorf("Expected legacy token to validate against default key, got %v", err)
+	}
+}
+
+func TestValidateToken_RS256(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	useKeys(t, keyConfig("rsa-1",
+		config.JWTKeyConfig{ID: "rsa-1", Algorithm: "RS256", PrivateKeyFile: writePrivateKeyPEM(t, private)},
+	))
+
+	token, err := GenerateToken(11, "testuser")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+	claims, err := ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken returned error: %v", err)
+	}
+	if claims.UserID != 11 {
+		t.Errorf("Expected user ID 11, got %d", claims.UserID)
+	}
+
+	jwks := PublicJWKS()
+	if len(jwks.Keys) != 1 || jwks.Keys[0].KeyType != "RSA" || jwks.Keys[0].KeyID != "rsa-1" {
+		t.Errorf("Unexpected JWKS %+v", jwks)
+	}
+}
+
+func TestValidateToken_EdDSA(t *testing.T) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+	useKeys(t, keyConfig("ed-1",
+		config.JWTKeyConfig{ID: "ed-1", Algorithm: "EdDSA", PrivateKeyFile: writePrivateKeyPEM(t, private)},
+		config.JWTKeyConfig{ID: "hs-old", Secret: "old-secret"},
+	))
+
+	token, err := GenerateToken(12, "testuser")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+	if _, err := ValidateToken(token); err != nil {
+		t.Fatalf("ValidateToken returned error: %v", err)
+	}
+
+	// HMAC secrets are never published
+	jwks := PublicJWKS()
+	if len(jwks.Keys) != 1 || jwks.Keys[0].KeyType != "OKP" || jwks.Keys[0].Curve != "Ed25519" {
+		t.Errorf("Unexpected JWKS %+v", jwks)
+	}
+}
+
+func TestValidateToken_RejectsAlgorithmMismatch(t *testing.T) {
+	useKeys(t, keyConfig("hs", config.JWTKeyConfig{ID: "hs", Secret: "secret"}))
+
+	// token claims to be HS384 while the key is HS256
+	token := jwt.NewWithClaims(jwt.SigningMethodHS384, &Claims{UserID: 1})
+	token.Header["kid"] = "hs"
+	tokenString, err := token.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	if _, err := ValidateToken(tokenString); err == nil {
+		t.Error("Expected token with mismatched algorithm to be rejected")
+	}
+}
+
+func TestGenerateToken_UsesAccessTokenTTL(t *testing.T) {
+	cfg := config.Default().JWT
+	cfg.AccessTokenTTL = config.Duration(5 * time.Minute)
+	useKeys(t, cfg)
+
+	token, err := GenerateToken(1, "testuser")
+	if err != nil {
+		t.Fatalf("GenerateToken returned error: %v", err)
+	}
+	claims, err := ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken returned error: %v", err)
+	}
+
+	lifetime := claims.ExpiresAt.Sub(claims.IssuedAt.Time)
+	if lifetime < 5*time.Minute-time.Millisecond || lifetime > 5*time.Minute+time.Millisecond {
+		t.Errorf("Expected 5 minute lifetime, got %v", lifetime)
+	}
+}
+
+func TestNewKeySet_UnknownActiveKey(t *testing.T) {
+	_, err := NewKeySet(config.JWTConfig{
+		ActiveKey: "missing",
+		Keys:      []config.JWTKeyConfig{{ID: "hs", Secret: "secret"}},
+	})
+	if err == nil {
+		t.Error("Expected error for unknown active key")
+	}
+}
@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+)
+
+// SigningKey is one entry of the key set. Sign is nil for verify-only keys.
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	Sign   interface{}
+	Verify interface{}
+}
+
+// KeySet holds the active signing key plus any retired keys that are still
+// accepted for verification, indexed by key ID ("kid").
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// NewKeySet builds a key set from the JWT configuration, reading PEM files
+// for asymmetric keys.
+func NewKeySet(cfg config.JWTConfig) (*KeySet, error) {
+	keyConfigs, activeID := cfg.SigningKeys()
+
+	ks := &KeySet{keys: make(map[string]*SigningKey)}
+	for _, kc := range keyConfigs {
+		key, err := loadSigningKey(kc)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", kc.ID, err)
+		}
+		ks.keys[key.ID] = key
+	}
+
+	active, ok := ks.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("active jwt key %q is not configured", activeID)
+	}
+	if active.Sign == nil {
+		return nil, fmt.Errorf("active jwt key %q has no private key", activeID)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+func loadSigningKey(kc config.JWTKeyConfig) (*SigningKey, error) {
+	key := &SigningKey{ID: kc.ID}
+
+	switch kc.Algorithm {
+	case "", "HS256":
+		key.Method = jwt.SigningMethodHS256
+		key.Sign = []byte(kc.Secret)
+		key.Verify = []byte(kc.Secret)
+
+	case "RS256":
+		key.Method = jwt.SigningMethodRS256
+		if kc.PrivateKeyFile != "" {
+			pemBytes, err := os.ReadFile(kc.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+			if err != nil {
+				return nil, err
+			}
+			key.Sign = private
+			key.Verify = &private.PublicKey
+		} else {
+			pemBytes, err := os.ReadFile(kc.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			public, err := jwt.ParseRSAPublicKeyFromPEM(pemBytes)
+			if err != nil {
+				return nil, err
+			}
+			key.Verify = public
+		}
+
+	case "EdDSA":
+		key.Method = jwt.SigningMethodEdDSA
+		if kc.PrivateKeyFile != "" {
+			pemBytes, err := os.ReadFile(kc.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			private, err := jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+			if err != nil {
+				return nil, err
+			}
+			key.Sign = private
+			key.Verify = private.(ed25519.PrivateKey).Public()
+		} else {
+			pemBytes, err := os.ReadFile(kc.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			public, err := jwt.ParseEdPublicKeyFromPEM(pemBytes)
+			if err != nil {
+				return nil, err
+			}
+			key.Verify = public
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", kc.Algorithm)
+	}
+
+	return key, nil
+}
+
+// Active returns the key used to sign new tokens
+func (ks *KeySet) Active() *SigningKey {
+	return ks.active
+}
+
+// Lookup finds a key by ID
+func (ks *KeySet) Lookup(kid string) (*SigningKey, bool) {
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// JWK is a public key in RFC 7517 form
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n,omitempty"`   // RSA modulus
+	E         string `json:"e,omitempty"`   // RSA exponent
+	Curve     string `json:"crv,omitempty"` // OKP curve
+	X         string `json:"x,omitempty"`   // OKP public key
+}
+
+// JWKS is the document served at /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public halves of the asymmetric keys. HS256 secrets are
+// never published.
+func (ks *KeySet) JWKS() JWKS {
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	set := JWKS{Keys: []JWK{}}
+	for _, id := range ids {
+		key := ks.keys[id]
+		switch public := key.Verify.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     key.ID,
+				Algorithm: key.Method.Alg(),
+				Use:       "sig",
+				N:         base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     key.ID,
+				Algorithm: key.Method.Alg(),
+				Use:       "sig",
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+	return set
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"sync"
+	"time"
+
+	"github.com/charlesrclark1243/FitnessTrackerApp-SWE-Spring2026/backend/config"
+)
+
+// LockoutPolicy controls how quickly repeated login failures are slowed
+// down. The first FreeAttempts failures cost nothing; each failure after
+// that locks the key for BaseDelay, doubling every time up to MaxDelay.
+// A key is forgotten ResetAfter its last failure.
+type LockoutPolicy struct {
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	ResetAfter   time.Duration
+}
+
+// Lockout describes a lock placed on a username or client IP
+type Lockout struct {
+	Scope    string // "username" or "ip"
+	Key      string
+	Failures int
+	Until    time.Time
+}
+
+type attemptState struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// LoginThrottle tracks failed logins per username and per client IP in
+// memory. Tracking usernames stops guessing one account's password;
+// tracking IPs stops one client from spraying many accounts.
+type LoginThrottle struct {
+	mu         sync.Mutex
+	userPolicy LockoutPolicy
+	ipPolicy   LockoutPolicy
+	users      map[string]*attemptState
+	ips        map[string]*attemptState
+	now        func() time.Time
+}
+
+func NewLoginThrottle(userPolicy, ipPolicy LockoutPolicy) *LoginThrottle {
+	return &LoginThrottle{
+		userPolicy: userPolicy,
+		ipPolicy:   ipPolicy,
+		users:      make(map[string]*attemptState),
+		ips:        make(map[string]*attemptState),
+		now:        time.Now,
+	}
+}
+
+// Check returns how long the caller must wait before trying again, or 0
+// if the attempt may go ahead
+func (t *LoginThrottle) Check(username, ip string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	wait := time.Duration(0)
+	if state, ok := t.users[username]; ok && state.lockedUntil.After(now) {
+		wait = state.lockedUntil.Sub(now)
+	}
+	if state, ok := t.ips[ip]; ok && state.lockedUntil.After(now) && state.lockedUntil.Sub(now) > wait {
+		wait = state.lockedUntil.Sub(now)
+	}
+	return wait
+}
+
+// Failure records a failed attempt and returns any lockouts it caused
+func (t *LoginThrottle) Failure(username, ip string) []Lockout {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	var lockouts []Lockout
+	if lockout, locked := recordFailure(t.users, username, t.userPolicy, now); locked {
+		lockout.Scope = "username"
+		lockouts = append(lockouts, lockout)
+	}
+	if lockout, locked := recordFailure(t.ips, ip, t.ipPolicy, now); locked {
+		lockout.Scope = "ip"
+		lockouts = append(lockouts, lockout)
+	}
+	return lockouts
+}
+
+// Success clears the username's failures. The IP's history is kept, so a
+// client cannot wipe it by logging into an account of its own.
+func (t *LoginThrottle) Success(username string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.users, username)
+}
+
+// Prune forgets keys whose last failure is older than their policy's
+// ResetAfter
+func (t *LoginThrottle) Prune(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	pruneAttempts(t.users, t.userPolicy, now)
+	pruneAttempts(t.ips, t.ipPolicy, now)
+}
+
+func recordFailure(states map[string]*attemptState, key string, policy LockoutPolicy, now time.Time) (Lockout, bool) {
+	state, ok := states[key]
+	if !ok || now.Sub(state.lastFailure) > policy.ResetAfter {
+		state = &attemptState{}
+		states[key] = state
+	}
+
+	state.failures++
+	state.lastFailure = now
+
+	excess := state.failures - policy.FreeAttempts
+	if excess <= 0 {
+		return Lockout{}, false
+	}
+
+	delay := policy.BaseDelay
+	for i := 1; i < excess && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+
+	state.lockedUntil = now.Add(delay)
+	return Lockout{Key: key, Failures: state.failures, Until: state.lockedUntil}, true
+}
+
+func pruneAttempts(states map[string]*attemptState, policy LockoutPolicy, now time.Time) {
+	for key, state := range states {
+		if now.Sub(state.lastFailure) > policy.ResetAfter && !state.lockedUntil.After(now) {
+			delete(states, key)
+		}
+	}
+}
+
+// NewLoginThrottleFromConfig builds a throttle from the lockout settings
+func NewLoginThrottleFromConfig(cfg config.LockoutConfig) *LoginThrottle {
+	policy := LockoutPolicy{
+		FreeAttempts: cfg.FreeAttempts,
+		BaseDelay:    cfg.BaseDelay.Std(),
+		MaxDelay:     cfg.MaxDelay.Std(),
+		ResetAfter:   cfg.ResetAfter.Std(),
+	}
+	ipPolicy := policy
+	ipPolicy.FreeAttempts = cfg.IPFreeAttempts
+	return NewLoginThrottle(policy, ipPolicy)
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func testThrottle(start time.Time) (*LoginThrottle, *time.Time) {
+	now := start
+	throttle := NewLoginThrottle(
+		LockoutPolicy{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: 8 * time.Second, ResetAfter: time.Minute},
+		LockoutPolicy{FreeAttempts: 10, BaseDelay: time.Second, MaxDelay: 8 * time.Second, ResetAfter: time.Minute},
+	)
+	throttle.now = func() time.Time { return now }
+	return throttle, &now
+}
+
+func TestLoginThrottle_FreeAttempts(t *testing.T) {
+	throttle, _ := testThrottle(time.Now())
+
+	for i := 0; i < 3; i++ {
+		if lockouts := throttle.Failure("alice", "10.0.0.1"); len(lockouts) != 0 {
+			t.Fatalf("Failure %d: unexpected lockout %+v", i+1, lockouts)
+		}
+	}
+	if wait := throttle.Check("alice", "10.0.0.1"); wait != 0 {
+		t.Errorf("Expected no wait, got %v", wait)
+	}
+}
+
+func TestLoginThrottle_ExponentialBackoff(t *testing.T) {
+	throttle, now := testThrottle(time.Now())
+	for i := 0; i < 3; i++ {
+		throttle.Failure("alice", "10.0.0.1")
+	}
+
+	for _, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second} {
+		lockouts := throttle.Failure("alice", "10.0.0.1")
+		if len(lockouts) != 1 || lockouts[0].Scope != "username" {
+			t.Fatalf("Expected a username lockout, got %+v", lockouts)
+		}
+		if wait := throttle.Check("alice", "10.0.0.2"); wait != want {
+			t.Errorf("Expected wait %v, got %v", want, wait)
+		}
+		*now = now.Add(want)
+	}
+}
+
+func TestLoginThrottle_IPLockoutAcrossUsernames(t *testing.T) {
+	throttle, _ := testThrottle(time.Now())
+
+	var lockouts []Lockout
+	for i := 0; i < 11; i++ {
+		lockouts = throttle.Failure("user"+string(rune('a'+i)), "10.0.0.1")
+	}
+	if len(lockouts) != 1 || lockouts[0].Scope != "ip" || lockouts[0].Key != "10.0.0.1" {
+		t.Fatalf("Expected an ip lockout, got %+v", lockouts)
+	}
+	if wait := throttle.Check("someoneelse", "10.0.0.1"); wait == 0 {
+		t.Error("Expected the IP to be locked for every username")
+	}
+	if wait := throttle.Check("someoneelse", "10.0.0.2"); wait != 0 {
+		t.Errorf("Expected other IPs to be unaffected, got %v", wait)
+	}
+}
+
+func TestLoginThrottle_SuccessAndReset(t *testing.T) {
+	throttle, now := testThrottle(time.Now())
+
+	for i := 0; i < 3; i++ {
+		throttle.Failure("alice", "10.0.0.1")
+	}
+	throttle.Success("alice")
+	if lockouts := throttle.Failure("alice", "10.0.0.1"); len(lockouts) != 0 {
+		t.Errorf("Expected success to clear failures, got %+v", lockouts)
+	}
+
+	for i := 0; i < 3; i++ {
+		throttle.Failure("bob", "10.0.0.3")
+	}
+	*now = now.Add(2 * time.Minute)
+	if lockouts := throttle.Failure("bob", "10.0.0.3"); len(lockouts) != 0 {
+		t.Errorf("Expected failures to expire after ResetAfter, got %+v", lockouts)
+	}
+
+	*now = now.Add(2 * time.Minute)
+	throttle.Prune(*now)
+	if len(throttle.users) != 0 || len(throttle.ips) != 0 {
+		t.Errorf("Expected prune to forget idle keys, have %d users and %d ips", len(throttle.users), len(throttle.ips))
+	}
+}
@@ -1,8 +1,9 @@
 package utils
 
 import (
-	"golang.org/x/crypto/bcrypt"
+	"sync"
 
+	"golang.org/x/crypto/bcrypt"
 )
 
 func HashPassword(password string) (string, error) {
@@ -12,4 +13,19 @@ func HashPassword(password string) (string, error) {
 
 func CheckPasswordHash(hash, password string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
-}
\ No newline at end of file
+}
+
+var dummyHash struct {
+	once sync.Once
+	hash string
+}
+
+// SimulatePasswordCheck does the same bcrypt work as CheckPasswordHash for
+// a user that does not exist, so response times do not reveal which
+// usernames are registered
+func SimulatePasswordCheck(password string) {
+	dummyHash.once.Do(func() {
+		dummyHash.hash, _ = HashPassword("dummy-password-for-timing")
+	})
+	CheckPasswordHash(dummyHash.hash, password)
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"strings"
+	"time"
+)
+
+// PersonalTokenPrefix starts every personal access token, which is how
+// AuthMiddleware tells them apart from JWTs and how secret scanners can
+// spot a leaked one
+const PersonalTokenPrefix = "ftpat_"
+
+// PersonalTokenResources are the route groups a personal access token can
+// be scoped to, each with a ":read" and a ":write" scope
+var PersonalTokenResources = []string{"profile", "water", "weight", "exercise", "notifications"}
+
+// PersonalToken is an authenticated personal access token
+type PersonalToken struct {
+	ID     uint
+	UserID uint
+	Scopes []string
+}
+
+// Allows reports whether the token was granted a scope
+func (t *PersonalToken) Allows(scope string) bool {
+	for _, granted := range t.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalTokenStore looks up personal access tokens. Authenticate returns
+// nil without an error for unknown, expired or otherwise unusable tokens.
+type PersonalTokenStore interface {
+	Authenticate(token string, now time.Time) (*PersonalToken, error)
+}
+
+// ValidScope reports whether a scope names a known resource and access
+// level, e.g. "water:write"
+func ValidScope(scope string) bool {
+	resource, access, ok := strings.Cut(scope, ":")
+	if !ok || (access != "read" && access != "write") {
+		return false
+	}
+	for _, known := range PersonalTokenResources {
+		if resource == known {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPersonalToken reports whether a bearer token is a personal access token
+func IsPersonalToken(token string) bool {
+	return strings.HasPrefix(token, PersonalTokenPrefix)
+}
+
+// GeneratePersonalToken returns a new personal access token and the hash to
+// store in its place
+func GeneratePersonalToken() (token string, hash string, err error) {
+	random, _, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+	token = PersonalTokenPrefix + random
+	return token, HashToken(token), nil
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimitPolicy is a token bucket: a client may burst up to Requests
+// calls, and the bucket refills at Requests per Period
+type RateLimitPolicy struct {
+	// Name keeps the buckets of different policies apart for the same key
+	Name     string
+	Requests int
+	Period   time.Duration
+}
+
+// refillInterval is how long one request takes to earn back
+func (p RateLimitPolicy) refillInterval() time.Duration {
+	return p.Period / time.Duration(p.Requests)
+}
+
+// RateLimitResult describes the state of a bucket after a Take
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until the next request would be allowed; zero
+	// when this one was
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// RateLimitStore keeps the token buckets. The memory store is enough for a
+// single instance; a shared store makes the limits hold across instances.
+type RateLimitStore interface {
+	// Take spends one token from key's bucket under policy if there is one
+	Take(key string, policy RateLimitPolicy, now time.Time) (RateLimitResult, error)
+	// Prune forgets buckets that have refilled completely
+	Prune(now time.Time) error
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // when the bucket is full again; used by Prune
+}
+
+// MemoryRateLimitStore keeps buckets in process memory
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*bucket)}
+}
+
+func (s *MemoryRateLimitStore) Take(key string, policy RateLimitPolicy, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key = policy.Name + ":" + key
+	capacity := float64(policy.Requests)
+	perToken := policy.refillInterval()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+float64(elapsed)/float64(perToken))
+		b.updated = now
+	}
+
+	result := RateLimitResult{}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration((capacity - b.tokens) * float64(perToken))
+	b.full = now.Add(result.Reset)
+	return result, nil
+}
+
+func (s *MemoryRateLimitStore) Prune(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, b := range s.buckets {
+		if !b.full.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+var testPolicy = RateLimitPolicy{Name: "test", Requests: 3, Period: 3 * time.Second}
+
+func TestMemoryRateLimitStore_Burst(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		result, _ := store.Take("alice", testPolicy, now)
+		if !result.Allowed || result.Remaining != 2-i {
+			t.Fatalf("Request %d: unexpected result %+v", i+1, result)
+		}
+	}
+
+	result, _ := store.Take("alice", testPolicy, now)
+	if result.Allowed {
+		t.Fatal("Expected the fourth request to be refused")
+	}
+	if result.RetryAfter != time.Second || result.Reset != 3*time.Second {
+		t.Errorf("Expected to retry after 1s and reset after 3s, got %+v", result)
+	}
+
+	// other keys and other policies have their own buckets
+	if result, _ := store.Take("bob", testPolicy, now); !result.Allowed {
+		t.Error("Expected another key to be allowed")
+	}
+	other := testPolicy
+	other.Name = "other"
+	if result, _ := store.Take("alice", other, now); !result.Allowed {
+		t.Error("Expected another policy to be allowed")
+	}
+}
+
+func TestMemoryRateLimitStore_Refill(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		store.Take("alice", testPolicy, now)
+	}
+
+	if result, _ := store.Take("alice", testPolicy, now.Add(999*time.Millisecond)); result.Allowed {
+		t.Error("Expected no token before a full interval")
+	}
+	if result, _ := store.Take("alice", testPolicy, now.Add(time.Second)); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("Expected one refilled token, got %+v", result)
+	}
+
+	// a long pause never earns more than the burst
+	later := now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if result, _ := store.Take("alice", testPolicy, later); !result.Allowed {
+			t.Fatalf("Request %d after refill was refused", i+1)
+		}
+	}
+	if result, _ := store.Take("alice", testPolicy, later); result.Allowed {
+		t.Error("Expected the bucket to hold no more than its capacity")
+	}
+}
+
+func TestMemoryRateLimitStore_Prune(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	now := time.Now()
+	store.Take("alice", testPolicy, now)
+
+	store.Prune(now.Add(500 * time.Millisecond))
+	if len(store.buckets) != 1 {
+		t.Fatal("Expected a bucket that is still refilling to be kept")
+	}
+	store.Prune(now.Add(time.Second))
+	if len(store.buckets) != 0 {
+		t.Error("Expected a full bucket to be pruned")
+	}
+}
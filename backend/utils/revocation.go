@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// RevocationStore remembers access tokens that were invalidated before
+// they expired. Entries only need to outlive the tokens they cover, so
+// Prune drops them once that is no longer possible.
+type RevocationStore interface {
+	// RevokeToken rejects the token with this jti until it expires
+	RevokeToken(jti string, userID uint, expiresAt time.Time) error
+	// RevokeUser rejects every token of the user issued before the given time
+	RevokeUser(userID uint, before time.Time) error
+	// IsRevoked reports whether a validated token must still be refused
+	IsRevoked(claims *Claims) (bool, error)
+	// Prune removes entries that can no longer match an unexpired token
+	Prune(now time.Time) error
+}
+
+// IssuedBefore reports whether a token predates a logout-all. Tokens carry
+// microsecond iat values, so the cutoff is compared at that precision; a
+// session issued right after a password change must not be caught by it.
+func IssuedBefore(claims *Claims, cutoff time.Time) bool {
+	if claims.IssuedAt == nil {
+		return true
+	}
+	return claims.IssuedAt.Time.Before(cutoff.Truncate(time.Microsecond))
+}
+
+// MemoryRevocationStore keeps revocations in process memory. It is the
+// default for tests and single-instance development servers.
+type MemoryRevocationStore struct {
+	mu     sync.Mutex
+	tokens map[string]time.Time // jti -> token expiry
+	users  map[uint]time.Time   // user ID -> revoked-before cutoff
+}
+
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		tokens: make(map[string]time.Time),
+		users:  make(map[uint]time.Time),
+	}
+}
+
+func (s *MemoryRevocationStore) RevokeToken(jti string, userID uint, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[jti] = expiresAt
+	return nil
+}
+
+func (s *MemoryRevocationStore) RevokeUser(userID uint, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if before.After(s.users[userID]) {
+		s.users[userID] = before
+	}
+	return nil
+}
+
+func (s *MemoryRevocationStore) IsRevoked(claims *Claims) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if claims.ID != "" {
+		if _, ok := s.tokens[claims.ID]; ok {
+			return true, nil
+		}
+	}
+	if cutoff, ok := s.users[claims.UserID]; ok && IssuedBefore(claims, cutoff) {
+		return true, nil
+	}
+	return false, nil
+}
+
+func (s *MemoryRevocationStore) Prune(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for jti, expiresAt := range s.tokens {
+		if now.After(expiresAt) {
+			delete(s.tokens, jti)
+		}
+	}
+	// every token issued before the cutoff has expired once a full
+	// access-token lifetime has passed
+	for userID, cutoff := range s.users {
+		if now.After(cutoff.Add(accessTokenTTL)) {
+			delete(s.users, userID)
+		}
+	}
+	return nil
+}
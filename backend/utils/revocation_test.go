@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func testClaims(userID uint, jti string, issuedAt time.Time) *Claims {
+	return &Claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:       jti,
+			IssuedAt: jwt.NewNumericDate(issuedAt),
+		},
+	}
+}
+
+func TestMemoryRevocationStore_RevokeToken(t *testing.T) {
+	store := NewMemoryRevocationStore()
+	now := time.Now()
+
+	store.RevokeToken("abc", 1, now.Add(time.Minute))
+
+	if revoked, _ := store.IsRevoked(testClaims(1, "abc", now)); !revoked {
+		t.Error("Expected revoked jti to be refused")
+	}
+	if revoked, _ := store.IsRevoked(testClaims(1, "def", now)); revoked {
+		t.Error("Expected other jti to be accepted")
+	}
+}
+
+func TestMemoryRevocationStore_RevokeUser(t *testing.T) {
+	store := NewMemoryRevocationStore()
+	cutoff := time.Now()
+
+	store.RevokeUser(1, cutoff)
+
+	if revoked, _ := store.IsRevoked(testClaims(1, "old", cutoff.Add(-time.Hour))); !revoked {
+		t.Error("Expected token issued before cutoff to be refused")
+	}
+	if revoked, _ := store.IsRevoked(testClaims(1, "new", cutoff.Add(2*time.Second))); revoked {
+		t.Error("Expected token issued after cutoff to be accepted")
+	}
+	if revoked, _ := store.IsRevoked(testClaims(2, "other", cutoff.Add(-time.Hour))); revoked {
+		t.Error("Expected other users' tokens to be accepted")
+	}
+}
+
+func TestMemoryRevocationStore_Prune(t *testing.T) {
+	store := NewMemoryRevocationStore()
+	now := time.Now()
+
+	store.RevokeToken("expired", 1, now.Add(-time.Minute))
+	store.RevokeToken("live", 1, now.Add(time.Minute))
+	store.RevokeUser(2, now.Add(-accessTokenTTL-time.Minute))
+
+	store.Prune(now)
+
+	if _, ok := store.tokens["expired"]; ok {
+		t.Error("Expected expired jti to be pruned")
+	}
+	if _, ok := store.tokens["live"]; !ok {
+		t.Error("Expected unexpired jti to be kept")
+	}
+	if _, ok := store.users[2]; ok {
+		t.Error("Expected stale user cutoff to be pruned")
+	}
+}
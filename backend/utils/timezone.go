@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+	// the zone names must resolve on hosts without /usr/share/zoneinfo
+	_ "time/tzdata"
+)
+
+// DateLayout is how the API writes calendar days
+const DateLayout = "2006-01-02"
+
+// TimeOfDayLayout is how the API writes a time of day, e.g. 08:30
+const TimeOfDayLayout = "15:04"
+
+// LoadTimeZone resolves an IANA time zone name such as
+// America/Los_Angeles. The empty name is UTC.
+func LoadTimeZone(name string) (*time.Location, error) {
+	switch name {
+	case "", "UTC":
+		return time.UTC, nil
+	case "Local":
+		// the server's zone means nothing to the user
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// DayBounds returns the start of the calendar day date (YYYY-MM-DD) in loc
+// and the start of the next one. Across a DST change the day is 23 or 25
+// hours long.
+func DayBounds(date string, loc *time.Location) (start, end time.Time, err error) {
+	start, err = time.ParseInLocation(DateLayout, date, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, start.AddDate(0, 0, 1), nil
+}
+
+// Today is the current calendar day in loc
+func Today(now time.Time, loc *time.Location) string {
+	return now.In(loc).Format(DateLayout)
+}
+
+// DayRange lists the calendar days from..to (inclusive, YYYY-MM-DD) in loc
+// and their bounds: the start of each day followed by the end of the last.
+// It returns no days when from is after to.
+func DayRange(from, to string, loc *time.Location) (dates []string, bounds []time.Time, err error) {
+	first, err := time.ParseInLocation(DateLayout, from, loc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := time.ParseInLocation(DateLayout, to, loc); err != nil {
+		return nil, nil, err
+	}
+
+	for day := first; day.Format(DateLayout) <= to; day = day.AddDate(0, 0, 1) {
+		dates = append(dates, day.Format(DateLayout))
+		bounds = append(bounds, day)
+	}
+	if len(dates) == 0 {
+		return nil, nil, nil
+	}
+	return dates, append(bounds, bounds[len(bounds)-1].AddDate(0, 0, 1)), nil
+}
+
+// AtTimeOfDay is clock (HH:MM) on the calendar day of t in loc
+func AtTimeOfDay(t time.Time, clock string, loc *time.Location) (time.Time, error) {
+	parsed, err := time.Parse(TimeOfDayLayout, clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), parsed.Hour(), parsed.Minute(), 0, 0, loc), nil
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadTimeZone(t *testing.T) {
+	for _, name := range []string{"", "UTC"} {
+		if loc, err := LoadTimeZone(name); err != nil || loc != time.UTC {
+			t.Errorf("Expected %q to be UTC, got %v, %v", name, loc, err)
+		}
+	}
+	if loc, err := LoadTimeZone("America/Los_Angeles"); err != nil || loc.String() != "America/Los_Angeles" {
+		t.Errorf("Expected America/Los_Angeles, got %v, %v", loc, err)
+	}
+	for _, name := range []string{"Local", "Mars/Olympus_Mons", "../etc/passwd"} {
+		if _, err := LoadTimeZone(name); err == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+	}
+}
+
+func TestDayBounds_DSTTransitions(t *testing.T) {
+	la, _ := LoadTimeZone("America/Los_Angeles")
+
+	tests := []struct {
+		date   string
+		length time.Duration
+		start  string // in UTC
+	}{
+		{"2026-03-07", 24 * time.Hour, "2026-03-07T08:00:00Z"},
+		{"2026-03-08", 23 * time.Hour, "2026-03-08T08:00:00Z"}, // spring forward
+		{"2026-03-09", 24 * time.Hour, "2026-03-09T07:00:00Z"},
+		{"2026-11-01", 25 * time.Hour, "2026-11-01T07:00:00Z"}, // fall back
+		{"2026-11-02", 24 * time.Hour, "2026-11-02T08:00:00Z"},
+	}
+	for _, tt := range tests {
+		start, end, err := DayBounds(tt.date, la)
+		if err != nil {
+			t.Fatalf("DayBounds(%s): %v", tt.date, err)
+		}
+		if got := start.UTC().Format(time.RFC3339); got != tt.start {
+			t.Errorf("%s: expected start %s, got %s", tt.date, tt.start, got)
+		}
+		if got := end.Sub(start); got != tt.length {
+			t.Errorf("%s: expected a %s day, got %s", tt.date, tt.length, got)
+		}
+	}
+}
+
+func TestDayBounds_InvalidDate(t *testing.T) {
+	if _, _, err := DayBounds("03/08/2026", time.UTC); err == nil {
+		t.Error("Expected an error for a date that is not YYYY-MM-DD")
+	}
+}
+
+func TestDayRange_AcrossDST(t *testing.T) {
+	la, _ := LoadTimeZone("America/Los_Angeles")
+
+	dates, bounds, err := DayRange("2026-03-07", "2026-03-09", la)
+	if err != nil {
+		t.Fatalf("DayRange: %v", err)
+	}
+	if len(dates) != 3 || dates[0] != "2026-03-07" || dates[2] != "2026-03-09" || len(bounds) != 4 {
+		t.Fatalf("Expected 3 days and 4 bounds, got %v %v", dates, bounds)
+	}
+	for i, length := range []time.Duration{24 * time.Hour, 23 * time.Hour, 24 * time.Hour} {
+		if got := bounds[i+1].Sub(bounds[i]); got != length {
+			t.Errorf("%s: expected a %s day, got %s", dates[i], length, got)
+		}
+	}
+
+	if dates, _, err := DayRange("2026-03-09", "2026-03-07", la); err != nil || len(dates) != 0 {
+		t.Errorf("Expected no days when from is after to, got %v, %v", dates, err)
+	}
+	if _, _, err := DayRange("2026-03-07", "tomorrow", la); err == nil {
+		t.Error("Expected an error for a date that is not YYYY-MM-DD")
+	}
+}
+
+func TestToday_UsesZone(t *testing.T) {
+	la, _ := LoadTimeZone("America/Los_Angeles")
+	// 8pm in California is already tomorrow in UTC
+	now := time.Date(2026, 6, 2, 3, 0, 0, 0, time.UTC)
+	if got := Today(now, la); got != "2026-06-01" {
+		t.Errorf("Expected 2026-06-01 in Los Angeles, got %s", got)
+	}
+	if got := Today(now, time.UTC); got != "2026-06-02" {
+		t.Errorf("Expected 2026-06-02 in UTC, got %s", got)
+	}
+}
+
+func TestAtTimeOfDay_AcrossDST(t *testing.T) {
+	la, _ := LoadTimeZone("America/Los_Angeles")
+	tests := []struct {
+		now      time.Time
+		expected time.Time
+	}{
+		// the evening of the 7th in California, though the 8th in UTC
+		{time.Date(2026, 3, 8, 5, 0, 0, 0, time.UTC), time.Date(2026, 3, 7, 16, 0, 0, 0, time.UTC)},
+		// clocks go forward on the 8th, so 08:00 is an hour earlier in UTC
+		{time.Date(2026, 3, 8, 20, 0, 0, 0, time.UTC), time.Date(2026, 3, 8, 15, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := AtTimeOfDay(tt.now, "08:00", la)
+		if err != nil || !got.Equal(tt.expected) {
+			t.Errorf("AtTimeOfDay(%v) = %v, %v; want %v", tt.now, got.UTC(), err, tt.expected)
+		}
+	}
+
+	for _, clock := range []string{"8am", "24:00", "08:60", ""} {
+		if _, err := AtTimeOfDay(time.Now(), clock, la); err == nil {
+			t.Errorf("Expected %q to be rejected", clock)
+		}
+	}
+}
+
+func TestCalculateAgeOn_BirthdayStartsAtLocalMidnight(t *testing.T) {
+	la, _ := LoadTimeZone("America/Los_Angeles")
+	dob := time.Date(1990, 6, 2, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 6, 2, 3, 0, 0, 0, time.UTC)
+
+	if age := CalculateAgeOn(&dob, now); age != 36 {
+		t.Errorf("Expected 36 in UTC, got %d", age)
+	}
+	if age := CalculateAgeOn(&dob, now.In(la)); age != 35 {
+		t.Errorf("Expected 35 the evening before in Los Angeles, got %d", age)
+	}
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RFC 6238 parameters. These are what every authenticator app assumes when
+// the provisioning URI does not say otherwise.
+const (
+	totpDigits = 6
+	totpPeriod = 30 * time.Second
+	// codes from one step either side are accepted to allow for clock drift
+	totpSkew = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a random 160-bit secret in base32, the form
+// authenticator apps expect
+func GenerateTOTPSecret() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(buf), nil
+}
+
+// TOTPProvisioningURI returns the otpauth:// URI that is usually shown to
+// the user as a QR code
+func TOTPProvisioningURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// TOTPStep returns the time step a moment falls in
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / int64(totpPeriod.Seconds())
+}
+
+// TOTPCode computes the code for a time step
+func TOTPCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// dynamic truncation, RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000), nil
+}
+
+// ValidateTOTP checks a code against the steps around now and returns the
+// step it matched. Steps at or before lastStep are refused so an observed
+// code cannot be replayed.
+func ValidateTOTP(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := TOTPStep(now)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		expected, err := TOTPCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// GenerateRecoveryCodes returns n one-time codes formatted for reading
+// aloud (xxxxx-xxxxx) and the hashes to store in their place
+func GenerateRecoveryCodes(n int) (codes []string, hashes []string, err error) {
+	const alphabet = "abcdefghjkmnpqrstuvwxyz23456789" // no 0/o or 1/l/i
+	// bytes at or above this are rejected so every letter is equally likely
+	limit := byte(256 - 256%len(alphabet))
+	for i := 0; i < n; i++ {
+		code := make([]byte, 0, 11)
+		for len(code) < 11 {
+			var b [1]byte
+			if _, err := rand.Read(b[:]); err != nil {
+				return nil, nil, err
+			}
+			if b[0] >= limit {
+				continue
+			}
+			if len(code) == 5 {
+				code = append(code, '-')
+			}
+			code = append(code, alphabet[int(b[0])%len(alphabet)])
+		}
+		codes = append(codes, string(code))
+		hashes = append(hashes, HashRecoveryCode(string(code)))
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode normalizes a recovery code as typed by a user and
+// hashes it
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+	return HashToken(normalized)
+}
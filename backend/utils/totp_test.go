@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// base32 of the ASCII secret "12345678901234567890" from RFC 6238
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	// the RFC lists 8-digit codes; ours are their last 6 digits
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, want := range vectors {
+		got, err := TOTPCode(rfcSecret, TOTPStep(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode: %v", err)
+		}
+		if got != want {
+			t.Errorf("At %d expected %s, got %s", unix, want, got)
+		}
+	}
+}
+
+func TestValidateTOTP_SkewAndReplay(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	step := TOTPStep(now)
+
+	previous, _ := TOTPCode(rfcSecret, step-1)
+	if matched, ok := ValidateTOTP(rfcSecret, previous, now, 0); !ok || matched != step-1 {
+		t.Errorf("Expected the previous step to be accepted, got %d %v", matched, ok)
+	}
+
+	stale, _ := TOTPCode(rfcSecret, step-2)
+	if _, ok := ValidateTOTP(rfcSecret, stale, now, 0); ok {
+		t.Error("Expected a code two steps old to be rejected")
+	}
+
+	current, _ := TOTPCode(rfcSecret, step)
+	if _, ok := ValidateTOTP(rfcSecret, current, now, step); ok {
+		t.Error("Expected an already used step to be rejected")
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("Fitness Tracker", "testuser", rfcSecret)
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("Invalid URI %q: %v", uri, err)
+	}
+	if parsed.Scheme != "otpauth" || parsed.Host != "totp" {
+		t.Errorf("Unexpected URI %q", uri)
+	}
+	if parsed.Path != "/Fitness Tracker:testuser" {
+		t.Errorf("Unexpected label %q", parsed.Path)
+	}
+	if parsed.Query().Get("secret") != rfcSecret || parsed.Query().Get("issuer") != "Fitness Tracker" {
+		t.Errorf("Unexpected query %q", parsed.RawQuery)
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, hashes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes: %v", err)
+	}
+	if len(codes) != 10 || len(hashes) != 10 {
+		t.Fatalf("Expected 10 codes, got %d", len(codes))
+	}
+
+	seen := map[string]bool{}
+	for i, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("Unexpected code format %q", code)
+		}
+		if seen[code] {
+			t.Errorf("Duplicate code %q", code)
+		}
+		seen[code] = true
+
+		// typed back in upper case with spaces, it still matches
+		if HashRecoveryCode(" "+strings.ToUpper(code)+" ") != hashes[i] {
+			t.Errorf("Hash of %q does not match", code)
+		}
+	}
+}
@@ -55,7 +55,7 @@ trap cleanup SIGINT SIGTERM
 # Start backend
 echo -e "${GREEN}Starting Go backend on http://localhost:8080...${NC}"
 cd "$SCRIPT_DIR/backend"
-go run main.go > "$SCRIPT_DIR/backend.log" 2>&1 &
+go run . > "$SCRIPT_DIR/backend.log" 2>&1 &
 BACKEND_PID=$!
 cd "$SCRIPT_DIR"
 